@@ -0,0 +1,29 @@
+// Package boot 在当前进程中启动 Agent 服务, 供一体化开发环境与集成测试使用.
+package boot
+
+import (
+	"github.com/godyy/ggs/app/agent/internal/app"
+	"github.com/godyy/ggs/app/agent/internal/base/config"
+	_ "github.com/godyy/ggs/app/agent/internal/infra/agent" // 注册会话处理.
+)
+
+// Config Agent 服务配置.
+type Config = config.Config
+
+// Options 启动选项.
+type Options = app.Options
+
+// LoadConfig 加载配置文件.
+func LoadConfig(path string) (*Config, error) {
+	return config.Load(path)
+}
+
+// Start 启动 Agent 服务, 失败时终止进程.
+func Start(opts *Options) {
+	app.Start(opts)
+}
+
+// Stop 停止 Agent 服务.
+func Stop() {
+	app.Stop()
+}
@@ -3,9 +3,12 @@ package internal
 import (
 	"net"
 	"sync"
+	"time"
 
 	"github.com/godyy/gactor"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
+	"github.com/godyy/ggs/pkg/compress"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	"google.golang.org/protobuf/proto"
 )
 
 // Agent 内部 Agent 接口.
@@ -21,10 +24,32 @@ type Agent interface {
 
 	// Stop Agent 停机.
 	Stop(reason pbc2s.DisconnectPush_Reason)
+
+	// Info 获取会话信息.
+	Info() AgentInfo
+
+	// Push 向客户端推送消息.
+	Push(m proto.Message) error
+
+	// Drain 通知客户端重连其他节点, 客户端断开后会话随之结束.
+	Drain() error
+
+	// HandleActorDisconnect 处理 Actor 断开连接, 玩家迁移中时重新连接至目标节点.
+	HandleActorDisconnect(sid uint32)
+}
+
+// AgentInfo 会话信息.
+type AgentInfo struct {
+	PlayerId    int64     `json:"player_id"`    // 角色ID.
+	SessionId   uint32    `json:"session_id"`   // 会话ID.
+	RemoteAddr  string    `json:"remote_addr"`  // 远端地址, 会话挂起时为最后一次连接的地址.
+	ConnectTime time.Time `json:"connect_time"` // 连接时间.
+	BytesIn     uint64    `json:"bytes_in"`     // 接收的字节数.
+	BytesOut    uint64    `json:"bytes_out"`    // 发送的字节数.
 }
 
 // StartAgent 启动Agent.
-var StartAgent func(conn net.Conn, sessionKey []byte, readInsideIndependentRoutine bool) error
+var StartAgent func(conn net.Conn, sessionKey []byte, compression compress.Algorithm, readInsideIndependentRoutine bool) error
 
 var agents sync.Map
 
@@ -56,6 +81,23 @@ func GetAgentBySessionId(playerId int64, sessionId uint32) Agent {
 	}
 }
 
+// RangeAgents 遍历所有 Agent, f 返回 false 时停止遍历.
+func RangeAgents(f func(a Agent) bool) {
+	agents.Range(func(key, value interface{}) bool {
+		return f(value.(Agent))
+	})
+}
+
+// CountAgents 返回 Agent 数量.
+func CountAgents() int {
+	n := 0
+	agents.Range(func(key, value interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
 // StopAllAgents 停止所有 Agent.
 func StopAllAgents() {
 	agents.Range(func(key, value interface{}) bool {
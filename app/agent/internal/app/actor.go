@@ -8,9 +8,8 @@ import (
 	"github.com/godyy/ggs/app/agent/internal/base/log"
 	"github.com/godyy/ggs/internal/base/consts"
 	"github.com/godyy/ggs/internal/base/logger"
-	"github.com/godyy/ggs/internal/base/nodeutil"
 	iactor "github.com/godyy/ggs/internal/infra/actor"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
 	"github.com/godyy/ggskit/infra/actor"
 	"github.com/godyy/ggskit/infra/cluster"
 	pkgerrors "github.com/pkg/errors"
@@ -27,7 +26,7 @@ func (a *app) startActor() error {
 	// 创建 actor 客户端.
 	clientCfg := &actor.ClientConfig{
 		Core: &gactor.ClientConfig{
-			NodeId:            cluster.MakeNodeID(consts.NodeAgent, nodeutil.MakeServerNodeName(Env().ServerID())),
+			NodeId:            cluster.MakeNodeID(consts.NodeAgent, Env().NodeName()),
 			ActorCategory:     iactor.CategoryPlayer.ActorCategory(),
 			DefRequestTimeout: time.Second * 10,
 			Handler:           a,
@@ -112,5 +111,5 @@ func (a *app) HandleDisconnect(id int64, sid uint32) {
 	if agent == nil {
 		return
 	}
-	agent.Stop(pbc2s.DisconnectPush_SystemError)
+	agent.HandleActorDisconnect(sid)
 }
@@ -0,0 +1,257 @@
+package app
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/godyy/ggs/app/agent/internal"
+	"github.com/godyy/ggs/internal/base/logger"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+)
+
+// adminBasePath 管理接口路径前缀.
+const adminBasePath = "/admin"
+
+// adminEnabled 返回是否启用管理接口.
+func (a *app) adminEnabled() bool {
+	return a.config.Admin.Token != ""
+}
+
+// registerAdminHttp 注册管理接口.
+func (a *app) registerAdminHttp(mux *http.ServeMux) {
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, a.adminAuth(h))
+	}
+
+	handle("GET "+adminBasePath+"/sessions", a.handleAdminListSessions)
+	handle("GET "+adminBasePath+"/sessions/{playerId}", a.handleAdminGetSession)
+	handle("POST "+adminBasePath+"/sessions/{playerId}/kick", a.handleAdminKick)
+	handle("POST "+adminBasePath+"/broadcast", a.handleAdminBroadcast)
+	handle("GET "+adminBasePath+"/drain", a.handleAdminDrainStatus)
+	handle("POST "+adminBasePath+"/drain", a.handleAdminDrain)
+	handle("GET "+adminBasePath+"/records", a.handleAdminListRecords)
+	handle("POST "+adminBasePath+"/records/{playerId}", a.handleAdminStartRecord)
+	handle("DELETE "+adminBasePath+"/records/{playerId}", a.handleAdminStopRecord)
+}
+
+// adminAuth 校验管理令牌, 令牌通过 Authorization: Bearer <token> 请求头传递.
+func (a *app) adminAuth(next http.Handler) http.Handler {
+	token := []byte(a.config.Admin.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), token) != 1 {
+			writeAdminError(w, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleAdminListSessions 列出所有会话.
+func (a *app) handleAdminListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions := make([]internal.AgentInfo, 0)
+	internal.RangeAgents(func(agent internal.Agent) bool {
+		sessions = append(sessions, agent.Info())
+		return true
+	})
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].PlayerId < sessions[j].PlayerId
+	})
+	writeAdminJSON(w, http.StatusOK, sessions)
+}
+
+// handleAdminGetSession 查询玩家会话.
+func (a *app) handleAdminGetSession(w http.ResponseWriter, r *http.Request) {
+	agent, ok := getAdminAgent(w, r)
+	if !ok {
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, agent.Info())
+}
+
+// adminKickReq 踢出玩家请求.
+type adminKickReq struct {
+	Reason string `json:"reason"` // DisconnectPush_Reason 名称, 默认为 Disconnect.
+}
+
+// handleAdminKick 踢出玩家.
+func (a *app) handleAdminKick(w http.ResponseWriter, r *http.Request) {
+	var req adminKickReq
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeAdminError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+			return
+		}
+	}
+
+	reason := pbc2s.DisconnectPush_Disconnect
+	if req.Reason != "" {
+		v, ok := pbc2s.DisconnectPush_Reason_value[req.Reason]
+		if !ok {
+			writeAdminError(w, http.StatusBadRequest, "unknown reason "+req.Reason)
+			return
+		}
+		reason = pbc2s.DisconnectPush_Reason(v)
+	}
+
+	agent, ok := getAdminAgent(w, r)
+	if !ok {
+		return
+	}
+	info := agent.Info()
+	agent.Stop(reason)
+	logger.Get().Infof("admin kick player %d session %d, reason=%s", info.PlayerId, info.SessionId, reason)
+	writeAdminJSON(w, http.StatusOK, info)
+}
+
+// adminBroadcastReq 广播系统推送请求.
+type adminBroadcastReq struct {
+	Content string `json:"content"` // 推送内容.
+}
+
+// adminBroadcastResp 广播系统推送响应.
+type adminBroadcastResp struct {
+	Sent   int `json:"sent"`   // 成功入列的会话数量.
+	Failed int `json:"failed"` // 失败的会话数量.
+}
+
+// handleAdminBroadcast 向所有会话广播系统推送.
+func (a *app) handleAdminBroadcast(w http.ResponseWriter, r *http.Request) {
+	var req adminBroadcastReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+		return
+	}
+	if req.Content == "" {
+		writeAdminError(w, http.StatusBadRequest, "content is empty")
+		return
+	}
+
+	var resp adminBroadcastResp
+	push := &pbc2s.SystemPush{Content: req.Content}
+	internal.RangeAgents(func(agent internal.Agent) bool {
+		if err := agent.Push(push); err != nil {
+			resp.Failed++
+		} else {
+			resp.Sent++
+		}
+		return true
+	})
+	logger.Get().Infof("admin broadcast system push, sent=%d failed=%d", resp.Sent, resp.Failed)
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// adminDrainResp 排空状态响应.
+type adminDrainResp struct {
+	Draining bool `json:"draining"` // 是否排空中.
+	Done     bool `json:"done"`     // 排空是否已完成.
+	Sessions int  `json:"sessions"` // 剩余会话数量.
+}
+
+// handleAdminDrainStatus 查询排空状态.
+func (a *app) handleAdminDrainStatus(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.drainStatus())
+}
+
+// handleAdminDrain 发起排空.
+func (a *app) handleAdminDrain(w http.ResponseWriter, r *http.Request) {
+	logger.Get().Info("admin drain requested")
+	a.drain()
+	writeAdminJSON(w, http.StatusAccepted, a.drainStatus())
+}
+
+// drainStatus 返回排空状态.
+func (a *app) drainStatus() adminDrainResp {
+	resp := adminDrainResp{
+		Draining: a.draining.Load(),
+		Sessions: internal.CountAgents(),
+	}
+	select {
+	case <-a.drainDone:
+		resp.Done = true
+	default:
+	}
+	return resp
+}
+
+// handleAdminListRecords 列出录制中的玩家.
+func (a *app) handleAdminListRecords(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, internal.ListRecords())
+}
+
+// handleAdminStartRecord 开始录制玩家数据包, 玩家无需在线, 之后建立的会话同样被录制.
+func (a *app) handleAdminStartRecord(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := getAdminPlayerId(w, r)
+	if !ok {
+		return
+	}
+	info, err := internal.StartRecord(playerId)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, internal.ErrRecordDisabled) {
+			status = http.StatusBadRequest
+		}
+		writeAdminError(w, status, err.Error())
+		return
+	}
+	logger.Get().Infof("admin start record player %d, path=%s", playerId, info.Path)
+	writeAdminJSON(w, http.StatusOK, info)
+}
+
+// handleAdminStopRecord 停止录制玩家数据包.
+func (a *app) handleAdminStopRecord(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := getAdminPlayerId(w, r)
+	if !ok {
+		return
+	}
+	info, ok := internal.StopRecord(playerId)
+	if !ok {
+		writeAdminError(w, http.StatusNotFound, "player not recording")
+		return
+	}
+	logger.Get().Infof("admin stop record player %d, path=%s packets=%d", playerId, info.Path, info.Packets)
+	writeAdminJSON(w, http.StatusOK, info)
+}
+
+// getAdminPlayerId 解析路径参数 playerId, 失败时写出错误响应.
+func getAdminPlayerId(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	playerId, err := strconv.ParseInt(r.PathValue("playerId"), 10, 64)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, "invalid playerId")
+		return 0, false
+	}
+	return playerId, true
+}
+
+// getAdminAgent 根据路径参数 playerId 获取 Agent, 失败时写出错误响应.
+func getAdminAgent(w http.ResponseWriter, r *http.Request) (internal.Agent, bool) {
+	playerId, ok := getAdminPlayerId(w, r)
+	if !ok {
+		return nil, false
+	}
+	agent := internal.GetAgent(playerId)
+	if agent == nil {
+		writeAdminError(w, http.StatusNotFound, "player not online")
+		return nil, false
+	}
+	return agent, true
+}
+
+// writeAdminJSON 写出 JSON 响应.
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Get().Errorf("write admin response failed, %v", err)
+	}
+}
+
+// writeAdminError 写出错误响应.
+func writeAdminError(w http.ResponseWriter, status int, msg string) {
+	writeAdminJSON(w, status, map[string]string{"error": msg})
+}
@@ -3,22 +3,32 @@ package app
 import (
 	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/godyy/gactor"
 	"github.com/godyy/ggs/app/agent/internal"
 	"github.com/godyy/ggs/app/agent/internal/base/config"
 	"github.com/godyy/ggs/app/agent/internal/base/env"
+	"github.com/godyy/ggs/app/agent/internal/infra/guard"
 	"github.com/godyy/ggs/app/agent/internal/infra/router"
+	"github.com/godyy/ggs/internal/base/appflags"
 	icrypto "github.com/godyy/ggs/internal/base/crypto"
 	applifecycle "github.com/godyy/ggs/internal/base/lifecycle"
 	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	"github.com/godyy/ggs/pkg/compress"
 	"github.com/godyy/ggskit/base/crypto"
 	"github.com/godyy/ggskit/base/db/redis"
-	"github.com/godyy/ggskit/base/flags"
+	"github.com/godyy/ggskit/base/protocol"
 	"github.com/godyy/ggskit/infra/actor"
 	"github.com/godyy/ggskit/infra/cluster"
 	"github.com/godyy/ggskit/infra/noderouter"
 	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 type app struct {
@@ -29,7 +39,10 @@ type app struct {
 	redisClient redis.Client // redis 客户端
 
 	// 对接 c 端
-	listener net.Listener
+	listener       net.Listener
+	listenerClosed atomic.Bool   // 监听是否已关闭.
+	wsServer       *http.Server  // WebSocket 服务
+	certReloader   *certReloader // TLS 证书热加载器
 
 	// cluster.
 	cluster *cluster.Service
@@ -44,35 +57,75 @@ type app struct {
 	// crypto.
 	secretDecryptor crypto.Decryptor
 
+	// compressions 按优先级排列的可协商压缩算法.
+	compressions []compress.Algorithm
+
+	// guard 流量防护, 未启用时为 nil.
+	guard *guard.Guard
+
 	httpServer *http.Server // http 服务
+
+	// 节点元数据与排空.
+	meta      *nodemeta.Publisher
+	metaStop  chan struct{}
+	metaDone  chan struct{}
+	gameMeta  *nodemeta.Watcher // Game 节点元数据, 用于负载均衡.
+	draining  atomic.Bool
+	drainOnce sync.Once
+	drainDone chan struct{}
+}
+
+var (
+	appInst *app
+	hooks   applifecycle.Hooks // 生命周期回调
+)
+
+// RegisterBeforeStart 注册在启动前调用的回调函数.
+func RegisterBeforeStart(cb applifecycle.Callback) {
+	hooks.RegisterBeforeStart(cb)
 }
 
-var appInst *app
+// Options 启动选项.
+type Options struct {
+	// Config 配置, 为空时加载 config-path 指定的配置文件.
+	Config *config.Config
+
+	// ServerID 服务器 ID, 为 0 时使用 env-server-id 与 env-node-index.
+	ServerID int64
+
+	// NodeIndex 同一服务器下的节点序号, 仅在指定 ServerID 时生效.
+	NodeIndex int
+}
 
-// Start 启动应用.
-func Start() {
-	appInst = &app{}
+// Start 启动应用. opts 为空时按命令行参数启动, 须先解析 flags.
+func Start(opts *Options) {
+	if opts == nil {
+		opts = &Options{}
+	}
 
-	// 解析flag
-	flags.Parse()
-	defer flags.Reset()
+	appInst = &app{
+		drainDone: make(chan struct{}),
+	}
 
 	// 加载配置表
-	cfg, err := config.Load(configPath())
-	if err != nil {
-		panic(pkgerrors.WithMessage(err, "load config"))
+	cfg := opts.Config
+	if cfg == nil {
+		var err error
+		if cfg, err = config.Load(appflags.ConfigPath()); err != nil {
+			panic(pkgerrors.WithMessage(err, "load config"))
+		}
 	}
 	appInst.config = cfg
 
 	// 初始化环境变量.
 	appInst.env = env.NewEnv()
-	appInst.env.Init()
+	appInst.env.Init(opts.ServerID, opts.NodeIndex)
 
 	// 初始化日志工具.
 	logger.Init(cfg.Log)
 
 	// 启动前回调.
-	applifecycle.BeforeStart()
+	hooks.BeforeStart()
 
 	// 初始化 redis.
 	redisClient, err := redis.NewClient(cfg.DB.Redis)
@@ -93,17 +146,33 @@ func Start() {
 
 	// 初始化节点路由选择器.
 	appInst.nodeSelector = router.NewNodeSelector(noderouter.NewRendezvousSelector())
+	appInst.nodeSelector.SetBalance(cfg.GameBalance)
+
+	// 初始化流量防护.
+	if err := appInst.initGuard(); err != nil {
+		logger.Get().Fatalf("init guard failed, %v", err)
+	}
 
 	// 启动 cluster.
 	if err := appInst.startCluster(); err != nil {
 		logger.Get().Fatalf("start cluster failed, %v", err)
 	}
 
+	// 发布节点元数据.
+	if err := appInst.startMeta(); err != nil {
+		logger.Get().Fatalf("start node meta failed, %v", err)
+	}
+
 	// 启动对 c 端监听服务.
 	if err := appInst.startListen(); err != nil {
 		logger.Get().Fatalf("start listening failed, %v", err)
 	}
 
+	// 启动对 c 端 WebSocket 监听服务.
+	if err := appInst.startWSListen(); err != nil {
+		logger.Get().Fatalf("start websocket listening failed, %v", err)
+	}
+
 	// 启动 http 服务.
 	appInst.startHttp()
 }
@@ -115,13 +184,20 @@ func Stop() {
 
 	// 停止对 c 端监听服务.
 	appInst.stopListen()
+	appInst.stopWSListen()
 
 	// 停止所有 agent.
 	appInst.stopAllAgents()
 
+	// 停止流量防护.
+	appInst.stopGuard()
+
 	// 停止 Actor 服务.
 	appInst.stopActor()
 
+	// 撤销节点元数据.
+	appInst.stopMeta()
+
 	// 停止 cluster.
 	appInst.stopCluster()
 
@@ -147,6 +223,39 @@ func (a *app) stopAllAgents() {
 	internal.StopAllAgents()
 }
 
+// initGuard 按需初始化流量防护.
+func (a *app) initGuard() error {
+	if a.config.Flood == nil {
+		return nil
+	}
+
+	g, err := guard.New(a.config.Flood, ResolveC2SPid)
+	if err != nil {
+		return err
+	}
+	a.guard = g
+	return nil
+}
+
+// stopGuard 停止流量防护.
+func (a *app) stopGuard() {
+	if a.guard != nil {
+		a.guard.Stop()
+	}
+}
+
+// ResolveC2SPid 根据消息名解析 c2s 消息 PID, 未指定包名时默认为 c2s.
+func ResolveC2SPid(name string) (protocol.PID, bool) {
+	if !strings.Contains(name, ".") {
+		name = "c2s." + name
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return 0, false
+	}
+	return c2s.Registry.GetPid(mt.New().Interface())
+}
+
 // Config 获取配置.
 func Config() *config.Config {
 	return appInst.config
@@ -167,6 +276,11 @@ func ActorRegistry() gactor.ActorRegistry {
 	return appInst.actorRegistry
 }
 
+// Guard 获取流量防护, 未启用时返回 nil.
+func Guard() *guard.Guard {
+	return appInst.guard
+}
+
 // ActorClient 获取 Actor 客户端.
 func ActorClient() *actor.Client {
 	return appInst.actorClient
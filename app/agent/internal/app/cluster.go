@@ -23,6 +23,7 @@ func (a *app) startCluster() error {
 		return errors.New("cluster port not specified")
 	}
 	node := nodeutil.NewServerNode(consts.NodeAgent, Env().ServerID(), fmt.Sprintf("%s:%d", ip, port))
+	node.Name = Env().NodeName()
 	clusterCfg := &cluster.ServiceConfig{
 		Core:           &a.config.Cluster.Core,
 		Self:           node,
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/godyy/ggs/app/agent/internal"
+	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+)
+
+const (
+	defaultDrainTimeout = time.Minute
+	drainCheckInterval  = time.Second
+	drainLogInterval    = 10 * time.Second
+)
+
+// Draining 返回节点是否排空中.
+func Draining() bool {
+	return appInst.draining.Load()
+}
+
+// Drain 发起排空, 返回排空完成通知. 重复调用不会重复发起.
+//
+// 排空时停止接收新连接, 在节点元数据中标记排空以便登录服不再分配该节点,
+// 通知所有客户端重连其他节点, 并等待会话离开, 超过时限后断开剩余会话.
+func Drain() <-chan struct{} {
+	return appInst.drain()
+}
+
+// ListenDrainSignal 监听排空信号 SIGUSR1.
+func ListenDrainSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+	go func() {
+		<-ch
+		logger.Get().Info("receive drain signal")
+		Drain()
+	}()
+}
+
+// drain 发起排空.
+func (a *app) drain() <-chan struct{} {
+	a.drainOnce.Do(func() {
+		a.draining.Store(true)
+		go a.drainLoop()
+	})
+	return a.drainDone
+}
+
+// drainTimeout 返回排空时限.
+func (a *app) drainTimeout() time.Duration {
+	if d := a.config.Drain.Timeout; d > 0 {
+		return d
+	}
+	return defaultDrainTimeout
+}
+
+// drainLoop 排空流程.
+func (a *app) drainLoop() {
+	defer close(a.drainDone)
+
+	timeout := a.drainTimeout()
+	logger.Get().Infof("agent draining, sessions=%d, timeout=%s", internal.CountAgents(), timeout)
+
+	// 停止接收新连接.
+	a.stopListen()
+	a.stopWSListen()
+
+	// 标记排空.
+	if a.meta != nil {
+		if err := a.meta.Update(func(m *nodemeta.Meta) { m.Draining = true }); err != nil {
+			logger.Get().Errorf("mark node meta draining failed, %v", err)
+		}
+	}
+
+	// 通知客户端重连其他节点.
+	internal.RangeAgents(func(agent internal.Agent) bool {
+		if err := agent.Drain(); err != nil {
+			logger.Get().Errorf("drain player %d failed, %v", agent.PlayerId(), err)
+			agent.Stop(pbc2s.DisconnectPush_Reconnect)
+		}
+		return true
+	})
+
+	// 等待会话离开.
+	deadline := time.Now().Add(timeout)
+	lastLog := time.Now()
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		n := internal.CountAgents()
+		if n == 0 {
+			logger.Get().Info("agent drained")
+			return
+		}
+		if time.Now().After(deadline) {
+			logger.Get().Infof("agent drain timeout, disconnect remaining sessions=%d", n)
+			internal.RangeAgents(func(agent internal.Agent) bool {
+				agent.Stop(pbc2s.DisconnectPush_Reconnect)
+				return true
+			})
+			return
+		}
+		if time.Since(lastLog) >= drainLogInterval {
+			logger.Get().Infof("agent draining, remaining sessions=%d", n)
+			lastLog = time.Now()
+		}
+	}
+}
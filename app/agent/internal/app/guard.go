@@ -0,0 +1,60 @@
+package app
+
+import (
+	"net"
+	"sync"
+
+	"github.com/godyy/ggs/app/agent/internal/infra/guard"
+	"github.com/godyy/ggs/internal/base/logger"
+)
+
+// guardedConn 关闭时释放来源 IP 连接计数的连接.
+type guardedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+// Close 关闭连接.
+func (c *guardedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
+
+// acquireConn 检查并登记来自 ip 的连接, 返回释放函数.
+func (a *app) acquireConn(ip string) (func(), error) {
+	if a.guard == nil {
+		return func() {}, nil
+	}
+	if err := a.guard.AcquireConn(ip); err != nil {
+		return nil, err
+	}
+	return func() { a.guard.ReleaseConn(ip) }, nil
+}
+
+// acceptConn 对新连接进行来源 IP 检查, 未通过时关闭连接.
+func (a *app) acceptConn(conn net.Conn) (net.Conn, bool) {
+	if a.guard == nil {
+		return conn, true
+	}
+
+	release, err := a.acquireConn(guard.IPOf(conn.RemoteAddr()))
+	if err != nil {
+		logger.Get().Infof("reject connection, remote=%s, %v", conn.RemoteAddr().String(), err)
+		conn.Close()
+		return nil, false
+	}
+	return &guardedConn{Conn: conn, release: release}, true
+}
+
+// reportKeyExchangeFailure 记录密钥交换失败.
+func (a *app) reportKeyExchangeFailure(conn net.Conn) {
+	if a.guard == nil {
+		return
+	}
+
+	ip := guard.IPOf(conn.RemoteAddr())
+	if a.guard.ReportKeyExchangeFailure(ip) {
+		logger.Get().Infof("ip %s banned for too many key exchange failures", ip)
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 func (a *app) startHttp() {
 	port := a.config.HttpPort
-	if port <= 0 || !a.config.EnablePProf {
+	if port <= 0 || (!a.config.EnablePProf && !a.adminEnabled()) {
 		return
 	}
 
@@ -21,6 +21,9 @@ func (a *app) startHttp() {
 	if a.config.EnablePProf {
 		monitor.RegisterPProfHttp(mux, "")
 	}
+	if a.adminEnabled() {
+		a.registerAdminHttp(mux)
+	}
 
 	a.httpServer = &http.Server{
 		Addr:    ":" + strconv.Itoa(port),
@@ -2,6 +2,7 @@ package app
 
 import (
 	"crypto/rand"
+	"crypto/tls"
 	"fmt"
 	"net"
 
@@ -10,9 +11,13 @@ import (
 	icrypto "github.com/godyy/ggs/internal/base/crypto"
 	"github.com/godyy/ggs/internal/base/logger"
 	inet "github.com/godyy/ggs/internal/base/net"
+	"github.com/godyy/ggs/pkg/compress"
 	pkgerrors "github.com/pkg/errors"
 )
 
+// sessionKeyLen 会话密钥长度.
+const sessionKeyLen = 16
+
 // startListen 启动监听.
 func (a *app) startListen() error {
 	port := a.config.Port
@@ -20,10 +25,21 @@ func (a *app) startListen() error {
 		return pkgerrors.New("port not specified")
 	}
 
+	if err := a.initTLS(); err != nil {
+		return pkgerrors.WithMessage(err, "init tls")
+	}
+
+	if err := a.initCompression(); err != nil {
+		return pkgerrors.WithMessage(err, "init compression")
+	}
+
 	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return pkgerrors.WithMessagef(err, "listening at :%d", port)
 	}
+	if l, err = a.wrapListener(l); err != nil {
+		return err
+	}
 
 	logger.Get().Infof("agent listening at :%d", port)
 	a.listener = l
@@ -32,11 +48,69 @@ func (a *app) startListen() error {
 	return nil
 }
 
-// stopListen 停止监听.
+// stopListen 停止监听, 可重复调用.
 func (a *app) stopListen() {
+	if !a.listenerClosed.CompareAndSwap(false, true) {
+		return
+	}
 	if err := a.listener.Close(); err != nil {
 		logger.Get().Errorf("close listener failed, %v", err)
 	}
+	if a.certReloader != nil {
+		a.certReloader.stop()
+	}
+}
+
+// initTLS 按需初始化 TLS 证书热加载器.
+func (a *app) initTLS() error {
+	cfg := &a.config.TLS
+	if !cfg.Enable {
+		return nil
+	}
+
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile, cfg.ReloadInterval)
+	if err != nil {
+		return err
+	}
+	a.certReloader = reloader
+	return nil
+}
+
+// initCompression 解析可协商的压缩算法.
+func (a *app) initCompression() error {
+	a.compressions = a.compressions[:0]
+	for _, name := range a.config.Compression.Algorithms {
+		algo, err := compress.Parse(name)
+		if err != nil {
+			return err
+		}
+		if algo != compress.None {
+			a.compressions = append(a.compressions, algo)
+		}
+	}
+	return nil
+}
+
+// wrapListener 按配置为监听器添加 PROXY protocol 解析与 TLS.
+// PROXY 头位于 TLS 握手之前, 因此先解析 PROXY 头再进行 TLS 握手.
+func (a *app) wrapListener(l net.Listener) (net.Listener, error) {
+	if a.config.ProxyProtocol.Enable {
+		pl, err := inet.NewProxyProtoListener(l, a.config.ProxyProtocol.TrustedCIDRs, consts.ReadWriteTimeout)
+		if err != nil {
+			l.Close()
+			return nil, pkgerrors.WithMessage(err, "new proxy protocol listener")
+		}
+		l = pl
+	}
+
+	if a.certReloader != nil {
+		l = tls.NewListener(l, &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: a.certReloader.GetCertificate,
+		})
+	}
+
+	return l, nil
 }
 
 // listenLoop 监听循环.
@@ -47,64 +121,77 @@ func (a *app) listenLoop() {
 			break
 		}
 
-		go a.handleConn(conn)
+		go func() {
+			if conn, ok := a.acceptConn(conn); ok {
+				a.handleConn(conn)
+			}
+		}()
 	}
 }
 
 // handleConn 处理连接.
 func (a *app) handleConn(conn net.Conn) {
 	// 交换密钥
-	sessionKey, err := a.exchangeSecretKey(conn)
+	sessionKey, compression, err := a.exchangeSecretKey(conn)
 	if err != nil {
 		logger.Get().Errorf("exchange secret key failed, remote=%s, %v", conn.RemoteAddr().String(), err)
+		a.reportKeyExchangeFailure(conn)
 		conn.Close()
 		return
 	}
 
 	// 启动agent
-	if err := internal.StartAgent(conn, sessionKey, false); err != nil {
+	if err := internal.StartAgent(conn, sessionKey, compression, false); err != nil {
 		logger.Get().Errorf("start agent failed, remote=%s, %v", conn.RemoteAddr().String(), err)
 		conn.Close()
 		return
 	}
 }
 
-// exchangeSecretKey 交换密钥.
-func (a *app) exchangeSecretKey(conn net.Conn) ([]byte, error) {
+// exchangeSecretKey 交换密钥, 同时协商压缩算法.
+func (a *app) exchangeSecretKey(conn net.Conn) ([]byte, compress.Algorithm, error) {
 	// 读取临时密钥
 	encryptedTmpSecret, err := inet.ReadPacket(conn, consts.ReadWriteTimeout)
 	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "read tmp secret")
+		return nil, compress.None, pkgerrors.WithMessage(err, "read tmp secret")
 	}
 
 	// 解密临时密钥
 	tmpSecret, err := a.secretDecryptor.Decrypt(encryptedTmpSecret)
 	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "decrypt tmp secret")
+		return nil, compress.None, pkgerrors.WithMessage(err, "decrypt tmp secret")
 	}
 
+	// 解析压缩算法协商扩展
+	tmpSecret, peerAlgos, offered := compress.ParseOffer(tmpSecret, sessionKeyLen)
+	compression := compress.Negotiate(a.compressions, peerAlgos)
+
 	// 生成会话密钥
-	sessionKey := make([]byte, 16)
+	sessionKey := make([]byte, sessionKeyLen)
 	if _, err := rand.Read(sessionKey); err != nil {
-		return nil, pkgerrors.WithMessage(err, "generate session key")
+		return nil, compress.None, pkgerrors.WithMessage(err, "generate session key")
 	}
 
 	// 利用临时密钥创建会话密钥加密器
 	sessionKeyEncryptor, err := icrypto.CreateAESCrypto(tmpSecret)
 	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "create session key encryptor")
+		return nil, compress.None, pkgerrors.WithMessage(err, "create session key encryptor")
 	}
 
 	// 加密会话密钥
-	encryptedSessionKey, err := sessionKeyEncryptor.Encrypt(sessionKey)
+	plainSessionKey := sessionKey
+	if offered {
+		plainSessionKey = compress.AppendSelection(sessionKey, compression)
+	}
+	encryptedSessionKey, err := sessionKeyEncryptor.Encrypt(plainSessionKey)
 	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "encrypt session key")
+		return nil, compress.None, pkgerrors.WithMessage(err, "encrypt session key")
 	}
 
 	// 发送加密后的会话密钥
 	if err := inet.WritePacket(conn, encryptedSessionKey, consts.ReadWriteTimeout); err != nil {
-		return nil, pkgerrors.WithMessage(err, "write encrypted session key")
+		return nil, compress.None, pkgerrors.WithMessage(err, "write encrypted session key")
 	}
 
-	return sessionKey, nil
+	return sessionKey, compression, nil
 }
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/base/logger"
+	inet "github.com/godyy/ggs/internal/base/net"
+	"github.com/gorilla/websocket"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	// defaultWSPath 默认 WebSocket 路径.
+	defaultWSPath = "/ws"
+
+	// wsReadLimit WebSocket 单帧大小上限, 与 c 端数据包长度上限(含 4 字节长度前缀)保持一致.
+	wsReadLimit = 128*1024 + 4
+)
+
+// startWSListen 启动 WebSocket 监听.
+func (a *app) startWSListen() error {
+	cfg := &a.config.WebSocket
+	if cfg.Port <= 0 {
+		return nil
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = defaultWSPath
+	}
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
+	if err != nil {
+		return pkgerrors.WithMessagef(err, "listening websocket at :%d", cfg.Port)
+	}
+	if l, err = a.wrapListener(l); err != nil {
+		return err
+	}
+
+	upgrader := &websocket.Upgrader{
+		HandshakeTimeout: consts.ReadWriteTimeout,
+		CheckOrigin:      makeWSOriginChecker(cfg.AllowedOrigins),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		// 来源 IP 检查.
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		release, err := a.acquireConn(ip)
+		if err != nil {
+			logger.Get().Infof("reject websocket connection, remote=%s, %v", r.RemoteAddr, err)
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			release()
+			logger.Get().Errorf("upgrade websocket failed, remote=%s, %v", r.RemoteAddr, err)
+			return
+		}
+		ws.SetReadLimit(wsReadLimit)
+		a.handleConn(&guardedConn{Conn: inet.NewWSConn(ws), release: release})
+	})
+
+	a.wsServer = &http.Server{Handler: mux}
+	go func() {
+		logger.Get().Infof("agent websocket listening at :%d%s", cfg.Port, path)
+		if err := a.wsServer.Serve(l); errors.Is(err, http.ErrServerClosed) {
+			logger.Get().Info("websocket server closed.")
+		} else {
+			logger.Get().Errorf("websocket server closed with error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// stopWSListen 停止 WebSocket 监听.
+func (a *app) stopWSListen() {
+	if a.wsServer == nil {
+		return
+	}
+
+	// 已升级的连接由 Agent 负责关闭, 此处仅停止接收新连接.
+	ctx, cancel := context.WithTimeout(context.Background(), consts.ShutdownTimeout)
+	defer cancel()
+	if err := a.wsServer.Shutdown(ctx); err != nil {
+		logger.Get().Errorf("websocket server shutdown with error: %v", err)
+	}
+}
+
+// makeWSOriginChecker 根据允许的 Origin 列表创建检查函数.
+// 列表为空时仅允许同源请求, 包含 "*" 时允许任意来源.
+func makeWSOriginChecker(allowedOrigins []string) func(r *http.Request) bool {
+	if len(allowedOrigins) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			return func(r *http.Request) bool { return true }
+		}
+		allowed[strings.ToLower(strings.TrimRight(origin, "/"))] = struct{}{}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// 非浏览器客户端.
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		_, ok := allowed[strings.ToLower(u.Scheme+"://"+u.Host)]
+		return ok
+	}
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/godyy/ggs/app/agent/internal"
+	"github.com/godyy/ggs/app/agent/internal/infra/router"
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	"github.com/godyy/ggskit/utils"
+)
+
+const (
+	metaUpdateInterval = 5 * time.Second // 节点负载发布间隔.
+	gameLoadInterval   = time.Second     // Game 节点负载同步间隔.
+)
+
+// startMeta 发布节点元数据, 并定期发布会话数量作为负载; 同时监听 Game 节点元数据,
+// 定期将其负载同步至节点路由选择器.
+func (a *app) startMeta() error {
+	metaCfg := nodemeta.ConfigFromCluster(&a.config.Cluster.Core)
+	gameMeta, err := nodemeta.NewWatcher(metaCfg, consts.NodeGame)
+	if err != nil {
+		return err
+	}
+	if err := gameMeta.Start(); err != nil {
+		return err
+	}
+	a.gameMeta = gameMeta
+
+	publisher, err := nodemeta.NewPublisher(metaCfg, nodemeta.Meta{
+		Category: consts.NodeAgent,
+		Name:     Env().NodeName(),
+		ServerId: Env().ServerID(),
+		Addr:     a.publicAddr(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := publisher.Start(); err != nil {
+		a.gameMeta.Stop()
+		return err
+	}
+	a.meta = publisher
+	a.metaStop = make(chan struct{})
+	a.metaDone = make(chan struct{})
+	go a.metaLoop()
+	return nil
+}
+
+// stopMeta 撤销节点元数据.
+func (a *app) stopMeta() {
+	if a.meta != nil {
+		close(a.metaStop)
+		<-a.metaDone
+		a.meta.Stop()
+		a.gameMeta.Stop()
+	}
+}
+
+// publicAddr 返回对客户端公布的连接地址.
+func (a *app) publicAddr() string {
+	if a.config.PublicAddr != "" {
+		return a.config.PublicAddr
+	}
+	return fmt.Sprintf("%s:%d", utils.ResolveLocalIPv4(), a.config.Port)
+}
+
+// metaLoop 定期发布自身负载, 并同步 Game 节点负载与托管的服务器.
+func (a *app) metaLoop() {
+	defer close(a.metaDone)
+
+	publishTicker := time.NewTicker(metaUpdateInterval)
+	defer publishTicker.Stop()
+	gameTicker := time.NewTicker(gameLoadInterval)
+	defer gameTicker.Stop()
+	a.syncGameMeta()
+	for {
+		select {
+		case <-a.metaStop:
+			return
+		case <-publishTicker.C:
+			a.publishLoad()
+		case <-gameTicker.C:
+			a.syncGameMeta()
+		}
+	}
+}
+
+// publishLoad 发布会话数量作为负载. 负载未变化时同样发布, 以便订阅方据更新时间识别失联的节点.
+func (a *app) publishLoad() {
+	load := int64(internal.CountAgents())
+	if err := a.meta.Update(func(m *nodemeta.Meta) { m.Load = load }); err != nil {
+		logger.Get().Errorf("update node meta load failed, %v", err)
+	}
+}
+
+// syncGameMeta 将 Game 节点负载与托管的服务器同步至节点路由选择器.
+func (a *app) syncGameMeta() {
+	loads := make(map[string]router.NodeLoad)
+	servers := make(map[string][]int64)
+	a.gameMeta.Range(func(m *nodemeta.Meta) bool {
+		if len(m.ServerIds) > 0 {
+			servers[m.NodeId()] = m.ServerIds
+		}
+		loads[m.NodeId()] = router.NodeLoad{
+			Players:  m.Load,
+			CPU:      m.CPU,
+			Draining: m.Draining,
+			UpdateAt: m.UpdateAt,
+		}
+		return true
+	})
+	a.nodeSelector.SetNodeServers(servers)
+	a.nodeSelector.SetLoads(loads)
+}
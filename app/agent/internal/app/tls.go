@@ -0,0 +1,118 @@
+package app
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/logger"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// defaultCertReloadInterval 默认证书文件检查间隔.
+const defaultCertReloadInterval = time.Minute
+
+// certReloader 证书热加载器, 定期检查证书文件, 发生变更时重新加载.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mtx     sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time // 已加载证书文件的最后修改时间.
+
+	chStop chan struct{}
+}
+
+// newCertReloader 创建证书热加载器并加载证书.
+func newCertReloader(certFile, keyFile string, interval time.Duration) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		chStop:   make(chan struct{}),
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	if interval <= 0 {
+		interval = defaultCertReloadInterval
+	}
+	go r.loop(interval)
+
+	return r, nil
+}
+
+// GetCertificate 返回当前证书, 用于 tls.Config.GetCertificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.cert, nil
+}
+
+// stop 停止检查.
+func (r *certReloader) stop() {
+	close(r.chStop)
+}
+
+// loop 检查循环.
+func (r *certReloader) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if reloaded, err := r.reload(); err != nil {
+				logger.Get().Errorf("reload tls certificate failed, %v", err)
+			} else if reloaded {
+				logger.Get().Infof("tls certificate reloaded, cert=%s", r.certFile)
+			}
+		case <-r.chStop:
+			return
+		}
+	}
+}
+
+// reload 证书文件发生变更时重新加载, 返回是否重新加载.
+func (r *certReloader) reload() (bool, error) {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return false, err
+	}
+
+	r.mtx.RLock()
+	unchanged := r.cert != nil && !modTime.After(r.modTime)
+	r.mtx.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, pkgerrors.WithMessage(err, "load x509 key pair")
+	}
+
+	r.mtx.Lock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.mtx.Unlock()
+	return true, nil
+}
+
+// latestModTime 返回证书文件与密钥文件中较晚的修改时间.
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, pkgerrors.WithMessagef(err, "stat %s", file)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
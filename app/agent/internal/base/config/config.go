@@ -1,6 +1,11 @@
 package config
 
 import (
+	"time"
+
+	"github.com/godyy/ggs/app/agent/internal/infra/guard"
+	"github.com/godyy/ggs/app/agent/internal/infra/router"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/fragment"
 	"github.com/godyy/ggskit/base/config"
 	"github.com/godyy/ggskit/base/db/redis"
 	"github.com/godyy/ggskit/base/logger"
@@ -13,6 +18,79 @@ type Config struct {
 	// Port 服务端口.
 	Port int
 
+	// PublicAddr 对客户端公布的连接地址, 由登录服下发给客户端, 默认为本机 IP 与 Port.
+	PublicAddr string
+
+	// TLS 对 c 端监听的 TLS 配置, 同时作用于 TCP 与 WebSocket 监听.
+	TLS struct {
+		// Enable 是否启用 TLS.
+		Enable bool
+
+		// CertFile 证书文件路径.
+		CertFile string
+
+		// KeyFile 私钥文件路径.
+		KeyFile string
+
+		// ReloadInterval 证书文件变更检查间隔, 默认 1 分钟.
+		ReloadInterval time.Duration
+	}
+
+	// ProxyProtocol 对 c 端监听的 PROXY protocol 配置, 同时作用于 TCP 与 WebSocket 监听.
+	ProxyProtocol struct {
+		// Enable 是否解析 PROXY protocol v1/v2 头.
+		Enable bool
+
+		// TrustedCIDRs 允许携带 PROXY 头的来源网段(如负载均衡器), 启用时不能为空.
+		TrustedCIDRs []string
+	}
+
+	// WebSocket WebSocket 监听配置.
+	WebSocket struct {
+		// Port 监听端口, 0 表示不启用.
+		Port int
+
+		// Path 请求路径, 默认为 /ws.
+		Path string
+
+		// AllowedOrigins 允许的 Origin 列表, 为空时仅允许同源请求, "*" 表示允许任意来源.
+		AllowedOrigins []string
+	}
+
+	// Compression 数据包压缩配置, 在密钥交换时与客户端协商.
+	Compression struct {
+		// Algorithms 按优先级排列的压缩算法(flate/zstd), 为空表示不压缩.
+		Algorithms []string
+
+		// Threshold 压缩阈值(字节), 长度不小于该值的数据包才进行压缩, 默认 1024.
+		Threshold int
+	}
+
+	// Fragment 超长数据包分片配置.
+	Fragment struct {
+		// MaxMessageLen 分片重组后数据包最大长度(字节), 默认 1MiB.
+		// 重组后的数据包经集群转发, 不能超过 Cluster.Core.Session.MaxPacketLength.
+		MaxMessageLen int
+
+		// MaxPartials 每个连接同时重组中的消息数量上限, 默认 4.
+		MaxPartials int
+	}
+
+	// Outbound 会话下行队列配置.
+	Outbound struct {
+		// MaxBytes 下行队列积压字节数上限, 超过时断开连接, 默认 4MiB.
+		MaxBytes int
+
+		// DropBytes 下行队列积压字节数超过该值时丢弃 Droppable 中的推送, 默认为 MaxBytes 的一半.
+		DropBytes int
+
+		// Droppable 积压时可丢弃的推送消息名称, 未指定包名时默认为 c2s.
+		Droppable []string
+
+		// Latest 仅需保留最新一条的推送消息名称, 尚未发送的旧推送被新推送替换.
+		Latest []string
+	}
+
 	// Cluster 集群配置.
 	Cluster struct {
 		// Port 集群端口.
@@ -22,6 +100,9 @@ type Config struct {
 		Core cluster.Config
 	}
 
+	// GameBalance Game 节点有界负载选择配置.
+	GameBalance router.Balance
+
 	// DB 数据库配置.
 	DB struct {
 		// Redis 配置.
@@ -37,8 +118,41 @@ type Config struct {
 	// EnablePProf 是否启用pprof.
 	EnablePProf bool
 
+	// Admin 管理接口配置, 管理接口挂载在 HttpPort 上.
+	Admin struct {
+		// Token 管理令牌, 为空表示不启用管理接口.
+		Token string
+	}
+
 	// Log 日志配置
 	Log *logger.Config
+
+	// Flood 流量防护配置, 为空表示不启用.
+	Flood *guard.Config
+
+	// Drain 排空配置.
+	Drain struct {
+		// Timeout 等待会话离开的时限, 超时后断开剩余会话, 默认 1 分钟.
+		Timeout time.Duration
+	}
+
+	// Record 玩家数据包录制配置, 通过管理接口开启指定玩家的录制.
+	Record struct {
+		// Dir 录制文件目录, 为空表示不启用录制.
+		Dir string
+	}
+
+	// Resume 会话恢复配置.
+	Resume struct {
+		// Enable 是否启用会话恢复.
+		Enable bool
+
+		// GracePeriod 连接断开后会话的保留时长.
+		GracePeriod time.Duration
+
+		// ReplayBufferSize 下行数据包重放缓冲区容量(数据包数量).
+		ReplayBufferSize int
+	}
 }
 
 // Load 从指定路径加载配置文件.
@@ -49,5 +163,21 @@ func Load(configPath string) (*Config, error) {
 		return nil, pkgerrors.WithMessage(err, "load file")
 	}
 
+	if err := cfg.check(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+// check 检查配置.
+func (c *Config) check() error {
+	maxMessageLen := c.Fragment.MaxMessageLen
+	if maxMessageLen <= 0 {
+		maxMessageLen = fragment.DefaultMaxMessageLen
+	}
+	if maxPacketLen := int(c.Cluster.Core.Session.MaxPacketLength); maxPacketLen > 0 && maxMessageLen > maxPacketLen {
+		return pkgerrors.Errorf("Fragment.MaxMessageLen %d exceeds Cluster.Core.Session.MaxPacketLength %d", maxMessageLen, maxPacketLen)
+	}
+	return nil
+}
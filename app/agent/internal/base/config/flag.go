@@ -1,15 +1,14 @@
 package config
 
 import (
+	_ "github.com/godyy/ggs/internal/base/appflags" // 注册共用参数 cluster-port, http-port, enable-pprof.
 	"github.com/godyy/ggskit/base/config"
 )
 
 func init() {
 	config.AddFlag("token-key-path", "", "token key path")
 	config.AddFlag("port", 0, "service port, must > 0")
-	config.AddFlag("cluster-port", 0, "cluster port, must > 0")
-	config.AddFlag("http-port", 0, "http port, 0 means disable http server")
-	config.AddFlag("enable-pprof", false, "enable pprof")
+	config.AddFlag("ws-port", 0, "websocket port, 0 means disable websocket listener")
 }
 
 func (c *Config) ApplyFlags() error {
@@ -19,6 +18,9 @@ func (c *Config) ApplyFlags() error {
 	if port, ok := config.GetFlagValue[int]("port"); ok && port > 0 {
 		c.Port = port
 	}
+	if wsPort, ok := config.GetFlagValue[int]("ws-port"); ok && wsPort > 0 {
+		c.WebSocket.Port = wsPort
+	}
 	if clusterPort, ok := config.GetFlagValue[int]("cluster-port"); ok && clusterPort > 0 {
 		c.Cluster.Port = clusterPort
 	}
@@ -1,12 +1,16 @@
 package env
 
-import baseenv "github.com/godyy/ggskit/base/env"
+import (
+	"github.com/godyy/ggs/internal/base/nodeutil"
+	baseenv "github.com/godyy/ggskit/base/env"
+)
 
 // Env 环境变量管理器.
 type Env struct {
 	baseenv.Env
 
-	serverId int64 // 服务器 ID
+	serverId  int64 // 服务器 ID
+	nodeIndex int   // 同一服务器下的节点序号
 }
 
 // NewEnv 创建环境变量管理器.
@@ -16,8 +20,13 @@ func NewEnv() *Env {
 	}
 }
 
-// Init 初始化环境变量.
-func (e *Env) Init() {
+// Init 初始化环境变量, serverId 为 0 时使用 env-server-id 与 env-node-index.
+func (e *Env) Init(serverId int64, nodeIndex int) {
+	if serverId > 0 {
+		e.serverId = serverId
+		e.nodeIndex = nodeIndex
+		return
+	}
 	e.applyFlags()
 }
 
@@ -25,3 +34,13 @@ func (e *Env) Init() {
 func (e *Env) ServerID() int64 {
 	return e.serverId
 }
+
+// NodeIndex 返回同一服务器下的节点序号.
+func (e *Env) NodeIndex() int {
+	return e.nodeIndex
+}
+
+// NodeName 返回集群节点名.
+func (e *Env) NodeName() string {
+	return nodeutil.MakeIndexedServerNodeName(e.serverId, e.nodeIndex)
+}
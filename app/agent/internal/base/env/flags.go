@@ -1,16 +1,20 @@
 package env
 
-import baseenv "github.com/godyy/ggskit/base/env"
+import (
+	"github.com/godyy/ggs/internal/base/appflags"
+)
 
 func (e *Env) applyFlags() {
-	serverId, ok := baseenv.GetFlagValue[int64]("server-id")
+	serverId, ok := appflags.ServerID()
 	if !ok || serverId <= 0 {
 		panic("env: env-server-id is required and must > 0")
 	}
 
-	e.serverId = serverId
-}
+	nodeIndex, _ := appflags.NodeIndex()
+	if nodeIndex < 0 {
+		panic("env: env-node-index must >= 0")
+	}
 
-func init() {
-	baseenv.AddFlag("server-id", int64(0), "server id")
+	e.serverId = serverId
+	e.nodeIndex = nodeIndex
 }
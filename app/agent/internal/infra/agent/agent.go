@@ -5,52 +5,76 @@ import (
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/godyy/gactor"
 	"github.com/godyy/ggs/app/agent/internal"
+	"github.com/godyy/ggs/app/agent/internal/app"
 	"github.com/godyy/ggs/app/agent/internal/base/log"
-	"github.com/godyy/ggs/internal/base/crypto"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
+	"github.com/godyy/ggs/app/agent/internal/infra/guard"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/record"
 	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	"github.com/godyy/ggs/pkg/compress"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
 	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
-	"github.com/godyy/ggskit/base/crypto/aes"
-	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 )
 
 // ErrStopped 代理已停止错误.
 var ErrStopped = errors.New("agent stopped")
 
-// maxPendingPackets
-const maxPendingPackets = 10
+// ErrDetached 会话挂起中, 没有可用连接.
+var ErrDetached = errors.New("agent detached")
 
 // Agent 用户代理.
 type Agent struct {
-	conn   net.Conn    // 网络连接.
-	crypto aes.Cryptor // 密码工具.
-
-	mtx              sync.RWMutex                // 互斥锁.
-	playerId         int64                       // 角色ID.
-	sessionId        uint32                      // 会话ID.
-	chPendingPackets chan gactor.Buffer          // 待处理的上游数据包.
-	stopFlag         int32                       // 停机标志.
-	stopReason       pbc2s.DisconnectPush_Reason // 停机原因.
-	chStop           chan struct{}               // 用于提供停止信号.
+	mtx         sync.RWMutex                // 互斥锁.
+	stream      *stream                     // 客户端连接, 会话挂起时为 nil.
+	remoteAddr  net.Addr                    // 远端地址.
+	playerId    int64                       // 角色ID.
+	sessionId   atomic.Uint32               // 会话ID, 迁移后更新.
+	outbound    *outboundQueue              // 待处理的上游数据包队列.
+	stopFlag    int32                       // 停机标志.
+	stopReason  pbc2s.DisconnectPush_Reason // 停机原因.
+	actorStop   atomic.Int32                // Player 停止前告知的断开原因, 未告知时为 0.
+	chStop      chan struct{}               // 用于提供停止信号.
+	limiter     *guard.SessionLimiter       // 会话级流量限制, 未启用时为 nil.
+	connectTime time.Time                   // 连接时间.
+	traffic     traffic                     // 流量统计.
+
+	writeMtx     sync.Mutex       // 串行化下行写出, 保证重放编号与写出顺序一致.
+	resumeTicket string           // 会话恢复票据, 为空表示会话不可恢复.
+	replayBuf    *replayBuffer    // 下行数据包重放缓冲区, 由 writeMtx 保护.
+	chResume     chan *resumeConn // 会话恢复连接.
+	chDetach     chan struct{}    // 会话挂起期间有效, 重新附着连接时关闭.
+
+	loginReq  *pbc2s.LoginCharacterReq // 登录角色请求, 迁移后用于在目标节点重新登录.
+	migration *migration               // 进行中的玩家迁移.
 }
 
 // NewAgent 创建Agent.
-func NewAgent(conn net.Conn, secretKey []byte) (*Agent, error) {
-	crypto, err := crypto.CreateAESCrypto(secretKey)
+func NewAgent(conn net.Conn, secretKey []byte, compression compress.Algorithm) (*Agent, error) {
+	a := &Agent{
+		remoteAddr:  conn.RemoteAddr(),
+		outbound:    newOutboundQueue(),
+		chStop:      make(chan struct{}),
+		chResume:    make(chan *resumeConn),
+		connectTime: time.Now(),
+	}
+
+	s, err := newStream(conn, secretKey, compression, &a.traffic)
 	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "create crypto failed")
+		return nil, err
 	}
+	a.stream = s
 
-	return &Agent{
-		conn:             conn,
-		crypto:           crypto,
-		chPendingPackets: make(chan gactor.Buffer, maxPendingPackets),
-		chStop:           make(chan struct{}),
-	}, nil
+	if g := app.Guard(); g != nil {
+		a.limiter = g.NewSessionLimiter()
+		s.limiter = a.limiter
+	}
+
+	return a, nil
 }
 
 // PlayerId Agent 关联的 PlayerId.
@@ -60,26 +84,84 @@ func (a *Agent) PlayerId() int64 {
 
 // SessionId Agent 与 Player 建立会话使用的 SessionId.
 func (a *Agent) SessionId() uint32 {
-	return a.sessionId
+	return a.sessionId.Load()
+}
+
+// RemoteAddr 返回当前连接的远端地址.
+func (a *Agent) RemoteAddr() net.Addr {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.remoteAddr
+}
+
+// getStream 返回当前连接, 会话挂起时返回 nil.
+func (a *Agent) getStream() *stream {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.stream
+}
+
+// Info 获取会话信息.
+func (a *Agent) Info() internal.AgentInfo {
+	info := internal.AgentInfo{
+		PlayerId:    a.playerId,
+		SessionId:   a.sessionId.Load(),
+		ConnectTime: a.connectTime,
+		BytesIn:     a.traffic.bytesIn.Load(),
+		BytesOut:    a.traffic.bytesOut.Load(),
+	}
+	if addr := a.RemoteAddr(); addr != nil {
+		info.RemoteAddr = addr.String()
+	}
+	return info
+}
+
+// Push 向客户端推送消息. 推送经由下行队列发送, 与 Player 的推送保持顺序.
+func (a *Agent) Push(m proto.Message) error {
+	p, err := codecc2s.EncodePacket(c2s.Registry, codecc2s.PtPush, 0, m)
+	if err != nil {
+		return err
+	}
+	return a.ReceivePacket(bytesBuffer(p))
+}
+
+// Drain 通知客户端重连其他节点. 会话不再可恢复, 客户端断开后会话随之结束.
+func (a *Agent) Drain() error {
+	a.delResumeTicket()
+	if a.getStream() == nil {
+		// 会话挂起中, 直接停止.
+		a.stop(pbc2s.DisconnectPush_Reconnect)
+		return nil
+	}
+	return a.Push(&pbc2s.DisconnectPush{Reason: pbc2s.DisconnectPush_Reconnect})
+}
+
+// bytesBuffer 以字节切片实现 gactor.Buffer.
+type bytesBuffer []byte
+
+// UnreadData 返回未读数据.
+func (b bytesBuffer) UnreadData() []byte {
+	return b
 }
 
 // isConnected 返回是否已与 Actor 之间建立连接.
 func (a *Agent) isConnected() bool {
-	return a.playerId != 0 && a.sessionId != 0
+	return a.playerId != 0 && a.sessionId.Load() != 0
 }
 
 // Start 启动Agent.
 func (a *Agent) Start(readInsideIndependentRoutine bool) {
 	go a.pendingPacketLoop()
+	s := a.getStream()
 	if readInsideIndependentRoutine {
-		go a.readLoop()
+		go a.readLoop(s)
 	} else {
-		a.readLoop()
+		a.readLoop(s)
 	}
 }
 
 // readLoop 读取循环.
-func (a *Agent) readLoop() {
+func (a *Agent) readLoop(s *stream) {
 read_loop:
 	for {
 		select {
@@ -87,10 +169,23 @@ read_loop:
 			break read_loop
 		default:
 			// 读取下游数据包
-			p, err := packetReadWriter.ReadAndDecryptPacket(a.conn, a.crypto)
+			p, err := s.readPacket()
+			if errors.Is(err, errRateLimited) {
+				a.rateLimited()
+				break read_loop
+			}
 			if err != nil {
-				a.errorFields("[readLoop] read packet field", log.FldError(err))
-				a.stop(pbc2s.DisconnectPush_SystemError)
+				if !a.detach(s, err) {
+					a.errorFields("[readLoop] read packet field", log.FldError(err))
+					a.stop(pbc2s.DisconnectPush_SystemError)
+				}
+				break read_loop
+			}
+			a.record(record.DirC2S, p)
+
+			// 流量限制, 字节数已在读取分片时计费.
+			if a.limiter != nil && !a.limiter.Allow(codecc2s.HeadGetPid(p)) {
+				a.rateLimited()
 				break read_loop
 			}
 
@@ -112,6 +207,11 @@ read_loop:
 				continue
 			}
 
+			// 迁移中, 暂缓转发.
+			if !a.waitMigration() {
+				break read_loop
+			}
+
 			// forward.
 			if !a.isConnected() {
 				// 未连接
@@ -119,7 +219,7 @@ read_loop:
 				a.stop(pbc2s.DisconnectPush_SystemError)
 				break read_loop
 			}
-			if err := forwardPacket2Player(a.playerId, a.sessionId, p); err != nil {
+			if err := forwardPacket2Player(a.playerId, a.sessionId.Load(), p); err != nil {
 				a.errorFields("[readLoop] forward packet to actor failed", log.FldError(err))
 				a.stop(pbc2s.DisconnectPush_SystemError)
 				break read_loop
@@ -133,25 +233,13 @@ func (a *Agent) pendingPacketLoop() {
 write_loop:
 	for {
 		select {
-		case p := <-a.chPendingPackets:
-			unread := p.UnreadData()
-
-			// hook
-			if hook, err := a.handleHookMsg(unread); err != nil {
-				pid := codecc2s.HeadGetPid(unread)
-				a.errorFields("[pendingPacketLoop] handle hook message failed", log.FldPid(pid), log.FldError(err))
-				a.pendingPacketLoopStop(true, pbc2s.DisconnectPush_SystemError, true)
+		case <-a.outbound.notify:
+			if !a.flushOutbound() {
 				break write_loop
-			} else if hook {
-				continue
 			}
 
-			// forward
-			if err := packetReadWriter.EncryptAndWritePacket(a.conn, unread, a.crypto); err != nil {
-				a.errorFields("[pendingPacketLoop] write packet field", log.FldError(err))
-				a.pendingPacketLoopStop(true, pbc2s.DisconnectPush_SystemError, false)
-				break write_loop
-			}
+		case rc := <-a.chResume:
+			a.handleResume(rc)
 
 		case <-a.chStop:
 			a.pendingPacketLoopStop(false, pbc2s.DisconnectPush_Unknown, true)
@@ -160,17 +248,82 @@ write_loop:
 	}
 }
 
+// flushOutbound 处理下行队列中的全部数据包, 返回 false 表示 pendingPacketLoop 需要退出.
+func (a *Agent) flushOutbound() bool {
+	for {
+		p, ok := a.outbound.pop()
+		if !ok {
+			return true
+		}
+		unread := p.UnreadData()
+
+		// hook
+		if hook, err := a.handleHookMsg(unread); err != nil {
+			pid := codecc2s.HeadGetPid(unread)
+			a.errorFields("[pendingPacketLoop] handle hook message failed", log.FldPid(pid), log.FldError(err))
+			a.pendingPacketLoopStop(true, pbc2s.DisconnectPush_SystemError, true)
+			return false
+		} else if hook {
+			continue
+		}
+
+		// 丢弃会话挂起期间代发心跳的响应.
+		if isKeepaliveResp(unread) {
+			continue
+		}
+
+		// forward
+		if s, err := a.writePacket(unread); err != nil {
+			if a.detach(s, err) {
+				continue
+			}
+			a.errorFields("[pendingPacketLoop] write packet field", log.FldError(err))
+			a.pendingPacketLoopStop(true, pbc2s.DisconnectPush_SystemError, false)
+			return false
+		}
+	}
+}
+
 // pendingPacketLoopStop 上游数据包处理循环停止逻辑.
 func (a *Agent) pendingPacketLoopStop(active bool, reason pbc2s.DisconnectPush_Reason, notifyDisconnect bool) {
 	if active {
 		a.stop(reason)
 	}
 
+	s := a.getStream()
+	if s == nil {
+		// 会话挂起中或连接已移交.
+		return
+	}
+
 	if notifyDisconnect && a.stopReason != pbc2s.DisconnectPush_Unknown {
 		a.pushDisconnect(a.stopReason)
 	}
 
-	a.conn.Close()
+	s.close()
+}
+
+// writePacket 写出下游数据包. 启用会话恢复时数据包同时进入重放缓冲区,
+// 会话挂起期间仅进入重放缓冲区. 返回写出时使用的连接.
+func (a *Agent) writePacket(p []byte) (*stream, error) {
+	a.writeMtx.Lock()
+	defer a.writeMtx.Unlock()
+	return a.writePacketLocked(p)
+}
+
+// writePacketLocked 同 writePacket, 须持有 writeMtx.
+func (a *Agent) writePacketLocked(p []byte) (*stream, error) {
+	a.record(record.DirS2C, p)
+	if a.replayBuf != nil {
+		a.replayBuf.push(p)
+	}
+
+	s := a.getStream()
+	if s == nil {
+		return nil, nil
+	}
+
+	return s, s.writePacket(p)
 }
 
 // handleHookMsg 处理钩子消息.
@@ -205,13 +358,22 @@ func (a *Agent) ReceivePacket(p gactor.Buffer) error {
 		return ErrPacketPt
 	}
 
-	// 数据包入列
-	select {
-	case a.chPendingPackets <- p:
-		return nil
-	case <-a.chStop:
+	if atomic.LoadInt32(&a.stopFlag) != 0 {
 		return ErrStopped
 	}
+
+	// 迁移与停止相关的数据包不入列.
+	if handled, err := a.handleMigratePacket(unread); err != nil || handled {
+		return err
+	}
+
+	// 数据包入列, 积压过多时断开连接.
+	if err := a.outbound.push(p); err != nil {
+		a.infoFields("outbound queue overflow, disconnect slow client")
+		a.stop(pbc2s.DisconnectPush_SlowClient)
+		return err
+	}
+	return nil
 }
 
 // Stop Agent 停机.
@@ -228,24 +390,27 @@ func (a *Agent) stop(reason pbc2s.DisconnectPush_Reason) {
 
 	close(a.chStop)
 	a.stopReason = reason
+	a.delResumeTicket()
 	if a.isConnected() {
 		internal.DelAgent(a)
 		a.disconnectPlayer()
 	}
 }
 
-// sendMessage 发送消息.
+// sendMessage 发送消息. 与 Player 的下行数据包一样计入重放编号, 会话挂起时返回 ErrDetached.
 func (a *Agent) sendMessage(pt int8, seq uint32, m proto.Message) error {
 	p, err := codecc2s.EncodePacket(c2s.Registry, pt, seq, m)
 	if err != nil {
 		return err
 	}
 
-	if err := packetReadWriter.EncryptAndWritePacket(a.conn, p, a.crypto); err != nil {
-		return err
+	a.writeMtx.Lock()
+	defer a.writeMtx.Unlock()
+	if a.getStream() == nil {
+		return ErrDetached
 	}
-
-	return nil
+	_, err = a.writePacketLocked(p)
+	return err
 }
 
 // sendRespMessage 发送响应消息.
@@ -259,7 +424,7 @@ func (a *Agent) forwardReq2Player(seq uint32, m proto.Message) error {
 	if err != nil {
 		return err
 	}
-	if err := forwardPacket2Player(a.playerId, a.sessionId, p); err != nil {
+	if err := forwardPacket2Player(a.playerId, a.sessionId.Load(), p); err != nil {
 		return err
 	}
 	return nil
@@ -267,11 +432,22 @@ func (a *Agent) forwardReq2Player(seq uint32, m proto.Message) error {
 
 // disconnectPlayer 断开与 Player 之间的连接.
 func (a *Agent) disconnectPlayer() {
-	if err := disconnectPlayer(a.playerId, a.sessionId); err != nil {
+	if err := disconnectPlayer(a.playerId, a.sessionId.Load()); err != nil {
 		a.errorFields("disconnect player failed", log.FldError(err))
 	}
 }
 
+// rateLimited 触发流量限制, 断开连接, 来源 IP 下的会话违规过于频繁时封禁该 IP.
+func (a *Agent) rateLimited() {
+	ip := guard.IPOf(a.RemoteAddr())
+	if app.Guard().ReportSessionViolation(ip) {
+		a.infoFields("[readLoop] rate limited, ip banned", zap.String("ip", ip))
+	} else {
+		a.infoFields("[readLoop] rate limited")
+	}
+	a.stop(pbc2s.DisconnectPush_RateLimited)
+}
+
 // pushDisconnect 推送断开连接消息.
 func (a *Agent) pushDisconnect(reason pbc2s.DisconnectPush_Reason) {
 	if err := a.sendMessage(codecc2s.PtPush, 0, &pbc2s.DisconnectPush{Reason: reason}); err != nil {
@@ -3,8 +3,8 @@ package agent
 import (
 	"reflect"
 
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
 	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
 	"github.com/godyy/ggskit/base/protocol"
 	pkgerrors "github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
@@ -14,10 +14,20 @@ type msgHook func(a *Agent, p []byte, msg proto.Message)
 
 var msgHooks map[protocol.PID]msgHook
 
+var (
+	migratePushPid protocol.PID // PlayerMigratePush 的协议ID.
+	stopPushPid    protocol.PID // PlayerStopPush 的协议ID.
+)
+
 func init() {
 	msgHooks = make(map[protocol.PID]msgHook, 4)
 	registerMsgHook((*pbc2s.LoginReq)(nil), handleLoginReq)
 	registerMsgHook((*pbc2s.LoginCharacterResp)(nil), handleLoginGameResp)
+	registerMsgHook((*pbc2s.ResumeReq)(nil), handleResumeReq)
+	registerMsgHook((*pbc2s.PlayerMigratePush)(nil), handlePlayerMigratePush)
+	registerMsgHook((*pbc2s.PlayerStopPush)(nil), handlePlayerStopPush)
+	migratePushPid, _ = c2s.Registry.GetPid((*pbc2s.PlayerMigratePush)(nil))
+	stopPushPid, _ = c2s.Registry.GetPid((*pbc2s.PlayerStopPush)(nil))
 }
 
 func registerMsgHook(msg proto.Message, hook msgHook) {
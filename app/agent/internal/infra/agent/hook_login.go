@@ -11,9 +11,9 @@ import (
 	"github.com/godyy/ggs/app/agent/internal"
 	"github.com/godyy/ggs/app/agent/internal/app"
 	"github.com/godyy/ggs/app/agent/internal/base/log"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
-	pbcommon "github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
 	"github.com/godyy/ggs/internal/models"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
 	authjwt "github.com/godyy/ggskit/base/auth/jwt"
 	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
 	"github.com/godyy/ggskit/base/db/redis"
@@ -47,6 +47,12 @@ func handleLoginReq(a *Agent, p []byte, msg proto.Message) {
 
 	req := msg.(*pbc2s.LoginReq)
 
+	// 节点排空中, 不再接受登录.
+	if app.Draining() {
+		a.Stop(pbc2s.DisconnectPush_Reconnect)
+		return
+	}
+
 	// 解析token
 	tokenInfo, errcode := parseLoginToken(a, req.Token)
 	if errcode != pbc2s.ErrCode_ECSuccess {
@@ -117,14 +123,15 @@ func handleLoginReq(a *Agent, p []byte, msg proto.Message) {
 
 	// 更新 agent
 	a.playerId = playerId
-	a.sessionId = sessionId
+	a.sessionId.Store(sessionId)
 	internal.AddAgent(a)
 
 	// 编码并发送登录游戏请求.
-	if err := a.forwardReq2Player(seq, &pbc2s.LoginCharacterReq{
+	a.loginReq = &pbc2s.LoginCharacterReq{
 		Uid:       tokenInfo.UID,
 		AccountId: tokenInfo.AccountID,
-	}); err != nil {
+	}
+	if err := a.forwardReq2Player(seq, a.loginReq); err != nil {
 		a.errorFields("forward login game request failed", log.FldUid(tokenInfo.UID), log.FldPlayerId(playerId), log.FldError(err))
 		internal.DelAgent(a)
 		a.Stop(pbc2s.DisconnectPush_SystemError)
@@ -162,12 +169,12 @@ func parseLoginToken(a *Agent, token string) (*models.TokenInfo, pbc2s.ErrCode)
 func handleLoginGameResp(a *Agent, p []byte, msg proto.Message) {
 	seq := codecc2s.HeadGetSeq(p)
 	resp := msg.(*pbc2s.LoginCharacterResp)
-	_ = resp
 
-	// 发送登录响应.
-	if err := a.sendRespMessage(seq, &pbc2s.LoginResp{
-		// todo
-	}); err != nil {
+	// 发送登录响应, 模块快照原样转发.
+	if err := a.sendLoginResp(seq, &pbc2s.LoginResp{
+		ResumeTicket: a.enableResume(),
+		Modules:      resp.Modules,
+	}, resumeReplayBufferSize()); err != nil {
 		a.errorFields("send login response failed", log.FldError(err))
 		a.Stop(pbc2s.DisconnectPush_SystemError)
 		return
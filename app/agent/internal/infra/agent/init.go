@@ -7,8 +7,8 @@ import (
 	"github.com/godyy/ggs/app/agent/internal/app"
 	"github.com/godyy/ggs/internal/base/consts"
 	"github.com/godyy/ggs/internal/base/crypto"
-	"github.com/godyy/ggs/internal/base/lifecycle"
 	inet "github.com/godyy/ggs/internal/base/net"
+	"github.com/godyy/ggs/pkg/compress"
 	authjwt "github.com/godyy/ggskit/base/auth/jwt"
 	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
 )
@@ -17,23 +17,33 @@ var (
 	// tokenKey 令牌密钥.
 	tokenKey any
 
+	// maxPacketLen 数据包最大长度.
+	maxPacketLen = uint32(128 * 1024)
+
+	// maxUnfragmentedLen 无需分片的数据包明文最大长度.
+	maxUnfragmentedLen int
+
 	// packetReadWriter 数据包读写器.
 	packetReadWriter *inet.PacketReadWriterWithCryptor
 )
 
 func init() {
-	internal.StartAgent = func(conn net.Conn, sessionKey []byte, readInsideIndependentRoutine bool) error {
-		agent, err := NewAgent(conn, sessionKey)
+	internal.StartAgent = func(conn net.Conn, sessionKey []byte, compression compress.Algorithm, readInsideIndependentRoutine bool) error {
+		agent, err := NewAgent(conn, sessionKey, compression)
 		if err != nil {
 			return err
 		}
 		agent.Start(readInsideIndependentRoutine)
 		return nil
 	}
+	internal.StartRecord = startRecord
+	internal.StopRecord = stopRecord
+	internal.ListRecords = listRecords
 
-	lifecycle.RegisterBeforeStart(func() {
+	app.RegisterBeforeStart(func() {
 		initTokenKey()
 		initPacketReadWriter()
+		initPushPolicies()
 	})
 }
 
@@ -50,6 +60,9 @@ func initPacketReadWriter() {
 	tmpKey := make([]byte, 16)
 	tmpCrypto, _ := crypto.CreateAESCrypto(tmpKey)
 	minLen := uint32(tmpCrypto.EncryptedLen(codecc2s.HeadLen))
-	maxLen := uint32(128 * 1024)
-	packetReadWriter = inet.NewPacketReadWriterWithCryptor(minLen, maxLen, consts.ReadWriteTimeout)
+	packetReadWriter = inet.NewPacketReadWriterWithCryptor(minLen, maxPacketLen, consts.ReadWriteTimeout)
+
+	// 扣除加密开销与压缩标志字节.
+	cryptoOverhead := tmpCrypto.EncryptedLen(codecc2s.HeadLen) - codecc2s.HeadLen
+	maxUnfragmentedLen = int(maxPacketLen) - cryptoOverhead - 1
 }
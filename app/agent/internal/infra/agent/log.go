@@ -21,9 +21,9 @@ func init() {
 func (a *Agent) getLogFields(fields ...zap.Field) []zap.Field {
 	var baseFields []zap.Field
 	if a.isConnected() {
-		baseFields = []zap.Field{log.FldPlayerId(a.playerId), log.FldSessionId(a.sessionId)}
+		baseFields = []zap.Field{log.FldPlayerId(a.playerId), log.FldSessionId(a.sessionId.Load())}
 	} else {
-		baseFields = []zap.Field{log.FldRemoteAddr(a.conn.RemoteAddr())}
+		baseFields = []zap.Field{log.FldRemoteAddr(a.RemoteAddr())}
 	}
 	return append(baseFields, fields...)
 }
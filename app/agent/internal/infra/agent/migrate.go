@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/godyy/ggs/app/agent/internal/base/log"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// migrateSeq 迁移后代替客户端重新登录使用的 seq, 与 keepaliveSeq 区分.
+	// 客户端请求 seq 从 1 开始递增, 不会使用该值.
+	migrateSeq = uint32(math.MaxUint32)
+
+	migrateTimeout      = 30 * time.Second       // 迁移时限.
+	migratePollInterval = 100 * time.Millisecond // 等待目标节点位置的轮询间隔.
+)
+
+// migration 玩家迁移.
+type migration struct {
+	target  string        // 目标节点ID.
+	relogin chan error    // 在目标节点重新登录的结果.
+	done    chan struct{} // 迁移结束时关闭, 期间客户端请求暂缓转发.
+}
+
+// handlePlayerMigratePush 丢弃客户端发送的玩家迁移推送, 来自 Player 的推送由 handleMigratePacket 处理.
+func handlePlayerMigratePush(a *Agent, p []byte, msg proto.Message) {}
+
+// handlePlayerStopPush 丢弃客户端发送的玩家停止推送, 来自 Player 的推送由 handleMigratePacket 处理.
+func handlePlayerStopPush(a *Agent, p []byte, msg proto.Message) {}
+
+// isMigrateResp 返回数据包是否为迁移后代为重新登录的响应.
+func isMigrateResp(p []byte) bool {
+	return codecc2s.HeadGetPt(p) == codecc2s.PtResp && codecc2s.HeadGetSeq(p) == migrateSeq
+}
+
+// handleMigratePacket 在来自 Player 的数据包入列前处理迁移与停止相关的数据包, 返回是否已处理.
+// 迁移推送与停止推送须在随后的 Actor 断开通知之前登记, 因此不能经由下行队列处理.
+func (a *Agent) handleMigratePacket(p []byte) (bool, error) {
+	switch {
+	case isMigrateResp(p):
+		a.handleMigrateResp(p)
+		return true, nil
+
+	case codecc2s.HeadGetPt(p) == codecc2s.PtPush && codecc2s.HeadGetPid(p) == migratePushPid:
+		msg, err := codecc2s.DecodeMessage(c2s.Registry, p)
+		if err != nil {
+			return false, err
+		}
+		if push := msg.(*pbc2s.PlayerMigratePush); push.Aborted {
+			a.abortMigration(push.TargetNodeId)
+		} else {
+			a.beginMigration(push.TargetNodeId)
+		}
+		return true, nil
+
+	case codecc2s.HeadGetPt(p) == codecc2s.PtPush && codecc2s.HeadGetPid(p) == stopPushPid:
+		msg, err := codecc2s.DecodeMessage(c2s.Registry, p)
+		if err != nil {
+			return false, err
+		}
+		a.actorStop.Store(int32(msg.(*pbc2s.PlayerStopPush).Reason))
+		return true, nil
+
+	default:
+		return false, nil
+	}
+}
+
+// beginMigration 登记玩家迁移.
+func (a *Agent) beginMigration(target string) {
+	a.mtx.Lock()
+	if a.migration == nil {
+		a.migration = &migration{
+			target:  target,
+			relogin: make(chan error, 1),
+			done:    make(chan struct{}),
+		}
+	}
+	a.mtx.Unlock()
+	a.infoFields("player migrating", zap.String("target", target))
+}
+
+// abortMigration 中止尚未开始的玩家迁移, 随后的 Actor 断开通知将断开客户端.
+func (a *Agent) abortMigration(target string) {
+	a.mtx.Lock()
+	m := a.migration
+	if m != nil && m.target == target {
+		a.migration = nil
+	}
+	a.mtx.Unlock()
+	if m == nil || m.target != target {
+		return
+	}
+	close(m.done)
+	a.infoFields("player migration aborted", zap.String("target", target))
+}
+
+// handleMigrateResp 处理在目标节点重新登录的响应, 登录失败时迁移立即失败.
+func (a *Agent) handleMigrateResp(p []byte) {
+	m := a.getMigration()
+	if m == nil {
+		return
+	}
+
+	msg, err := codecc2s.DecodeMessage(c2s.Registry, p)
+	if err == nil {
+		switch resp := msg.(type) {
+		case *pbc2s.LoginCharacterResp:
+		case *pbcommon.Error:
+			err = fmt.Errorf("login failed, code=%d", resp.Code)
+		default:
+			err = fmt.Errorf("unexpected login response %T", msg)
+		}
+	}
+	select {
+	case m.relogin <- err:
+	default:
+	}
+}
+
+// getMigration 返回进行中的玩家迁移.
+func (a *Agent) getMigration() *migration {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.migration
+}
+
+// waitMigration 等待进行中的玩家迁移结束, 返回 false 表示 Agent 已停止.
+func (a *Agent) waitMigration() bool {
+	m := a.getMigration()
+	if m == nil {
+		return true
+	}
+	select {
+	case <-m.done:
+		return true
+	case <-a.chStop:
+		return false
+	}
+}
+
+// HandleActorDisconnect 处理 Actor 断开连接, 玩家迁移中时重新连接至目标节点;
+// 否则以 Player 停止前告知的原因断开客户端, 未告知时视为系统错误.
+func (a *Agent) HandleActorDisconnect(sid uint32) {
+	if sid != a.SessionId() {
+		return
+	}
+
+	m := a.getMigration()
+	if m == nil {
+		reason := pbc2s.DisconnectPush_Reason(a.actorStop.Load())
+		if reason == pbc2s.DisconnectPush_Unknown {
+			reason = pbc2s.DisconnectPush_SystemError
+		}
+		a.stop(reason)
+		return
+	}
+	go a.migrate(m)
+}
+
+// migrate 等待玩家位置更新至目标节点后重新连接并登录.
+func (a *Agent) migrate(m *migration) {
+	defer a.endMigration(m)
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	if err := a.reconnectPlayer(ctx, m); err != nil {
+		if errors.Is(err, ErrStopped) {
+			return
+		}
+		a.errorFields("migrate player failed", zap.String("target", m.target), log.FldError(err))
+		a.stop(pbc2s.DisconnectPush_SystemError)
+		return
+	}
+	a.infoFields("player migrated", zap.String("target", m.target))
+}
+
+// reconnectPlayer 重新连接至目标节点上的 Player 并登录.
+func (a *Agent) reconnectPlayer(ctx context.Context, m *migration) error {
+	// 等待位置更新.
+	for {
+		location, err := getPlayerLocation(a.playerId)
+		if err == nil && checkLocation(location) && location.NodeId == m.target {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return pkgerrors.WithMessage(ctx.Err(), "wait player location")
+		case <-a.chStop:
+			return ErrStopped
+		case <-time.After(migratePollInterval):
+		}
+	}
+
+	// 重新连接.
+	sessionId := genSessionId()
+	if err := connect2Player(a.playerId, sessionId); err != nil {
+		return pkgerrors.WithMessage(err, "connect player actor")
+	}
+	a.sessionId.Store(sessionId)
+
+	// 重新登录.
+	if err := a.forwardReq2Player(migrateSeq, a.loginReq); err != nil {
+		return pkgerrors.WithMessage(err, "forward login request")
+	}
+	select {
+	case err := <-m.relogin:
+		return pkgerrors.WithMessage(err, "relogin")
+	case <-ctx.Done():
+		return pkgerrors.WithMessage(ctx.Err(), "wait login response")
+	case <-a.chStop:
+		return ErrStopped
+	}
+}
+
+// endMigration 结束玩家迁移, 迁移已中止时不再重复结束.
+func (a *Agent) endMigration(m *migration) {
+	a.mtx.Lock()
+	ended := a.migration != m
+	if !ended {
+		a.migration = nil
+	}
+	a.mtx.Unlock()
+	if !ended {
+		close(m.done)
+	}
+}
@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/godyy/gactor"
+	"github.com/godyy/ggs/app/agent/internal/app"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/godyy/ggskit/base/protocol"
+)
+
+const defaultOutboundMaxBytes = 4 * 1024 * 1024
+
+// ErrSlowClient 下行数据积压超过上限.
+var ErrSlowClient = errors.New("slow client")
+
+// pushPolicy 下行推送积压策略.
+type pushPolicy int8
+
+const (
+	pushPolicyKeep   pushPolicy = iota // 始终保留.
+	pushPolicyDrop                     // 积压时丢弃.
+	pushPolicyLatest                   // 仅保留最新一条.
+)
+
+// pushPolicies 推送消息 PID 到积压策略的映射, 启动前初始化, 此后只读.
+var pushPolicies map[protocol.PID]pushPolicy
+
+// initPushPolicies 根据配置初始化推送积压策略.
+func initPushPolicies() {
+	cfg := &app.Config().Outbound
+	pushPolicies = make(map[protocol.PID]pushPolicy, len(cfg.Droppable)+len(cfg.Latest))
+	for _, names := range []struct {
+		names  []string
+		policy pushPolicy
+	}{
+		{cfg.Droppable, pushPolicyDrop},
+		{cfg.Latest, pushPolicyLatest},
+	} {
+		for _, name := range names.names {
+			pid, ok := app.ResolveC2SPid(name)
+			if !ok {
+				loggerInst.Fatal("outbound push %s not registered", name)
+				return
+			}
+			pushPolicies[pid] = names.policy
+		}
+	}
+}
+
+// outboundEntry 下行队列中的数据包.
+type outboundEntry struct {
+	p    gactor.Buffer // 数据包.
+	size int           // 数据包长度.
+	pid  protocol.PID  // 消息 PID.
+}
+
+// outboundQueue 会话下行数据包队列.
+// 入列不阻塞, 积压字节数超过 dropBytes 时丢弃可丢弃的推送, 超过 maxBytes 时返回 ErrSlowClient.
+type outboundQueue struct {
+	mtx       sync.Mutex
+	entries   []*outboundEntry                // 待发送的数据包.
+	latest    map[protocol.PID]*outboundEntry // 仅保留最新一条的推送在队列中的位置.
+	bytes     int                             // 积压字节数.
+	maxBytes  int                             // 积压字节数上限.
+	dropBytes int                             // 开始丢弃推送的积压字节数.
+	notify    chan struct{}                   // 入列通知.
+}
+
+// newOutboundQueue 创建下行队列.
+func newOutboundQueue() *outboundQueue {
+	cfg := &app.Config().Outbound
+	maxBytes := cfg.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultOutboundMaxBytes
+	}
+	dropBytes := cfg.DropBytes
+	if dropBytes <= 0 || dropBytes > maxBytes {
+		dropBytes = maxBytes / 2
+	}
+
+	return &outboundQueue{
+		latest:    make(map[protocol.PID]*outboundEntry),
+		maxBytes:  maxBytes,
+		dropBytes: dropBytes,
+		notify:    make(chan struct{}, 1),
+	}
+}
+
+// push 数据包入列.
+func (q *outboundQueue) push(p gactor.Buffer) error {
+	unread := p.UnreadData()
+	e := &outboundEntry{
+		p:    p,
+		size: len(unread),
+		pid:  codecc2s.HeadGetPid(unread),
+	}
+
+	policy := pushPolicyKeep
+	if codecc2s.HeadGetPt(unread) == codecc2s.PtPush {
+		policy = pushPolicies[e.pid]
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	switch policy {
+	case pushPolicyLatest:
+		if old := q.latest[e.pid]; old != nil {
+			// 替换尚未发送的旧推送, 替换后同样受积压上限约束.
+			if q.bytes+e.size-old.size > q.maxBytes {
+				return ErrSlowClient
+			}
+			q.bytes += e.size - old.size
+			old.p, old.size = e.p, e.size
+			return nil
+		}
+	case pushPolicyDrop:
+		if q.bytes+e.size > q.dropBytes {
+			return nil
+		}
+	}
+
+	if q.bytes+e.size > q.maxBytes {
+		return ErrSlowClient
+	}
+
+	q.entries = append(q.entries, e)
+	q.bytes += e.size
+	if policy == pushPolicyLatest {
+		q.latest[e.pid] = e
+	}
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// pop 取出最早入列的数据包.
+func (q *outboundQueue) pop() (gactor.Buffer, bool) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if len(q.entries) == 0 {
+		return nil, false
+	}
+
+	e := q.entries[0]
+	q.entries[0] = nil
+	q.entries = q.entries[1:]
+	if len(q.entries) == 0 {
+		q.entries = nil
+	}
+	q.bytes -= e.size
+	if q.latest[e.pid] == e {
+		delete(q.latest, e.pid)
+	}
+	return e.p, true
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/godyy/gactor"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/godyy/ggskit/base/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type testBuffer []byte
+
+func (b testBuffer) UnreadData() []byte { return b }
+
+func newTestPacket(t *testing.T, pt int8, m proto.Message) gactor.Buffer {
+	p, err := codecc2s.EncodePacket(c2s.Registry, pt, 0, m)
+	require.NoError(t, err)
+	return testBuffer(p)
+}
+
+func newTestOutboundQueue(maxBytes, dropBytes int) *outboundQueue {
+	return &outboundQueue{
+		latest:    make(map[protocol.PID]*outboundEntry),
+		maxBytes:  maxBytes,
+		dropBytes: dropBytes,
+		notify:    make(chan struct{}, 1),
+	}
+}
+
+func TestOutboundQueue_Policy(t *testing.T) {
+	itemPid, _ := c2s.Registry.GetPid(&pbc2s.ItemPush{})
+	disconnectPid, _ := c2s.Registry.GetPid(&pbc2s.DisconnectPush{})
+	pushPolicies = map[protocol.PID]pushPolicy{
+		itemPid:       pushPolicyLatest,
+		disconnectPid: pushPolicyDrop,
+	}
+	defer func() { pushPolicies = nil }()
+
+	item1 := newTestPacket(t, codecc2s.PtPush, &pbc2s.ItemPush{Items: []*pbcommon.Item{{Id: 1, Count: 1}}})
+	item2 := newTestPacket(t, codecc2s.PtPush, &pbc2s.ItemPush{Items: []*pbcommon.Item{{Id: 1, Count: 2}}})
+	resp := newTestPacket(t, codecc2s.PtResp, &pbc2s.HeartbeatResp{})
+	drop := newTestPacket(t, codecc2s.PtPush, &pbc2s.DisconnectPush{Reason: pbc2s.DisconnectPush_Disconnect})
+
+	q := newTestOutboundQueue(1024, len(item1.UnreadData())+len(resp.UnreadData()))
+
+	// 未发送的旧推送被替换, 且保持原位置.
+	require.NoError(t, q.push(item1))
+	require.NoError(t, q.push(resp))
+	require.NoError(t, q.push(item2))
+	// 积压超过 dropBytes, 可丢弃的推送被丢弃.
+	require.NoError(t, q.push(drop))
+
+	p, ok := q.pop()
+	require.True(t, ok)
+	assert.Equal(t, item2, p)
+	p, ok = q.pop()
+	require.True(t, ok)
+	assert.Equal(t, resp, p)
+	_, ok = q.pop()
+	assert.False(t, ok)
+	assert.Zero(t, q.bytes)
+
+	// 已取出的推送不再被替换.
+	require.NoError(t, q.push(item1))
+	q.pop()
+	require.NoError(t, q.push(item2))
+	assert.Len(t, q.entries, 1)
+	p, ok = q.pop()
+	require.True(t, ok)
+	assert.Equal(t, item2, p)
+}
+
+func TestOutboundQueue_Overflow(t *testing.T) {
+	resp := newTestPacket(t, codecc2s.PtResp, &pbc2s.HeartbeatResp{})
+	size := len(resp.UnreadData())
+
+	q := newTestOutboundQueue(size*2, size)
+	require.NoError(t, q.push(resp))
+	require.NoError(t, q.push(resp))
+	assert.ErrorIs(t, q.push(resp), ErrSlowClient)
+
+	q.pop()
+	assert.NoError(t, q.push(resp))
+}
+
+func TestOutboundQueue_LatestOverflow(t *testing.T) {
+	itemPid, _ := c2s.Registry.GetPid(&pbc2s.ItemPush{})
+	pushPolicies = map[protocol.PID]pushPolicy{itemPid: pushPolicyLatest}
+	defer func() { pushPolicies = nil }()
+
+	small := newTestPacket(t, codecc2s.PtPush, &pbc2s.ItemPush{Items: []*pbcommon.Item{{Id: 1}}})
+	large := newTestPacket(t, codecc2s.PtPush, &pbc2s.ItemPush{Items: make([]*pbcommon.Item, 100)})
+
+	// 替换旧推送后积压超过上限.
+	q := newTestOutboundQueue(len(small.UnreadData())*2, 0)
+	require.NoError(t, q.push(small))
+	assert.ErrorIs(t, q.push(large), ErrSlowClient)
+	assert.Equal(t, len(small.UnreadData()), q.bytes)
+}
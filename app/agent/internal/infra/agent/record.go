@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggs/app/agent/internal"
+	"github.com/godyy/ggs/app/agent/internal/app"
+	"github.com/godyy/ggs/app/agent/internal/base/log"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/record"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// recorder 玩家数据包录制器, 跨会话保持, 直至停止录制.
+type recorder struct {
+	mtx  sync.Mutex
+	info internal.RecordInfo
+	file *os.File
+	w    *record.Writer
+}
+
+// write 写入录制条目.
+func (r *recorder) write(e *record.Entry) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	if err := r.w.Write(e); err != nil {
+		return err
+	}
+	r.info.Packets++
+	return nil
+}
+
+// getInfo 返回录制信息.
+func (r *recorder) getInfo() internal.RecordInfo {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.info
+}
+
+// close 关闭录制文件.
+func (r *recorder) close() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+}
+
+var (
+	recordMtx sync.Mutex // 串行化录制的开始与停止.
+	recorders sync.Map   // 录制中的玩家, 角色ID -> *recorder.
+)
+
+// startRecord 开始录制玩家数据包, 录制文件位于 Record.Dir 下, 以角色ID与开始时间命名.
+func startRecord(playerId int64) (internal.RecordInfo, error) {
+	dir := app.Config().Record.Dir
+	if dir == "" {
+		return internal.RecordInfo{}, internal.ErrRecordDisabled
+	}
+
+	recordMtx.Lock()
+	defer recordMtx.Unlock()
+
+	if v, ok := recorders.Load(playerId); ok {
+		return v.(*recorder).getInfo(), nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return internal.RecordInfo{}, pkgerrors.WithMessage(err, "create record dir")
+	}
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("%d-%s.jsonl", playerId, now.Format("20060102-150405")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return internal.RecordInfo{}, pkgerrors.WithMessage(err, "open record file")
+	}
+
+	r := &recorder{
+		info: internal.RecordInfo{PlayerId: playerId, Path: path, StartTime: now},
+		file: file,
+		w:    record.NewWriter(file),
+	}
+	recorders.Store(playerId, r)
+	return r.info, nil
+}
+
+// stopRecord 停止录制玩家数据包.
+func stopRecord(playerId int64) (internal.RecordInfo, bool) {
+	recordMtx.Lock()
+	defer recordMtx.Unlock()
+
+	v, ok := recorders.LoadAndDelete(playerId)
+	if !ok {
+		return internal.RecordInfo{}, false
+	}
+	r := v.(*recorder)
+	r.close()
+	return r.getInfo(), true
+}
+
+// listRecords 列出录制中的玩家.
+func listRecords() []internal.RecordInfo {
+	infos := make([]internal.RecordInfo, 0)
+	recorders.Range(func(_, v any) bool {
+		infos = append(infos, v.(*recorder).getInfo())
+		return true
+	})
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].PlayerId < infos[j].PlayerId
+	})
+	return infos
+}
+
+// record 录制玩家的明文数据包, 玩家未在录制中时忽略. 写入失败时停止录制.
+func (a *Agent) record(dir record.Dir, p []byte) {
+	if a.playerId == 0 {
+		return
+	}
+	v, ok := recorders.Load(a.playerId)
+	if !ok {
+		return
+	}
+	if err := v.(*recorder).write(record.NewEntry(time.Now(), dir, p)); err != nil {
+		a.errorFields("record packet failed, stop recording", log.FldError(err))
+		stopRecord(a.playerId)
+	}
+}
@@ -0,0 +1,53 @@
+package agent
+
+// replayBuffer 下行数据包重放环形缓冲区.
+// 按写出顺序为数据包编号(从 1 开始), 仅保留最近的 capacity 个数据包.
+type replayBuffer struct {
+	packets [][]byte // 环形存储.
+	head    int      // 最旧数据包所在下标.
+	size    int      // 当前缓存的数据包数量.
+	lastSeq uint32   // 最近写入的数据包编号.
+}
+
+// newReplayBuffer 创建重放缓冲区.
+func newReplayBuffer(capacity int) *replayBuffer {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &replayBuffer{
+		packets: make([][]byte, capacity),
+	}
+}
+
+// push 写入数据包, 返回其编号. 缓冲区已满时覆盖最旧的数据包.
+func (b *replayBuffer) push(p []byte) uint32 {
+	capacity := len(b.packets)
+	if b.size < capacity {
+		b.packets[(b.head+b.size)%capacity] = p
+		b.size++
+	} else {
+		b.packets[b.head] = p
+		b.head = (b.head + 1) % capacity
+	}
+	b.lastSeq++
+	return b.lastSeq
+}
+
+// canReplay 返回是否能完整重放编号大于 ackSeq 的数据包.
+func (b *replayBuffer) canReplay(ackSeq uint32) bool {
+	return ackSeq <= b.lastSeq && b.lastSeq-ackSeq <= uint32(b.size)
+}
+
+// since 返回编号大于 ackSeq 的数据包, 调用前需通过 canReplay 校验.
+func (b *replayBuffer) since(ackSeq uint32) [][]byte {
+	n := int(b.lastSeq - ackSeq)
+	if n <= 0 {
+		return nil
+	}
+	capacity := len(b.packets)
+	packets := make([][]byte, 0, n)
+	for i := b.size - n; i < b.size; i++ {
+		packets = append(packets, b.packets[(b.head+i)%capacity])
+	}
+	return packets
+}
@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayBuffer_Since(t *testing.T) {
+	buf := newReplayBuffer(3)
+
+	// 空缓冲区只能从 0 恢复.
+	assert.True(t, buf.canReplay(0))
+	assert.False(t, buf.canReplay(1))
+	assert.Empty(t, buf.since(0))
+
+	for i := byte(1); i <= 2; i++ {
+		assert.Equal(t, uint32(i), buf.push([]byte{i}))
+	}
+	assert.True(t, buf.canReplay(0))
+	assert.Equal(t, [][]byte{{1}, {2}}, buf.since(0))
+	assert.Equal(t, [][]byte{{2}}, buf.since(1))
+	assert.Empty(t, buf.since(2))
+}
+
+func TestReplayBuffer_Overwrite(t *testing.T) {
+	buf := newReplayBuffer(3)
+	for i := byte(1); i <= 5; i++ {
+		buf.push([]byte{i})
+	}
+
+	// 编号 1,2 已被覆盖, 无法完整重放.
+	assert.False(t, buf.canReplay(0))
+	assert.False(t, buf.canReplay(1))
+	assert.True(t, buf.canReplay(2))
+	assert.Equal(t, [][]byte{{3}, {4}, {5}}, buf.since(2))
+	assert.Equal(t, [][]byte{{5}}, buf.since(4))
+
+	// 客户端确认的编号超出已发送范围.
+	assert.False(t, buf.canReplay(6))
+}
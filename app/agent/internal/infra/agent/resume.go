@@ -0,0 +1,327 @@
+package agent
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/godyy/ggs/app/agent/internal/app"
+	"github.com/godyy/ggs/app/agent/internal/base/log"
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultResumeGracePeriod      = 30 * time.Second
+	defaultResumeReplayBufferSize = 256
+
+	// keepaliveSeq 会话挂起期间代替客户端发送心跳使用的 seq.
+	// 客户端请求 seq 从 1 开始, 因此 seq 为 0 的响应均来自代发心跳.
+	keepaliveSeq = uint32(0)
+)
+
+// resumeTickets 会话恢复票据到 Agent 的映射.
+var resumeTickets sync.Map
+
+// resumeConn 用于恢复会话的新连接.
+type resumeConn struct {
+	stream  *stream // 客户端连接.
+	seq     uint32  // 恢复请求 seq.
+	lastSeq uint32  // 客户端已收到的下行数据包编号.
+}
+
+// resumeEnabled 返回是否启用会话恢复.
+func resumeEnabled() bool {
+	return app.Config().Resume.Enable
+}
+
+// resumeGracePeriod 返回连接断开后会话的保留时长.
+func resumeGracePeriod() time.Duration {
+	if d := app.Config().Resume.GracePeriod; d > 0 {
+		return d
+	}
+	return defaultResumeGracePeriod
+}
+
+// resumeReplayBufferSize 返回重放缓冲区容量.
+func resumeReplayBufferSize() int {
+	if n := app.Config().Resume.ReplayBufferSize; n > 0 {
+		return n
+	}
+	return defaultResumeReplayBufferSize
+}
+
+// genResumeTicket 生成会话恢复票据.
+func genResumeTicket() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// getResumeAgent 根据票据获取 Agent.
+func getResumeAgent(ticket string) *Agent {
+	if ticket == "" {
+		return nil
+	}
+	v, ok := resumeTickets.Load(ticket)
+	if !ok {
+		return nil
+	}
+	return v.(*Agent)
+}
+
+// isKeepaliveResp 返回数据包是否为代发心跳的响应.
+func isKeepaliveResp(p []byte) bool {
+	return codecc2s.HeadGetPt(p) == codecc2s.PtResp && codecc2s.HeadGetSeq(p) == keepaliveSeq
+}
+
+// enableResume 为已登录的会话生成恢复票据, 未启用会话恢复或生成失败时返回空.
+// 重放缓冲区在登录响应写出后由 sendLoginResp 创建.
+func (a *Agent) enableResume() string {
+	if !resumeEnabled() {
+		return ""
+	}
+
+	ticket, err := a.rotateResumeTicket()
+	if err != nil {
+		a.errorFields("gen resume ticket failed", log.FldError(err))
+		return ""
+	}
+	return ticket
+}
+
+// sendLoginResp 发送登录响应, 携带票据时随即创建容量为 replayCapacity 的重放缓冲区.
+// 登录响应不计入重放编号, 其后写出的全部下行数据包(包括 Agent 直接发送的消息)从 1 开始编号,
+// 与客户端收到登录响应后重新计数保持一致.
+func (a *Agent) sendLoginResp(seq uint32, resp *pbc2s.LoginResp, replayCapacity int) error {
+	p, err := codecc2s.EncodePacket(c2s.Registry, codecc2s.PtResp, seq, resp)
+	if err != nil {
+		return err
+	}
+
+	a.writeMtx.Lock()
+	defer a.writeMtx.Unlock()
+	if _, err := a.writePacketLocked(p); err != nil {
+		return err
+	}
+	if resp.ResumeTicket != "" {
+		a.replayBuf = newReplayBuffer(replayCapacity)
+	}
+	return nil
+}
+
+// rotateResumeTicket 生成新的票据并替换旧票据.
+func (a *Agent) rotateResumeTicket() (string, error) {
+	ticket, err := genResumeTicket()
+	if err != nil {
+		return "", err
+	}
+
+	a.mtx.Lock()
+	old := a.resumeTicket
+	a.resumeTicket = ticket
+	a.mtx.Unlock()
+
+	if old != "" {
+		resumeTickets.Delete(old)
+	}
+	resumeTickets.Store(ticket, a)
+	return ticket, nil
+}
+
+// delResumeTicket 删除票据, 会话不再可恢复.
+func (a *Agent) delResumeTicket() {
+	a.mtx.Lock()
+	ticket := a.resumeTicket
+	a.resumeTicket = ""
+	a.mtx.Unlock()
+
+	if ticket != "" {
+		resumeTickets.Delete(ticket)
+	}
+}
+
+// detach 连接 s 读写失败时挂起会话, 保留与 Player 的会话等待客户端恢复.
+// 返回 false 表示会话不可恢复, 需由调用方停止 Agent.
+func (a *Agent) detach(s *stream, err error) bool {
+	a.mtx.Lock()
+	if a.stream != s {
+		// 连接已被替换或移交, 无需处理.
+		a.mtx.Unlock()
+		return true
+	}
+	if a.resumeTicket == "" || atomic.LoadInt32(&a.stopFlag) != 0 {
+		a.mtx.Unlock()
+		return false
+	}
+	a.stream = nil
+	a.chDetach = make(chan struct{})
+	chDetach := a.chDetach
+	a.mtx.Unlock()
+
+	s.close()
+	a.infoFields("connection lost, session detached", log.FldError(err))
+	go a.detachLoop(chDetach)
+	return true
+}
+
+// detachLoop 会话挂起期间的处理循环.
+// 代替客户端向 Player 发送心跳, 超过保留时长仍未恢复则停止 Agent.
+func (a *Agent) detachLoop(chDetach chan struct{}) {
+	graceTimer := time.NewTimer(resumeGracePeriod())
+	defer graceTimer.Stop()
+	keepaliveTicker := time.NewTicker(consts.HeartbeatInterval)
+	defer keepaliveTicker.Stop()
+
+	keepalive := func() bool {
+		if err := a.forwardReq2Player(keepaliveSeq, &pbc2s.HeartbeatReq{}); err != nil {
+			a.errorFields("[detachLoop] forward keepalive heartbeat failed", log.FldError(err))
+			a.stop(pbc2s.DisconnectPush_SystemError)
+			return false
+		}
+		return true
+	}
+
+	if !keepalive() {
+		return
+	}
+
+	for {
+		select {
+		case <-keepaliveTicker.C:
+			if !keepalive() {
+				return
+			}
+		case <-graceTimer.C:
+			a.mtx.RLock()
+			detached := a.chDetach == chDetach
+			a.mtx.RUnlock()
+			if detached {
+				a.infoFields("session resume grace period expired")
+				a.stop(pbc2s.DisconnectPush_Disconnect)
+			}
+			return
+		case <-chDetach:
+			return
+		case <-a.chStop:
+			return
+		}
+	}
+}
+
+// handOver 移交当前连接, 随后停止 Agent 但不关闭连接.
+func (a *Agent) handOver() *stream {
+	a.mtx.Lock()
+	s := a.stream
+	a.stream = nil
+	a.mtx.Unlock()
+
+	a.stop(pbc2s.DisconnectPush_Unknown)
+	return s
+}
+
+// resume 将新连接提交给 Agent 以恢复会话.
+func (a *Agent) resume(rc *resumeConn) error {
+	select {
+	case a.chResume <- rc:
+		return nil
+	case <-a.chStop:
+		return ErrStopped
+	}
+}
+
+// handleResume 使用新连接恢复会话, 并重放客户端未收到的下行数据包.
+// 仅在 pendingPacketLoop 中调用.
+func (a *Agent) handleResume(rc *resumeConn) {
+	a.writeMtx.Lock()
+	defer a.writeMtx.Unlock()
+
+	// 校验重放范围.
+	if a.replayBuf == nil || !a.replayBuf.canReplay(rc.lastSeq) {
+		a.infoFields("resume rejected, replay range unavailable", zap.Uint32("lastSeq", rc.lastSeq))
+		rejectResume(rc.stream, rc.seq)
+		return
+	}
+
+	ticket, err := a.rotateResumeTicket()
+	if err != nil {
+		a.errorFields("rotate resume ticket failed", log.FldError(err))
+		rejectResume(rc.stream, rc.seq)
+		return
+	}
+
+	// 替换连接, 新连接的流量计入当前会话并受当前会话的限制.
+	rc.stream.traffic = &a.traffic
+	rc.stream.limiter = a.limiter
+	a.mtx.Lock()
+	old := a.stream
+	a.stream = rc.stream
+	a.remoteAddr = rc.stream.conn.RemoteAddr()
+	if a.chDetach != nil {
+		close(a.chDetach)
+		a.chDetach = nil
+	}
+	a.mtx.Unlock()
+	if old != nil {
+		old.close()
+	}
+
+	a.infoFields("session resumed", zap.Uint32("lastSeq", rc.lastSeq), zap.Uint32("replay", a.replayBuf.lastSeq-rc.lastSeq))
+
+	// 发送响应并重放数据包.
+	if err := rc.stream.writeMessage(codecc2s.PtResp, rc.seq, &pbc2s.ResumeResp{Ticket: ticket}); err != nil {
+		if !a.detach(rc.stream, err) {
+			a.stop(pbc2s.DisconnectPush_SystemError)
+		}
+		return
+	}
+	for _, p := range a.replayBuf.since(rc.lastSeq) {
+		if err := rc.stream.writePacket(p); err != nil {
+			if !a.detach(rc.stream, err) {
+				a.stop(pbc2s.DisconnectPush_SystemError)
+			}
+			return
+		}
+	}
+
+	go a.readLoop(rc.stream)
+}
+
+// rejectResume 拒绝会话恢复并关闭连接.
+func rejectResume(s *stream, seq uint32) {
+	s.writeMessage(codecc2s.PtResp, seq, &pbcommon.Error{Code: int32(pbc2s.ErrCode_ECResumeFailed)})
+	s.close()
+}
+
+// handleResumeReq 处理会话恢复请求.
+func handleResumeReq(a *Agent, p []byte, msg proto.Message) {
+	seq := codecc2s.HeadGetSeq(p)
+	req := msg.(*pbc2s.ResumeReq)
+
+	target := getResumeAgent(req.Ticket)
+	if a.isConnected() || target == nil {
+		a.infoFields("resume ticket invalid")
+		a.sendRespMessage(seq, &pbcommon.Error{Code: int32(pbc2s.ErrCode_ECResumeFailed)})
+		a.Stop(pbc2s.DisconnectPush_Unknown)
+		return
+	}
+
+	// 连接移交给目标 Agent.
+	s := a.handOver()
+	if err := target.resume(&resumeConn{
+		stream:  s,
+		seq:     seq,
+		lastSeq: req.LastSeq,
+	}); err != nil {
+		rejectResume(s, seq)
+	}
+}
@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/crypto"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	"github.com/godyy/ggs/pkg/compress"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	sdkclient "github.com/godyy/ggs/sdk/client"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// testStreamHandler 按客户端规则统计登录后收到的下行数据包.
+type testStreamHandler struct {
+	chMsg chan sdkclient.Msg
+}
+
+func (h *testStreamHandler) OnStreamMsg(msg sdkclient.Msg) { h.chMsg <- msg }
+func (h *testStreamHandler) OnStreamClose(error)           {}
+
+func TestAgent_ReplaySeqMixed(t *testing.T) {
+	initPacketReadWriter()
+
+	key := []byte("0123456789abcdef")
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	cryptor, err := crypto.CreateAESCrypto(key)
+	require.NoError(t, err)
+
+	a := &Agent{chStop: make(chan struct{})}
+	a.stream = &stream{conn: serverConn, crypto: cryptor, traffic: &a.traffic}
+
+	h := &testStreamHandler{chMsg: make(chan sdkclient.Msg, 16)}
+	cs, err := sdkclient.NewStream(clientConn, key, compress.None, h)
+	require.NoError(t, err)
+	defer cs.Close()
+
+	// Player 推送经由 writePacket 写出, Agent 直接发送的响应经由 sendMessage 写出.
+	push := func(n int32) {
+		p, err := codecc2s.EncodePacket(c2s.Registry, codecc2s.PtPush, 0, &pbc2s.ItemPush{Items: []*pbcommon.Item{{Id: n}}})
+		require.NoError(t, err)
+		_, err = a.writePacket(p)
+		require.NoError(t, err)
+	}
+	reply := func(seq uint32) {
+		require.NoError(t, a.sendRespMessage(seq, &pbcommon.Error{Code: int32(seq)}))
+	}
+
+	// 登录前的响应不计入编号.
+	reply(1)
+	require.NoError(t, a.sendLoginResp(2, &pbc2s.LoginResp{ResumeTicket: "ticket"}, 16))
+	push(1)
+	reply(3)
+	push(2)
+	reply(4)
+	push(3)
+
+	// 客户端计数规则: 登录响应后清零, 其后每个数据包计数.
+	var (
+		recvSeq  uint32
+		received []proto.Message
+	)
+	for i := 0; i < 7; i++ {
+		select {
+		case msg := <-h.chMsg:
+			if _, ok := msg.Msg.(*pbc2s.LoginResp); ok {
+				recvSeq = 0
+				received = received[:0]
+				continue
+			}
+			recvSeq++
+			received = append(received, msg.Msg)
+		case <-time.After(time.Second):
+			t.Fatal("receive timeout")
+		}
+	}
+
+	require.Equal(t, a.replayBuf.lastSeq, recvSeq)
+
+	// 从任意位置恢复, 重放的数据包与客户端在该位置之后收到的一致.
+	for ack := uint32(0); ack <= recvSeq; ack++ {
+		require.True(t, a.replayBuf.canReplay(ack))
+		replayed := a.replayBuf.since(ack)
+		require.Len(t, replayed, int(recvSeq-ack))
+		for i, p := range replayed {
+			msg, err := codecc2s.DecodeMessage(c2s.Registry, p)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(received[int(ack)+i], msg), "ack=%d index=%d", ack, i)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/godyy/ggs/app/agent/internal/app"
+	"github.com/godyy/ggs/app/agent/internal/infra/guard"
+	"github.com/godyy/ggs/internal/base/crypto"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/fragment"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	"github.com/godyy/ggs/pkg/compress"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/godyy/ggskit/base/crypto/aes"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// stream 客户端连接, 负责数据包的分片、压缩、加密与读写.
+type stream struct {
+	conn        net.Conn              // 网络连接.
+	crypto      aes.Cryptor           // 密码工具.
+	codec       *compress.Codec       // 压缩编解码器, 未协商压缩时为 nil.
+	writeMtx    sync.Mutex            // 串行化写出, 保证同一消息的分片连续写出.
+	fragId      uint32                // 分片消息编号.
+	reassembler *fragment.Reassembler // 分片重组器, 仅由 readLoop 访问.
+	traffic     *traffic              // 流量统计.
+	limiter     *guard.SessionLimiter // 会话级流量限制, 按分片计费字节数, 未启用时为 nil.
+}
+
+// errRateLimited 读取的数据包超出会话字节数限制.
+var errRateLimited = errors.New("rate limited")
+
+// traffic 会话流量统计.
+type traffic struct {
+	bytesIn  atomic.Uint64 // 接收的字节数.
+	bytesOut atomic.Uint64 // 发送的字节数.
+}
+
+// newStream 创建 stream.
+func newStream(conn net.Conn, sessionKey []byte, compression compress.Algorithm, traffic *traffic) (*stream, error) {
+	crypto, err := crypto.CreateAESCrypto(sessionKey)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "create crypto failed")
+	}
+
+	fragCfg := &app.Config().Fragment
+	return &stream{
+		conn:        conn,
+		crypto:      crypto,
+		codec:       compress.NewCodec(compression, app.Config().Compression.Threshold, int(maxPacketLen)),
+		reassembler: fragment.NewReassembler(fragCfg.MaxMessageLen, fragCfg.MaxPartials),
+		traffic:     traffic,
+	}, nil
+}
+
+// readPacket 读取数据包, 分片消息重组完成后返回原数据包.
+func (s *stream) readPacket() ([]byte, error) {
+	for {
+		p, err := s.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		if !fragment.IsFragment(p) {
+			return p, nil
+		}
+		if p, err = s.reassembler.Add(p); err != nil || p != nil {
+			return p, err
+		}
+	}
+}
+
+// readFrame 读取单个数据包, 返回解压后的明文. 超出字节数限制时返回 errRateLimited.
+func (s *stream) readFrame() ([]byte, error) {
+	p, err := packetReadWriter.ReadAndDecryptPacket(s.conn, s.crypto)
+	if err != nil {
+		return nil, err
+	}
+	s.traffic.bytesIn.Add(uint64(len(p)))
+	if s.limiter != nil && !s.limiter.AllowBytes(len(p)) {
+		return nil, errRateLimited
+	}
+	if s.codec == nil {
+		return p, nil
+	}
+	return s.codec.Decode(p)
+}
+
+// writePacket 写出数据包, 超过长度上限时拆分为分片. 可并发调用.
+func (s *stream) writePacket(p []byte) error {
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+
+	if len(p) <= maxUnfragmentedLen {
+		return s.writeFrame(p)
+	}
+
+	fps, err := fragment.Split(p, atomic.AddUint32(&s.fragId, 1), fragment.DefaultChunkSize)
+	if err != nil {
+		return err
+	}
+	for _, fp := range fps {
+		if err := s.writeFrame(fp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFrame 写出单个数据包, 按需压缩.
+func (s *stream) writeFrame(p []byte) error {
+	if s.codec != nil {
+		var err error
+		if p, err = s.codec.Encode(p); err != nil {
+			return err
+		}
+	}
+	if err := packetReadWriter.EncryptAndWritePacket(s.conn, p, s.crypto); err != nil {
+		return err
+	}
+	s.traffic.bytesOut.Add(uint64(len(p)))
+	return nil
+}
+
+// writeMessage 编码并写出消息.
+func (s *stream) writeMessage(pt int8, seq uint32, m proto.Message) error {
+	p, err := codecc2s.EncodePacket(c2s.Registry, pt, seq, m)
+	if err != nil {
+		return err
+	}
+	return s.writePacket(p)
+}
+
+// close 关闭连接.
+func (s *stream) close() error {
+	return s.conn.Close()
+}
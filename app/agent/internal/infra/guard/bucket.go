@@ -0,0 +1,58 @@
+package guard
+
+import "time"
+
+// tokenBucket 令牌桶, 非并发安全.
+type tokenBucket struct {
+	rate   float64   // 每秒补充的令牌数.
+	burst  float64   // 桶容量.
+	tokens float64   // 当前令牌数.
+	last   time.Time // 上一次补充时间.
+}
+
+// newTokenBucket 创建令牌桶, 不限制时返回 nil.
+func newTokenBucket(l Limit, now time.Time) *tokenBucket {
+	if l.Rate <= 0 {
+		return nil
+	}
+	burst := l.Burst
+	if burst <= 0 {
+		burst = l.Rate
+	}
+	return &tokenBucket{
+		rate:   l.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   now,
+	}
+}
+
+// allow 尝试消耗 n 个令牌, 返回是否成功. nil 令牌桶总是成功.
+func (b *tokenBucket) allow(n float64, now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	if b.tokens < n {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+// full 返回令牌桶是否已满.
+func (b *tokenBucket) full(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	return b.tokens >= b.burst
+}
+
+// refill 补充令牌.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
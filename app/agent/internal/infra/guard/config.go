@@ -0,0 +1,65 @@
+package guard
+
+import "time"
+
+// Limit 令牌桶限制.
+type Limit struct {
+	// Rate 每秒补充的令牌数, <=0 表示不限制.
+	Rate float64
+
+	// Burst 桶容量, <=0 时与 Rate 相同.
+	// 用于字节数限制时, 需不小于单个数据包的最大长度.
+	Burst float64
+}
+
+// ClassConfig 按 PID 分类的会话限制.
+type ClassConfig struct {
+	// Name 分类名称.
+	Name string
+
+	// Messages 属于该分类的消息名, 如 "UseItemReq" 或 "c2s.UseItemReq".
+	Messages []string
+
+	// Packets 数据包数量限制.
+	Packets Limit
+}
+
+// SessionConfig 会话级限制配置.
+type SessionConfig struct {
+	// Packets 数据包数量限制.
+	Packets Limit
+
+	// Bytes 字节数限制.
+	Bytes Limit
+
+	// Classes 按 PID 分类的数据包数量限制, 与整体限制同时生效.
+	Classes []ClassConfig
+}
+
+// IPConfig 来源 IP 级限制配置.
+type IPConfig struct {
+	// Attempts 连接尝试频率限制.
+	Attempts Limit
+
+	// MaxConns 最大并发连接数, <=0 表示不限制.
+	MaxConns int
+
+	// KeyExchangeFailures 密钥交换失败频率限制.
+	KeyExchangeFailures Limit
+
+	// SessionViolations 会话超出限制的频率限制. 超出会话限制时仅断开该会话,
+	// 同一 IP 下的会话违规超出该限制时才封禁 IP.
+	SessionViolations Limit
+}
+
+// Config 流量防护配置.
+type Config struct {
+	// BanDuration 违规来源 IP 的封禁时长, <=0 表示不封禁.
+	BanDuration time.Duration
+
+	// Session 会话级限制.
+	Session SessionConfig
+
+	// IP 来源 IP 级限制.
+	IP IPConfig
+}
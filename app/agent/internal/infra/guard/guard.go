@@ -0,0 +1,256 @@
+package guard
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggskit/base/protocol"
+	pkgerrors "github.com/pkg/errors"
+)
+
+var (
+	// ErrBanned 来源 IP 已被封禁.
+	ErrBanned = errors.New("ip banned")
+
+	// ErrTooManyAttempts 连接尝试过于频繁.
+	ErrTooManyAttempts = errors.New("too many connection attempts")
+
+	// ErrTooManyConns 并发连接数过多.
+	ErrTooManyConns = errors.New("too many connections")
+)
+
+// sweepInterval 清理空闲 IP 状态的间隔.
+const sweepInterval = time.Minute
+
+// PidResolver 根据消息名解析 PID.
+type PidResolver func(name string) (protocol.PID, bool)
+
+// ipState 来源 IP 状态.
+type ipState struct {
+	attempts    *tokenBucket // 连接尝试.
+	kexFailures *tokenBucket // 密钥交换失败.
+	violations  *tokenBucket // 会话违规.
+	conns       int          // 当前连接数.
+	bannedUntil time.Time    // 封禁截止时间.
+}
+
+// Guard 流量防护, 提供来源 IP 级限制与会话级限制.
+type Guard struct {
+	cfg     *Config
+	classes map[protocol.PID]int // PID 到分类下标的映射.
+
+	mtx sync.Mutex
+	ips map[string]*ipState
+
+	chStop chan struct{}
+}
+
+// New 创建 Guard.
+func New(cfg *Config, resolvePid PidResolver) (*Guard, error) {
+	classes := make(map[protocol.PID]int)
+	for i, class := range cfg.Session.Classes {
+		for _, name := range class.Messages {
+			pid, ok := resolvePid(name)
+			if !ok {
+				return nil, pkgerrors.Errorf("class %s: message %s not registered", class.Name, name)
+			}
+			if j, ok := classes[pid]; ok && j != i {
+				return nil, pkgerrors.Errorf("class %s: message %s already in class %s", class.Name, name, cfg.Session.Classes[j].Name)
+			}
+			classes[pid] = i
+		}
+	}
+
+	g := &Guard{
+		cfg:     cfg,
+		classes: classes,
+		ips:     make(map[string]*ipState),
+		chStop:  make(chan struct{}),
+	}
+	go g.sweepLoop()
+	return g, nil
+}
+
+// Stop 停止 Guard.
+func (g *Guard) Stop() {
+	close(g.chStop)
+}
+
+// getIPState 获取来源 IP 状态, 需持有锁.
+func (g *Guard) getIPState(ip string, now time.Time) *ipState {
+	s, ok := g.ips[ip]
+	if !ok {
+		s = &ipState{
+			attempts:    newTokenBucket(g.cfg.IP.Attempts, now),
+			kexFailures: newTokenBucket(g.cfg.IP.KeyExchangeFailures, now),
+			violations:  newTokenBucket(g.cfg.IP.SessionViolations, now),
+		}
+		g.ips[ip] = s
+	}
+	return s
+}
+
+// AcquireConn 检查并登记来自 ip 的新连接, 成功后需调用 ReleaseConn 释放.
+func (g *Guard) AcquireConn(ip string) error {
+	now := time.Now()
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	s := g.getIPState(ip, now)
+	if now.Before(s.bannedUntil) {
+		return ErrBanned
+	}
+	if !s.attempts.allow(1, now) {
+		g.ban(s, now)
+		return ErrTooManyAttempts
+	}
+	if g.cfg.IP.MaxConns > 0 && s.conns >= g.cfg.IP.MaxConns {
+		return ErrTooManyConns
+	}
+	s.conns++
+	return nil
+}
+
+// ReleaseConn 释放来自 ip 的连接.
+func (g *Guard) ReleaseConn(ip string) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if s, ok := g.ips[ip]; ok && s.conns > 0 {
+		s.conns--
+	}
+}
+
+// ReportKeyExchangeFailure 记录来自 ip 的密钥交换失败, 返回是否因此被封禁.
+func (g *Guard) ReportKeyExchangeFailure(ip string) bool {
+	now := time.Now()
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	s := g.getIPState(ip, now)
+	if s.kexFailures.allow(1, now) {
+		return false
+	}
+	return g.ban(s, now)
+}
+
+// ReportSessionViolation 记录来自 ip 的会话超出限制, 返回是否因此被封禁.
+// 违规会话由调用方断开, 仅当该 IP 下的会话违规过于频繁时才封禁 IP.
+func (g *Guard) ReportSessionViolation(ip string) bool {
+	now := time.Now()
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	s := g.getIPState(ip, now)
+	if s.violations.allow(1, now) {
+		return false
+	}
+	return g.ban(s, now)
+}
+
+// ban 封禁, 需持有锁.
+func (g *Guard) ban(s *ipState, now time.Time) bool {
+	if g.cfg.BanDuration <= 0 {
+		return false
+	}
+	s.bannedUntil = now.Add(g.cfg.BanDuration)
+	return true
+}
+
+// sweepLoop 定期清理空闲的 IP 状态.
+func (g *Guard) sweepLoop() {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.sweep(time.Now())
+		case <-g.chStop:
+			return
+		}
+	}
+}
+
+// sweep 清理无连接, 未封禁且令牌桶已满的 IP 状态.
+func (g *Guard) sweep(now time.Time) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	for ip, s := range g.ips {
+		if s.conns == 0 && !now.Before(s.bannedUntil) && s.attempts.full(now) && s.kexFailures.full(now) && s.violations.full(now) {
+			delete(g.ips, ip)
+		}
+	}
+}
+
+// NewSessionLimiter 创建会话级限制器.
+func (g *Guard) NewSessionLimiter() *SessionLimiter {
+	now := time.Now()
+	l := &SessionLimiter{
+		guard:   g,
+		packets: newTokenBucket(g.cfg.Session.Packets, now),
+		bytes:   newTokenBucket(g.cfg.Session.Bytes, now),
+		classes: make([]*tokenBucket, len(g.cfg.Session.Classes)),
+	}
+	for i, class := range g.cfg.Session.Classes {
+		l.classes[i] = newTokenBucket(class.Packets, now)
+	}
+	return l
+}
+
+// SessionLimiter 会话级限制器.
+type SessionLimiter struct {
+	guard   *Guard
+	mtx     sync.Mutex
+	packets *tokenBucket   // 数据包数量.
+	bytes   *tokenBucket   // 字节数.
+	classes []*tokenBucket // 各分类数据包数量.
+}
+
+// Allow 返回是否允许接收 PID 为 pid 的数据包, 分片消息在重组完成后计数一次.
+func (l *SessionLimiter) Allow(pid protocol.PID) bool {
+	now := time.Now()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if !l.packets.allow(1, now) {
+		return false
+	}
+	if i, ok := l.guard.classes[pid]; ok {
+		return l.classes[i].allow(1, now)
+	}
+	return true
+}
+
+// AllowBytes 返回是否允许接收长度为 size 的线上数据包. 按分片计费,
+// 单个分片不超过数据包长度上限, 因此重组后的消息长度不受 Bytes.Burst 约束.
+func (l *SessionLimiter) AllowBytes(size int) bool {
+	now := time.Now()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.bytes.allow(float64(size), now)
+}
+
+// IPOf 返回地址中的 IP 部分.
+func IPOf(addr net.Addr) string {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP.String()
+	case *net.UDPAddr:
+		return a.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
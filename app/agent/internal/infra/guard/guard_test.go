@@ -0,0 +1,107 @@
+package guard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godyy/ggskit/base/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(Limit{Rate: 2, Burst: 4}, now)
+
+	for i := 0; i < 4; i++ {
+		assert.True(t, b.allow(1, now))
+	}
+	assert.False(t, b.allow(1, now))
+
+	// 0.5 秒补充 1 个令牌.
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, b.allow(1, now))
+	assert.False(t, b.allow(1, now))
+
+	// 不超过桶容量.
+	now = now.Add(time.Hour)
+	assert.True(t, b.full(now))
+	assert.False(t, b.allow(5, now))
+
+	// 不限制.
+	assert.Nil(t, newTokenBucket(Limit{}, now))
+	assert.True(t, (*tokenBucket)(nil).allow(1e9, now))
+}
+
+func TestGuard_IP(t *testing.T) {
+	g, err := New(&Config{
+		BanDuration: time.Minute,
+		IP: IPConfig{
+			Attempts:            Limit{Rate: 1, Burst: 3},
+			MaxConns:            2,
+			KeyExchangeFailures: Limit{Rate: 1, Burst: 1},
+			SessionViolations:   Limit{Rate: 1, Burst: 2},
+		},
+	}, nil)
+	assert.NoError(t, err)
+	defer g.Stop()
+
+	// 并发连接数.
+	assert.NoError(t, g.AcquireConn("1.1.1.1"))
+	assert.NoError(t, g.AcquireConn("1.1.1.1"))
+	assert.ErrorIs(t, g.AcquireConn("1.1.1.1"), ErrTooManyConns)
+	g.ReleaseConn("1.1.1.1")
+
+	// 连接尝试过于频繁, 触发封禁.
+	assert.ErrorIs(t, g.AcquireConn("1.1.1.1"), ErrTooManyAttempts)
+	assert.ErrorIs(t, g.AcquireConn("1.1.1.1"), ErrBanned)
+
+	// 密钥交换失败.
+	assert.False(t, g.ReportKeyExchangeFailure("2.2.2.2"))
+	assert.True(t, g.ReportKeyExchangeFailure("2.2.2.2"))
+	assert.ErrorIs(t, g.AcquireConn("2.2.2.2"), ErrBanned)
+
+	// 会话违规, 仅在过于频繁时封禁 IP.
+	assert.False(t, g.ReportSessionViolation("4.4.4.4"))
+	assert.False(t, g.ReportSessionViolation("4.4.4.4"))
+	assert.NoError(t, g.AcquireConn("4.4.4.4"))
+	assert.True(t, g.ReportSessionViolation("4.4.4.4"))
+	assert.ErrorIs(t, g.AcquireConn("4.4.4.4"), ErrBanned)
+
+	// 其他 IP 不受影响.
+	assert.NoError(t, g.AcquireConn("3.3.3.3"))
+}
+
+func TestGuard_SessionLimiter(t *testing.T) {
+	g, err := New(&Config{
+		Session: SessionConfig{
+			Packets: Limit{Rate: 100, Burst: 100},
+			Bytes:   Limit{Rate: 1000, Burst: 1000},
+			Classes: []ClassConfig{
+				{Name: "slow", Messages: []string{"Slow"}, Packets: Limit{Rate: 1, Burst: 2}},
+			},
+		},
+	}, func(name string) (protocol.PID, bool) {
+		return 7, name == "Slow"
+	})
+	assert.NoError(t, err)
+	defer g.Stop()
+
+	l := g.NewSessionLimiter()
+
+	// 分类限制.
+	assert.True(t, l.Allow(7))
+	assert.True(t, l.Allow(7))
+	assert.False(t, l.Allow(7))
+	assert.True(t, l.Allow(1))
+
+	// 字节数限制, 按分片计费.
+	assert.True(t, l.AllowBytes(600))
+	assert.False(t, l.AllowBytes(600))
+	assert.True(t, l.AllowBytes(400))
+
+	// 未注册的消息名.
+	_, err = New(&Config{
+		Session: SessionConfig{Classes: []ClassConfig{{Name: "x", Messages: []string{"Unknown"}}}},
+	}, func(string) (protocol.PID, bool) { return 0, false })
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"math"
+)
+
+const (
+	defaultLoadFactor = 1.25
+	defaultMaxCPU     = 0.9
+)
+
+// Balance 有界负载配置。
+type Balance struct {
+	// LoadFactor 节点负载上限相对平均负载的倍数，默认 1.25，小于 1 时视为 1。
+	LoadFactor float64
+
+	// MaxCPU CPU 使用率上限（0~1），超过时不作为首选节点，默认 0.9。
+	MaxCPU float64
+}
+
+// DefaultBalance 返回默认配置。
+func DefaultBalance() Balance {
+	return Balance{
+		LoadFactor: defaultLoadFactor,
+		MaxCPU:     defaultMaxCPU,
+	}
+}
+
+// withDefaults 返回补全默认值后的配置。
+func (b Balance) withDefaults() Balance {
+	if b.LoadFactor <= 0 {
+		b.LoadFactor = defaultLoadFactor
+	} else if b.LoadFactor < 1 {
+		b.LoadFactor = 1
+	}
+	if b.MaxCPU <= 0 {
+		b.MaxCPU = defaultMaxCPU
+	}
+	return b
+}
+
+// NodeLoad 节点负载，由节点定期发布。
+type NodeLoad struct {
+	Players  int64   // 活跃玩家数量
+	CPU      float64 // CPU 使用率估算值（0~1）
+	Draining bool    // 是否排空中
+	UpdateAt int64   // 发布时间，用于识别新的发布
+}
+
+// nodeLoad 节点负载及本地计入的选择次数。
+type nodeLoad struct {
+	NodeLoad
+	picked int64 // 自上次发布以来本地选中的次数
+}
+
+// players 返回计入本地选择后的玩家数量。
+func (l *nodeLoad) players() int64 {
+	return l.Players + l.picked
+}
+
+// SetLoads 全量设置节点负载，节点ID -> 负载。
+// 节点发布新的负载时，清零本地计入的选择次数。
+func (s *NodeSelector) SetLoads(loads map[string]NodeLoad) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	next := make(map[string]*nodeLoad, len(loads))
+	for id, load := range loads {
+		l := &nodeLoad{NodeLoad: load}
+		if prev := s.loads[id]; prev != nil && prev.UpdateAt == load.UpdateAt {
+			l.picked = prev.picked
+		}
+		next[id] = l
+	}
+	s.loads = next
+}
+
+// pick 在按哈希顺序排列的候选 ids 中选择首选节点，返回其下标。
+// 缺少负载信息的节点视为空载；所有节点都不满足条件时，依次放宽 CPU、负载上限，
+// 仍不满足时返回第一个未排空的节点，全部排空时返回 0。
+// 负载上限为 LoadFactor 倍的未排空节点平均负载（计入本次选择）。
+func (b *Balance) pick(ids []string, loads map[string]*nodeLoad) int {
+	// 平均负载仅统计未排空的节点。
+	var total int64
+	count := 0
+	for _, id := range ids {
+		l := loads[id]
+		if l != nil && l.Draining {
+			continue
+		}
+		if l != nil {
+			total += l.players()
+		}
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	bound := math.Ceil(b.LoadFactor * float64(total+1) / float64(count))
+
+	firstAvailable := -1
+	firstUnderBound := -1
+	for i, id := range ids {
+		l := loads[id]
+		if l == nil {
+			return i
+		}
+		if l.Draining {
+			continue
+		}
+		if firstAvailable < 0 {
+			firstAvailable = i
+		}
+		if float64(l.players()+1) > bound {
+			continue
+		}
+		if l.CPU <= b.MaxCPU {
+			return i
+		}
+		if firstUnderBound < 0 {
+			firstUnderBound = i
+		}
+	}
+
+	if firstUnderBound >= 0 {
+		return firstUnderBound
+	}
+	if firstAvailable >= 0 {
+		return firstAvailable
+	}
+	return 0
+}
@@ -3,6 +3,8 @@ package router
 import (
 	"encoding/binary"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/godyy/ggs/internal/base/consts"
 	"github.com/godyy/ggskit/infra/cluster"
@@ -11,30 +13,70 @@ import (
 
 // NodeSelector Agent 专用的节点选择封装，内部组合通用的 router.Selector。
 // 负责：
-//   - 仅接收和维护 Game 节点，并按 Category/ServerId 分组
+//   - 仅接收和维护 Game 节点，并按 Category/ServerId 分组；托管多个服务器的节点同时属于多个分组
 //   - 承接 Center 的增量/全量事件并更新路由
 //   - 提供面向业务的查询方法（如按 serverId 选 Game 节点）
+//   - 结合节点负载进行有界负载选择（见 Balance）
 type NodeSelector struct {
 	base noderouter.Selector
+
+	mtx     sync.Mutex
+	nodes   map[string]int64               // 节点ID -> 节点自身的 ServerId
+	servers map[string][]int64             // 节点ID -> 节点托管的全部 ServerId，来自节点元数据
+	members map[string]map[string]struct{} // 分组 -> 节点ID集合
+	balance Balance                        // 负载均衡配置
+	loads   map[string]*nodeLoad           // 节点ID -> 负载
 }
 
 // NewNodeSelector 创建 NodeSelector。
 // base 为通用路由实现（如 RendezvousSelector）。
 func NewNodeSelector(base noderouter.Selector) *NodeSelector {
-	return &NodeSelector{base: base}
+	return &NodeSelector{
+		base:    base,
+		nodes:   make(map[string]int64),
+		servers: make(map[string][]int64),
+		members: make(map[string]map[string]struct{}),
+		balance: DefaultBalance(),
+		loads:   make(map[string]*nodeLoad),
+	}
+}
+
+// SetBalance 设置负载均衡配置，未设置的字段使用默认值。
+func (s *NodeSelector) SetBalance(b Balance) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.balance = b.withDefaults()
 }
 
 // SetNodes 接收节点列表并进行分组，随后调用底层路由的 Set。
 // all=true 表示全量替换所有分组；否则仅替换传入分组。
 func (s *NodeSelector) SetNodes(nodes []*cluster.Node, all bool) {
 	groups := make(map[string][]string)
+
+	s.mtx.Lock()
+	if all {
+		s.nodes = make(map[string]int64, len(nodes))
+		s.members = make(map[string]map[string]struct{})
+	}
 	for _, n := range nodes {
 		if !acceptNode(n) {
 			continue
 		}
-		g := makeGroup(n.Category, n.ServerId)
-		groups[g] = append(groups[g], n.GetNodeId())
+		id := n.GetNodeId()
+		s.nodes[id] = n.ServerId
+		for _, g := range s.nodeGroups(id, n.ServerId) {
+			groups[g] = append(groups[g], id)
+		}
+	}
+	for g, ids := range groups {
+		m := make(map[string]struct{}, len(ids))
+		for _, id := range ids {
+			m[id] = struct{}{}
+		}
+		s.members[g] = m
 	}
+	s.mtx.Unlock()
+
 	s.base.Set(groups, all)
 }
 
@@ -42,30 +84,135 @@ func (s *NodeSelector) SetNodes(nodes []*cluster.Node, all bool) {
 // 要求同一分组内保持事件顺序。
 func (s *NodeSelector) UpdateEvents(events []cluster.NodeEvent) {
 	updates := make(map[string][]noderouter.UpdateOp)
+
+	s.mtx.Lock()
 	for _, ev := range events {
 		if !acceptNode(ev.Node) {
 			continue
 		}
-		g := makeGroup(ev.Node.Category, ev.Node.ServerId)
 		id := ev.Node.GetNodeId()
 		switch ev.Type {
 		case cluster.NodeEventAdd:
-			updates[g] = append(updates[g], noderouter.UpdateOp{Type: noderouter.UpdateAdd, IDs: []string{id}})
+			s.nodes[id] = ev.Node.ServerId
+			for _, g := range s.nodeGroups(id, ev.Node.ServerId) {
+				s.addMember(updates, g, id)
+			}
 		case cluster.NodeEventDel:
-			updates[g] = append(updates[g], noderouter.UpdateOp{Type: noderouter.UpdateRemove, IDs: []string{id}})
+			serverId, ok := s.nodes[id]
+			if !ok {
+				serverId = ev.Node.ServerId
+			}
+			for _, g := range s.nodeGroups(id, serverId) {
+				s.removeMember(updates, g, id)
+			}
+			delete(s.nodes, id)
 		}
 	}
+	s.mtx.Unlock()
+
 	if len(updates) > 0 {
 		s.base.Update(updates)
 	}
 }
 
+// SetNodeServers 全量设置节点托管的服务器，节点ID -> ServerId 列表，通常来自节点元数据。
+// 托管多个服务器的节点同时加入各服务器的分组；未设置的节点仅属于自身 ServerId 的分组。
+func (s *NodeSelector) SetNodeServers(servers map[string][]int64) {
+	updates := make(map[string][]noderouter.UpdateOp)
+
+	s.mtx.Lock()
+	prev := make(map[string][]string, len(s.nodes))
+	for id, serverId := range s.nodes {
+		prev[id] = s.nodeGroups(id, serverId)
+	}
+	s.servers = servers
+	for id, serverId := range s.nodes {
+		groups := s.nodeGroups(id, serverId)
+		for _, g := range prev[id] {
+			if !slices.Contains(groups, g) {
+				s.removeMember(updates, g, id)
+			}
+		}
+		for _, g := range groups {
+			if !slices.Contains(prev[id], g) {
+				s.addMember(updates, g, id)
+			}
+		}
+	}
+	s.mtx.Unlock()
+
+	if len(updates) > 0 {
+		s.base.Update(updates)
+	}
+}
+
+// nodeGroups 返回节点所属的分组：自身 ServerId 的分组及其托管的各服务器分组。
+func (s *NodeSelector) nodeGroups(id string, serverId int64) []string {
+	groups := []string{makeGroup(consts.NodeGame, serverId)}
+	for _, sid := range s.servers[id] {
+		if g := makeGroup(consts.NodeGame, sid); !slices.Contains(groups, g) {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// addMember 将节点加入分组，并记录对应的路由更新。
+func (s *NodeSelector) addMember(updates map[string][]noderouter.UpdateOp, g, id string) {
+	updates[g] = append(updates[g], noderouter.UpdateOp{Type: noderouter.UpdateAdd, IDs: []string{id}})
+	if s.members[g] == nil {
+		s.members[g] = make(map[string]struct{})
+	}
+	s.members[g][id] = struct{}{}
+}
+
+// removeMember 将节点移出分组，并记录对应的路由更新。
+func (s *NodeSelector) removeMember(updates map[string][]noderouter.UpdateOp, g, id string) {
+	updates[g] = append(updates[g], noderouter.UpdateOp{Type: noderouter.UpdateRemove, IDs: []string{id}})
+	delete(s.members[g], id)
+}
+
 // PickGame 按 serverId 选择 Game 组中的前 n 个候选节点ID；n<=1 返回单个候选。
+//
+// 候选顺序以 playerId 的 rendezvous 哈希顺序为基础，首选节点按有界负载规则确定：
+// 沿哈希顺序取第一个未排空、CPU 未超限且负载不超过 LoadFactor 倍平均负载的节点，
+// 其余候选保持哈希顺序以供重试。负载均衡时首选节点即哈希首选，玩家落点保持稳定。
+// 选中的首选节点会在本地计入一次负载，直至该节点下一次发布负载。
 func (s *NodeSelector) PickGame(serverId, playerId int64, n int) []string {
 	group := makeGroup(consts.NodeGame, serverId)
 	key := [8]byte{}
 	binary.NativeEndian.PutUint64(key[:], uint64(playerId))
-	return s.base.Pick(group, key[:], n)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	size := len(s.members[group])
+	if size <= 1 {
+		return s.base.Pick(group, key[:], n)
+	}
+
+	ids := s.base.Pick(group, key[:], size)
+	if len(ids) == 0 {
+		return ids
+	}
+	i := s.balance.pick(ids, s.loads)
+	if i > 0 {
+		// 首选节点移至最前，其余保持哈希顺序。
+		chosen := ids[i]
+		copy(ids[1:i+1], ids[:i])
+		ids[0] = chosen
+	}
+	if l := s.loads[ids[0]]; l != nil {
+		l.picked++
+	}
+
+	if n < 1 {
+		n = 1
+	}
+	if n < len(ids) {
+		ids = ids[:n]
+	}
+	return ids
 }
 
 func acceptNode(node *cluster.Node) bool {
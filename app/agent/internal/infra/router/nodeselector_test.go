@@ -1,6 +1,9 @@
 package router
 
 import (
+	"encoding/binary"
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/godyy/ggs/internal/base/consts"
@@ -84,3 +87,150 @@ func TestNodeSelector_UpdateEventsIgnoreNonGame(t *testing.T) {
 	candidates := selector.PickGame(101, 12345, 10)
 	assert.Equal(t, []string{cluster.MakeNodeID(consts.NodeGame, "game1")}, candidates)
 }
+
+// newBalanceTestSelector 创建包含 count 个 Game 节点(ServerId=101)的 NodeSelector。
+func newBalanceTestSelector(count int) (*NodeSelector, []string) {
+	selector := NewNodeSelector(noderouter.NewRendezvousSelector())
+	nodes := make([]*cluster.Node, count)
+	ids := make([]string, count)
+	for i := range nodes {
+		nodes[i] = &cluster.Node{Category: consts.NodeGame, Name: fmt.Sprintf("n%d", i+1), ServerId: 101}
+		ids[i] = nodes[i].GetNodeId()
+	}
+	selector.SetNodes(nodes, true)
+	return selector, ids
+}
+
+// pickDistribution 为 [start, start+count) 的玩家选择节点，返回各节点分配数量。
+func pickDistribution(selector *NodeSelector, start, count int64) map[string]int {
+	dist := make(map[string]int)
+	for id := start; id < start+count; id++ {
+		dist[selector.PickGame(101, id, 1)[0]]++
+	}
+	return dist
+}
+
+func TestNodeSelector_PickGame_StableWhenBalanced(t *testing.T) {
+	selector, ids := newBalanceTestSelector(4)
+	loads := make(map[string]NodeLoad)
+	for _, id := range ids {
+		loads[id] = NodeLoad{Players: 1000, CPU: 0.3, UpdateAt: 1}
+	}
+	selector.SetLoads(loads)
+
+	// 负载均衡时，绝大多数玩家落在哈希首选节点。
+	base := noderouter.NewRendezvousSelector()
+	base.Set(map[string][]string{makeGroup(consts.NodeGame, 101): ids}, true)
+	const players = 2000
+	stable := 0
+	for id := int64(0); id < players; id++ {
+		key := [8]byte{}
+		binary.NativeEndian.PutUint64(key[:], uint64(id))
+		if selector.PickGame(101, id, 1)[0] == base.Pick(makeGroup(consts.NodeGame, 101), key[:], 1)[0] {
+			stable++
+		}
+	}
+	t.Logf("stable placement %d/%d", stable, players)
+	assert.GreaterOrEqual(t, stable, players*9/10)
+}
+
+func TestNodeSelector_PickGame_BoundedDistribution(t *testing.T) {
+	selector, ids := newBalanceTestSelector(4)
+	loads := make(map[string]NodeLoad)
+	for _, id := range ids {
+		loads[id] = NodeLoad{UpdateAt: 1}
+	}
+	selector.SetLoads(loads)
+
+	const players = 10000
+	dist := pickDistribution(selector, 0, players)
+	t.Logf("distribution %v", dist)
+	bound := int(math.Ceil(defaultLoadFactor * players / float64(len(ids))))
+	for _, id := range ids {
+		assert.LessOrEqual(t, dist[id], bound+1, id)
+	}
+}
+
+func TestNodeSelector_PickGame_AvoidOverloaded(t *testing.T) {
+	selector, ids := newBalanceTestSelector(4)
+	selector.SetLoads(map[string]NodeLoad{
+		ids[0]: {Players: 5000, UpdateAt: 1},
+		ids[1]: {Players: 1000, UpdateAt: 1},
+		ids[2]: {Players: 1000, UpdateAt: 1},
+		ids[3]: {Players: 1000, UpdateAt: 1},
+	})
+
+	dist := pickDistribution(selector, 0, 1000)
+	t.Logf("distribution %v", dist)
+	assert.Zero(t, dist[ids[0]])
+	for _, id := range ids[1:] {
+		assert.Greater(t, dist[id], 200, id)
+	}
+
+	// 节点发布新的负载后，本地计入的选择次数清零。
+	selector.SetLoads(map[string]NodeLoad{
+		ids[0]: {Players: 1000, UpdateAt: 2},
+		ids[1]: {Players: 1000, UpdateAt: 2},
+		ids[2]: {Players: 1000, UpdateAt: 2},
+		ids[3]: {Players: 1000, UpdateAt: 2},
+	})
+	dist = pickDistribution(selector, 1000, 1000)
+	t.Logf("distribution after publish %v", dist)
+	assert.Greater(t, dist[ids[0]], 200)
+}
+
+func TestNodeSelector_PickGame_SkipDrainingAndBusy(t *testing.T) {
+	selector, ids := newBalanceTestSelector(3)
+	selector.SetLoads(map[string]NodeLoad{
+		ids[0]: {Draining: true, UpdateAt: 1},
+		ids[1]: {CPU: 0.95, UpdateAt: 1},
+		ids[2]: {CPU: 0.2, UpdateAt: 1},
+	})
+
+	dist := pickDistribution(selector, 0, 1000)
+	t.Logf("distribution %v", dist)
+	assert.Zero(t, dist[ids[0]])
+	assert.Greater(t, dist[ids[2]], dist[ids[1]])
+
+	// 首选之外的候选仍保留，供重试使用。
+	candidates := selector.PickGame(101, 12345, 3)
+	assert.ElementsMatch(t, ids, candidates)
+	assert.NotEqual(t, ids[0], candidates[0])
+
+	// 全部排空时仍返回候选。
+	selector.SetLoads(map[string]NodeLoad{
+		ids[0]: {Draining: true, UpdateAt: 2},
+		ids[1]: {Draining: true, UpdateAt: 2},
+		ids[2]: {Draining: true, UpdateAt: 2},
+	})
+	assert.Len(t, selector.PickGame(101, 12345, 1), 1)
+}
+
+func TestNodeSelector_MultiServerNode(t *testing.T) {
+	selector := NewNodeSelector(noderouter.NewRendezvousSelector())
+	selector.SetNodes([]*cluster.Node{
+		{Category: consts.NodeGame, Name: "101", ServerId: 101},
+		{Category: consts.NodeGame, Name: "102", ServerId: 102},
+	}, true)
+	node101 := cluster.MakeNodeID(consts.NodeGame, "101")
+	node102 := cluster.MakeNodeID(consts.NodeGame, "102")
+
+	// 节点 101 额外托管 103、104。
+	selector.SetNodeServers(map[string][]int64{node101: {101, 103, 104}})
+	assert.Equal(t, []string{node101}, selector.PickGame(103, 1, 10))
+	assert.Equal(t, []string{node101}, selector.PickGame(104, 1, 10))
+	assert.Equal(t, []string{node101}, selector.PickGame(101, 1, 10))
+
+	// 103 改由节点 102 托管。
+	selector.SetNodeServers(map[string][]int64{node101: {101, 104}, node102: {102, 103}})
+	assert.Equal(t, []string{node102}, selector.PickGame(103, 1, 10))
+	assert.Equal(t, []string{node101}, selector.PickGame(104, 1, 10))
+
+	// 节点下线后移出其托管的全部分组。
+	selector.UpdateEvents([]cluster.NodeEvent{
+		{Type: cluster.NodeEventDel, Node: &cluster.Node{Category: consts.NodeGame, Name: "101", ServerId: 101}},
+	})
+	assert.Empty(t, selector.PickGame(104, 1, 10))
+	assert.Empty(t, selector.PickGame(101, 1, 10))
+	assert.Equal(t, []string{node102}, selector.PickGame(103, 1, 10))
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRecordDisabled 未启用录制.
+var ErrRecordDisabled = errors.New("record disabled")
+
+// RecordInfo 玩家数据包录制信息.
+type RecordInfo struct {
+	PlayerId  int64     `json:"player_id"`  // 角色ID.
+	Path      string    `json:"path"`       // 录制文件路径.
+	StartTime time.Time `json:"start_time"` // 开始录制时间.
+	Packets   int64     `json:"packets"`    // 已录制的数据包数量.
+}
+
+var (
+	// StartRecord 开始录制玩家数据包, 已在录制中时返回当前录制信息.
+	StartRecord func(playerId int64) (RecordInfo, error)
+
+	// StopRecord 停止录制玩家数据包, 未在录制中时返回 false.
+	StopRecord func(playerId int64) (RecordInfo, bool)
+
+	// ListRecords 列出录制中的玩家.
+	ListRecords func() []RecordInfo
+)
@@ -3,11 +3,15 @@ package main
 import (
 	"github.com/godyy/ggs/app/agent/internal/app"
 	_ "github.com/godyy/ggs/app/agent/internal/infra/agent"
+	"github.com/godyy/ggskit/base/flags"
 	"github.com/godyy/ggskit/utils"
 )
 
 func main() {
-	app.Start()
+	flags.Parse()
+	app.Start(nil)
+	flags.Reset()
+	app.ListenDrainSignal()
 	utils.ListenShutdown()
 	app.Stop()
 }
@@ -2,7 +2,7 @@ package conf
 
 var (
 	LoginURLRoot string // login url root
-	AgentAddr    string // server address
+	AgentAddr    string // fallback agent address
 	SignKeyPath  string // auth private key path
 	Mode         string // mode: client or robot
 )
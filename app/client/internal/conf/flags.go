@@ -12,9 +12,6 @@ func applyFlags() {
 		log.Fatal("-login-url-root is empty")
 	}
 	AgentAddr, _ = flags.GetValue[string]("agent-addr")
-	if AgentAddr == "" {
-		log.Fatal("-agent-addr is empty")
-	}
 	Mode, _ = flags.GetValue[string]("mode")
 	if Mode == "" {
 		log.Fatal("-mode is empty")
@@ -27,7 +24,7 @@ func applyFlags() {
 
 func init() {
 	flags.String("login-url-root", "", "login url root")
-	flags.String("agent-addr", "", "agent address")
+	flags.String("agent-addr", "", "fallback agent address, used when login returns none")
 	flags.String("sign-key-path", "", "sign key path")
 	flags.String("mode", "client", "client or robot")
 	flags.AddParsedFunc(applyFlags)
@@ -1,26 +1,12 @@
 package client
 
 import (
-	"crypto/rand"
-	"errors"
 	"fmt"
-	"log"
-	"net"
-	"reflect"
+	"slices"
 	"sync"
-	"sync/atomic"
-	"time"
 
-	"github.com/godyy/ggs/app/client/internal/conf"
 	"github.com/godyy/ggs/app/client/internal/mode"
-	inet "github.com/godyy/ggs/app/client/internal/net"
-	"github.com/godyy/ggs/internal/base/consts"
-	icrypto "github.com/godyy/ggs/internal/base/crypto"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
-	pbcom "github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
-	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
 	pkgerrors "github.com/pkg/errors"
-	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -29,14 +15,15 @@ const (
 	statePlay  = 2 // 游玩状态. 解析命令行输入.
 )
 
+// Client 交互式客户端, 可同时持有多个玩家会话, 命令默认作用于当前会话.
 type Client struct {
-	mtx        sync.Mutex
-	state      int32              // 状态
-	agentToken string             // 网关令牌
-	stream     *inet.Stream       // stream
-	seqIncr    uint32             // seq自增键
-	reqSeq     uint32             // 请求Seq
-	chResp     chan proto.Message // 请求响应
+	mtx         sync.Mutex
+	state       int32            // 状态
+	sessions    map[int]*session // 会话编号 -> 会话
+	sessionIncr int              // 会话编号自增键
+	active      *session         // 当前会话
+	pending     *session         // 启动时正在登录的会话
+	watches     *watchSet        // 关注的消息
 }
 
 func init() {
@@ -44,7 +31,8 @@ func init() {
 	mode.RegisterMode("client", func() mode.Mode {
 		applyFlags()
 		return &Client{
-			chResp: make(chan proto.Message, 1),
+			sessions: make(map[int]*session),
+			watches:  newWatchSet(),
 		}
 	})
 }
@@ -56,7 +44,9 @@ func (c *Client) Start() {
 
 // Stop 停止
 func (c *Client) Stop() {
-
+	for _, s := range c.sessionList() {
+		s.close()
+	}
 }
 
 // changeState 改变状态
@@ -71,146 +61,111 @@ func (c *Client) loop() {
 	}
 }
 
-// connectAgent 连接网关.
-func (c *Client) connectAgent() error {
-	// 建立连接
-	conn, err := net.Dial("tcp", conf.AgentAddr)
-	if err != nil {
-		return err
-	}
+// newSession 创建会话, 尚未加入会话列表.
+func (c *Client) newSession(uid string, serverId int64) *session {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.sessionIncr++
+	return newSession(c, c.sessionIncr, uid, serverId)
+}
 
-	// 交换密钥
-	sessionKey, err := exchangeSecretKey(conn)
-	if err != nil {
-		conn.Close()
-		return err
+// openSession 登录新会话并切换为当前会话.
+func (c *Client) openSession(uid string, serverId, characterId int64, create bool) (*session, error) {
+	s := c.newSession(uid, serverId)
+	if err := s.prepare(characterId, create); err != nil {
+		return nil, err
 	}
-
-	// 创建stream
-	c.stream, err = inet.NewStream(conn, sessionKey, c)
-	if err != nil {
-		conn.Close()
-		return err
+	if err := s.login(); err != nil {
+		return nil, err
 	}
-	return nil
+	c.addSession(s)
+	return s, nil
 }
 
-// genSeq 生成Seq.
-func (c *Client) genSeq() uint32 {
-	c.seqIncr++
-	return c.seqIncr
+// addSession 加入会话列表并切换为当前会话.
+func (c *Client) addSession(s *session) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.sessions[s.id] = s
+	c.active = s
 }
 
-// sendReq 发送请求, 并等待响应.
-func (c *Client) sendReq(msg proto.Message) (proto.Message, error) {
+// removeSession 移除会话, 若为当前会话则切换到编号最小的会话.
+func (c *Client) removeSession(s *session) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
-
-	if c.stream == nil {
-		return nil, pkgerrors.New("not connected")
-	}
-
-	// 清空
-	select {
-	case <-c.chResp:
-	default:
-	}
-
-	// 发送消息.
-	seq := c.genSeq()
-	atomic.StoreUint32(&c.reqSeq, seq)
-	if err := c.stream.SendReq(seq, msg); err != nil {
-		atomic.CompareAndSwapUint32(&c.reqSeq, seq, 0)
-		return nil, err
-	}
-
-	// 等待回复.
-	timer := time.NewTimer(5 * time.Second)
-	select {
-	case rsp := <-c.chResp:
-		return rsp, nil
-	case <-timer.C:
-		return nil, errors.New("timeout")
+	if c.sessions[s.id] != s {
+		return
+	}
+	delete(c.sessions, s.id)
+	if c.active == s {
+		c.active = nil
+		if ids := c.sortedIds(); len(ids) > 0 {
+			c.active = c.sessions[ids[0]]
+		}
 	}
 }
 
-// sendReq 泛型封装
-func sendReq[Req, Resp proto.Message](c *Client, req Req) (r Resp, err error) {
-	var resp proto.Message
-	resp, err = c.sendReq(req)
-	if err != nil {
-		return r, err
-	}
-
-	var ok bool
-	r, ok = resp.(Resp)
+// getSession 获取指定编号的会话.
+func (c *Client) getSession(id int) (*session, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	s, ok := c.sessions[id]
 	if !ok {
-		if respErr, ok := resp.(*pbcom.Error); ok {
-			return r, fmt.Errorf("%+v", respErr)
-		}
-		return r, fmt.Errorf("resp is %T", resp)
+		return nil, fmt.Errorf("session #%d not found", id)
 	}
-
-	return
+	return s, nil
 }
 
-func (c *Client) tick() {
-	tickHeartbeat := time.NewTicker(consts.HeartbeatInterval)
-	for {
-		select {
-		case <-tickHeartbeat.C:
-			log.Println("heartbeat.")
-			if _, err := sendReq[*pbc2s.HeartbeatReq, *pbc2s.HeartbeatResp](c, &pbc2s.HeartbeatReq{}); err != nil {
-				log.Fatalf("send heartbeat failed, %v", err)
-			}
-		}
+// activeSession 获取当前会话.
+func (c *Client) activeSession() (*session, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.active == nil {
+		return nil, pkgerrors.New("no active session, open one first")
 	}
+	return c.active, nil
 }
 
-// OnStreamMsg 处理流消息.
-func (c *Client) OnStreamMsg(msg inet.Msg) {
-	switch msg.Pt {
-	case codecc2s.PtResp:
-		log.Printf("receive resp, seq=%d, %s{%+v}", msg.Seq, reflect.TypeOf(msg.Msg).Elem().Name(), msg.Msg)
-		if atomic.CompareAndSwapUint32(&c.reqSeq, msg.Seq, 0) {
-			c.chResp <- msg.Msg
-		}
-	case codecc2s.PtPush:
-		log.Printf("receive push, %s{%+v}", reflect.TypeOf(msg.Msg).Elem().Name(), msg.Msg)
+// useSession 切换当前会话.
+func (c *Client) useSession(id int) (*session, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	s, ok := c.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("session #%d not found", id)
 	}
+	c.active = s
+	return s, nil
 }
 
-// OnStreamClose 处理流关闭事件.
-func (c *Client) OnStreamClose(err error) {
-	log.Fatalf("stream closed: %v", err)
-}
-
-// exchangeSecretKey 交换密钥
-func exchangeSecretKey(conn net.Conn) ([]byte, error) {
-	// 生成临时secret key
-	tmpKey := make([]byte, 16)
-	rand.Read(tmpKey)
-
-	// 创建加密器
-	entryptor, err := icrypto.CreateRSAEncryptor()
-	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "create encryptor failed")
+// sessionList 返回按编号排序的会话列表.
+func (c *Client) sessionList() []*session {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	list := make([]*session, 0, len(c.sessions))
+	for _, id := range c.sortedIds() {
+		list = append(list, c.sessions[id])
 	}
+	return list
+}
 
-	// 发送临时secret key
-	if err := inet.PacketReadWriter.EncryptAndWritePacket(conn, tmpKey, entryptor); err != nil {
-		return nil, pkgerrors.WithMessage(err, "encrypt and write tmpKey failed")
+// sortedIds 返回排序后的会话编号, 须持有锁.
+func (c *Client) sortedIds() []int {
+	ids := make([]int, 0, len(c.sessions))
+	for id := range c.sessions {
+		ids = append(ids, id)
 	}
+	slices.Sort(ids)
+	return ids
+}
 
-	// 接收会话密钥
-	sessionKeyDecryptor, err := icrypto.CreateAESCrypto(tmpKey)
-	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "create sessionKey decryptor failed")
-	}
-	sessionKey, err := inet.PacketReadWriter.ReadAndDecryptPacket(conn, sessionKeyDecryptor)
-	if err != nil {
-		return nil, pkgerrors.WithMessage(err, "read and decrypt sessionKey failed")
+// prompt 命令行提示符, 显示当前会话.
+func (c *Client) prompt() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.active == nil {
+		return "client> "
 	}
-
-	return sessionKey, nil
+	return fmt.Sprintf("client#%d(%s@%d)> ", c.active.id, c.active.uid, c.active.serverId)
 }
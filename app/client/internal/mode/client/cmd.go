@@ -5,6 +5,7 @@ import (
 	"log"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/chzyer/readline"
@@ -15,9 +16,10 @@ import (
 	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
-type cmdExecFunc func(c *cmd, cli *Client, args string) bool
+// cmdExecFunc 命令执行逻辑, sess 为命令作用的会话, 可能为 nil.
+type cmdExecFunc func(c *cmd, cli *Client, sess *session, args string) bool
 
-type cmdExec func(cli *Client, args string) bool
+type cmdExec func(cli *Client, sess *session, args string) bool
 
 type cmd struct {
 	name          string                            // 命令名称
@@ -27,8 +29,8 @@ type cmd struct {
 	autoCompleter readline.PrefixCompleterInterface // 自动补全
 }
 
-func (c *cmd) execute(cli *Client, args string) bool {
-	return c.exec(c, cli, args)
+func (c *cmd) execute(cli *Client, sess *session, args string) bool {
+	return c.exec(c, cli, sess, args)
 }
 
 var (
@@ -51,10 +53,39 @@ func registerCmd(c ...*cmd) {
 	}
 }
 
-func getCmdExec(name string) func(cli *Client, args string) bool {
+func getCmdExec(name string) cmdExec {
 	return cmdExecMap[name]
 }
 
+// execLine 以指定会话执行一行命令, 返回是否退出.
+func execLine(cli *Client, sess *session, line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+
+	// 提取命令和参数部分
+	name, args, _ := strings.Cut(line, " ")
+	args = strings.TrimSpace(args)
+
+	// 获取并执行命令
+	exec := getCmdExec(name)
+	if exec == nil {
+		log.Printf("unknown command: %s", name)
+		return false
+	}
+	return exec(cli, sess, args)
+}
+
+// requireSession 检查命令是否有作用的会话.
+func requireSession(sess *session) bool {
+	if sess == nil {
+		log.Println("no active session, open one first")
+		return false
+	}
+	return true
+}
+
 func cmdAllUsage() {
 	log.Println("commands: name..desc..usage")
 	for _, cmd := range cmdList {
@@ -72,7 +103,7 @@ func init() {
 		&cmd{
 			name: "help|h|?",
 			desc: "print commands",
-			exec: func(_ *cmd, c *Client, args string) bool {
+			exec: func(_ *cmd, c *Client, _ *session, args string) bool {
 				cmdAllUsage()
 				return false
 			},
@@ -83,7 +114,7 @@ func init() {
 		&cmd{
 			name: "exit|quit|q",
 			desc: "exit client",
-			exec: func(_ *cmd, c *Client, args string) bool {
+			exec: func(_ *cmd, c *Client, _ *session, args string) bool {
 				return true
 			},
 			autoCompleter: readline.PcItemDynamic(func(s string) []string {
@@ -95,7 +126,11 @@ func init() {
 			desc: "send request message to server",
 			// usage:         "sendreq msgname[Req]" + cmdSendReqArgsSp + "msgjsonbody",
 			usage: `sendreq msgname {key1:value1[,key2:value2,...]}`,
-			exec: func(c *cmd, cli *Client, args string) bool {
+			exec: func(c *cmd, cli *Client, sess *session, args string) bool {
+				if !requireSession(sess) {
+					return false
+				}
+
 				// parts := strings.Split(args, cmdSendReqArgsSp)
 				// if len(parts) < 2 {
 				// 	cmdUsage(c)
@@ -110,7 +145,7 @@ func init() {
 
 				msg := parts[1]
 				body := parts[2]
-				log.Printf("name:%s\t json:%s", msg, body)
+				sess.logf("name:%s\t json:%s", msg, body)
 				if !strings.HasSuffix(msg, "Req") {
 					msg = msg + "Req"
 				}
@@ -134,17 +169,218 @@ func init() {
 					return false
 				}
 
-				resp, err := cli.sendReq(req)
+				resp, err := sess.sendReq(req)
 				if err != nil {
-					log.Println(err)
+					sess.logf("%v", err)
 					return false
 				}
 
-				log.Printf("%s:{%+v}", reflect.TypeOf(resp).Elem().Name(), resp)
+				sess.logf("%s:{%+v}", reflect.TypeOf(resp).Elem().Name(), resp)
 				return false
 			},
 			autoCompleter: genSendreqAutoCompleter(),
 		},
+		&cmd{
+			name:  "me",
+			desc:  "print mirrored player info",
+			usage: "me",
+			exec: func(_ *cmd, cli *Client, sess *session, args string) bool {
+				if !requireSession(sess) {
+					return false
+				}
+				sess.logf("%s %s", sess, sess.mirror.me())
+				return false
+			},
+			autoCompleter: readline.PcItem("me"),
+		},
+		&cmd{
+			name:  "items",
+			desc:  "print mirrored items",
+			usage: "items",
+			exec: func(_ *cmd, cli *Client, sess *session, args string) bool {
+				if !requireSession(sess) {
+					return false
+				}
+				lines := sess.mirror.itemList()
+				sess.logf("items: %d", len(lines))
+				for _, line := range lines {
+					log.Println("\t" + line)
+				}
+				return false
+			},
+			autoCompleter: readline.PcItem("items"),
+		},
+		&cmd{
+			name:  "watch",
+			desc:  "toggle printing a message in full when received, list watched messages without args",
+			usage: "watch [msgname]",
+			exec: func(c *cmd, cli *Client, _ *session, args string) bool {
+				name := strings.TrimSpace(args)
+				if name == "" {
+					log.Printf("watching: %v", cli.watches.list())
+					return false
+				}
+				fullName := protoreflect.FullName("c2s." + name)
+				if _, err := protoregistry.GlobalTypes.FindMessageByName(fullName); err != nil {
+					log.Printf("message %s not found", fullName)
+					return false
+				}
+				if cli.watches.toggle(name) {
+					log.Printf("watch %s", name)
+				} else {
+					log.Printf("unwatch %s", name)
+				}
+				return false
+			},
+			autoCompleter: genWatchAutoCompleter(),
+		},
+		&cmd{
+			name:  "sessions|ls",
+			desc:  "list sessions, * marks the active one",
+			usage: "sessions",
+			exec: func(_ *cmd, cli *Client, sess *session, args string) bool {
+				list := cli.sessionList()
+				log.Printf("sessions: %d", len(list))
+				for _, s := range list {
+					mark := " "
+					if s == sess {
+						mark = "*"
+					}
+					log.Printf("\t%s %s", mark, s)
+				}
+				return false
+			},
+			autoCompleter: readline.PcItemDynamic(func(s string) []string {
+				return []string{"sessions", "ls"}
+			}),
+		},
+		&cmd{
+			name:  "open",
+			desc:  "open a session and make it active, logs in the first character on the server or creates one",
+			usage: "open uid serverid [characterid]",
+			exec: func(c *cmd, cli *Client, _ *session, args string) bool {
+				fields := strings.Fields(args)
+				if len(fields) < 2 || len(fields) > 3 {
+					cmdUsage(c)
+					return false
+				}
+				serverId, err := strconv.ParseInt(fields[1], 10, 64)
+				if err != nil {
+					cmdUsage(c)
+					return false
+				}
+				var characterId int64
+				if len(fields) == 3 {
+					if characterId, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+						cmdUsage(c)
+						return false
+					}
+				}
+				s, err := cli.openSession(fields[0], serverId, characterId, false)
+				if err != nil {
+					log.Printf("open session failed, %v", err)
+					return false
+				}
+				log.Printf("session opened, %s", s)
+				return false
+			},
+			autoCompleter: readline.PcItem("open"),
+		},
+		&cmd{
+			name:  "create",
+			desc:  "create a new character on the server and open a session with it",
+			usage: "create uid serverid",
+			exec: func(c *cmd, cli *Client, _ *session, args string) bool {
+				fields := strings.Fields(args)
+				if len(fields) != 2 {
+					cmdUsage(c)
+					return false
+				}
+				serverId, err := strconv.ParseInt(fields[1], 10, 64)
+				if err != nil {
+					cmdUsage(c)
+					return false
+				}
+				s, err := cli.openSession(fields[0], serverId, 0, true)
+				if err != nil {
+					log.Printf("create session failed, %v", err)
+					return false
+				}
+				log.Printf("session opened, %s", s)
+				return false
+			},
+			autoCompleter: readline.PcItem("create"),
+		},
+		&cmd{
+			name:  "use",
+			desc:  "switch the active session",
+			usage: "use sessionid",
+			exec: func(c *cmd, cli *Client, _ *session, args string) bool {
+				id, err := strconv.Atoi(strings.TrimPrefix(args, "#"))
+				if err != nil {
+					cmdUsage(c)
+					return false
+				}
+				s, err := cli.useSession(id)
+				if err != nil {
+					log.Println(err)
+					return false
+				}
+				log.Printf("active session %s", s)
+				return false
+			},
+			autoCompleter: readline.PcItem("use"),
+		},
+		&cmd{
+			name:  "as",
+			desc:  "run a command as the given session without switching",
+			usage: "as sessionid command [args]",
+			exec: func(c *cmd, cli *Client, _ *session, args string) bool {
+				idStr, line, ok := strings.Cut(args, " ")
+				if !ok {
+					cmdUsage(c)
+					return false
+				}
+				id, err := strconv.Atoi(strings.TrimPrefix(idStr, "#"))
+				if err != nil {
+					cmdUsage(c)
+					return false
+				}
+				s, err := cli.getSession(id)
+				if err != nil {
+					log.Println(err)
+					return false
+				}
+				return execLine(cli, s, line)
+			},
+			autoCompleter: readline.PcItem("as"),
+		},
+		&cmd{
+			name:  "close",
+			desc:  "close a session, the active one without args",
+			usage: "close [sessionid]",
+			exec: func(c *cmd, cli *Client, sess *session, args string) bool {
+				if args != "" {
+					id, err := strconv.Atoi(strings.TrimPrefix(args, "#"))
+					if err != nil {
+						cmdUsage(c)
+						return false
+					}
+					if sess, err = cli.getSession(id); err != nil {
+						log.Println(err)
+						return false
+					}
+				}
+				if !requireSession(sess) {
+					return false
+				}
+				sess.close()
+				cli.removeSession(sess)
+				log.Printf("session closed, %s", sess)
+				return false
+			},
+			autoCompleter: readline.PcItem("close"),
+		},
 	)
 }
 
@@ -152,6 +388,18 @@ var (
 	cmdSendReqArgsRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*)\s+({.*})$`)
 )
 
+func genWatchAutoCompleter() readline.PrefixCompleterInterface {
+	var children []readline.PrefixCompleterInterface
+	protoregistry.GlobalTypes.RangeMessages(func(mt protoreflect.MessageType) bool {
+		name := string(mt.Descriptor().Name())
+		if mt.Descriptor().FullName().Parent() == "c2s" && (strings.HasSuffix(name, "Resp") || strings.HasSuffix(name, "Push")) {
+			children = append(children, readline.PcItem(name))
+		}
+		return true
+	})
+	return readline.PcItem("watch", children...)
+}
+
 func genSendreqAutoCompleter() readline.PrefixCompleterInterface {
 	marshalOptions := protojson.MarshalOptions{
 		EmitUnpopulated: true,
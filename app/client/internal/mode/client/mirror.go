@@ -0,0 +1,187 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcom "github.com/godyy/ggs/pkg/protocol/pb/common"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// mirror 玩家状态本地镜像, 由登录响应初始化, 随推送与响应更新,
+// 并校验响应与镜像状态是否一致.
+type mirror struct {
+	mtx             sync.Mutex
+	loggedIn        bool
+	name            string          // 昵称.
+	items           map[int32]int64 // 道具ID -> 数量.
+	inconsistencies int             // 发现的不一致次数.
+	req             proto.Message   // 等待响应的请求.
+	tag             string          // 日志标签.
+}
+
+func newMirror(tag string) *mirror {
+	return &mirror{
+		items: make(map[int32]int64),
+		tag:   tag,
+	}
+}
+
+// onLogin 由登录响应中的模块快照重置镜像.
+func (m *mirror) onLogin(resp *pbc2s.LoginResp) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.loggedIn = true
+	m.name = ""
+	m.items = make(map[int32]int64)
+	for _, snapshot := range resp.Modules {
+		switch module := snapshot.Module.(type) {
+		case *pbcom.ModuleSnapshot_Base:
+			m.name = module.Base.GetName()
+		case *pbcom.ModuleSnapshot_Items:
+			for _, item := range module.Items.GetItems() {
+				m.items[item.Id] = item.Count
+			}
+		}
+	}
+}
+
+// onPush 由推送更新镜像.
+func (m *mirror) onPush(msg proto.Message) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	switch push := msg.(type) {
+	case *pbc2s.ItemPush:
+		for _, item := range push.Items {
+			m.setItem(item.Id, item.Count)
+		}
+	case *pbc2s.PlayerBasePush:
+		m.name = push.Base.GetName()
+	}
+}
+
+// onReq 记录等待响应的请求.
+func (m *mirror) onReq(req proto.Message) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.req = req
+}
+
+// onResp 由响应更新镜像, 并校验响应与镜像状态是否一致.
+// 响应先于同一请求产生的推送到达, 因此以请求前的镜像状态校验.
+func (m *mirror) onResp(resp proto.Message) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	req := m.req
+	m.req = nil
+	switch resp := resp.(type) {
+	case *pbc2s.UseItemResp:
+		before := m.items[resp.ItemId]
+		if want := before - resp.Num; want != resp.LeftNum {
+			m.inconsistent("item %d: mirror has %d, used %d, server left %d", resp.ItemId, before, resp.Num, resp.LeftNum)
+		}
+		m.setItem(resp.ItemId, resp.LeftNum)
+	case *pbc2s.ModifyNameResp:
+		if r, ok := req.(*pbc2s.ModifyNameReq); ok && r.Name != resp.Name {
+			m.inconsistent("name: requested %q, server returns %q", r.Name, resp.Name)
+		}
+		m.name = resp.Name
+	}
+}
+
+// setItem 设置道具数量, 数量为 0 时移除, 须持有锁.
+func (m *mirror) setItem(id int32, count int64) {
+	if count <= 0 {
+		delete(m.items, id)
+		return
+	}
+	m.items[id] = count
+}
+
+// inconsistent 记录不一致, 须持有锁.
+func (m *mirror) inconsistent(format string, args ...any) {
+	m.inconsistencies++
+	log.Printf(m.tag+" [mirror] inconsistent, "+format, args...)
+}
+
+// me 返回基础信息描述.
+func (m *mirror) me() string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if !m.loggedIn {
+		return "not logged in"
+	}
+	return fmt.Sprintf("name=%q items=%d inconsistencies=%d", m.name, len(m.items), m.inconsistencies)
+}
+
+// itemList 返回按道具ID排序的道具描述.
+func (m *mirror) itemList() []string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	ids := make([]int32, 0, len(m.items))
+	for id := range m.items {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	lines := make([]string, 0, len(ids))
+	for _, id := range ids {
+		lines = append(lines, fmt.Sprintf("%d\t%d", id, m.items[id]))
+	}
+	return lines
+}
+
+// watchSet 关注的消息名集合, 关注的消息收到时完整打印, 对所有会话生效.
+type watchSet struct {
+	mtx   sync.Mutex
+	names map[string]bool
+}
+
+func newWatchSet() *watchSet {
+	return &watchSet{names: make(map[string]bool)}
+}
+
+// toggle 切换对消息的关注, 返回切换后是否关注.
+func (w *watchSet) toggle(name string) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.names[name] {
+		delete(w.names, name)
+		return false
+	}
+	w.names[name] = true
+	return true
+}
+
+// list 返回关注的消息名.
+func (w *watchSet) list() []string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	names := make([]string, 0, len(w.names))
+	for name := range w.names {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// print 关注的消息到达时完整打印.
+func (w *watchSet) print(s *session, msg proto.Message) {
+	name := string(msg.ProtoReflect().Descriptor().Name())
+	w.mtx.Lock()
+	watched := w.names[name]
+	w.mtx.Unlock()
+	if !watched {
+		return
+	}
+	b, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		s.logf("[watch] %s: %v", name, err)
+		return
+	}
+	s.logf("[watch] %s\n%s", name, strings.TrimSpace(string(b)))
+}
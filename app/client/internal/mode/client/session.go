@@ -0,0 +1,386 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/godyy/ggs/app/client/internal/conf"
+	"github.com/godyy/ggs/app/client/internal/mode/internal/utils"
+	"github.com/godyy/ggs/app/login/httpproto"
+	"github.com/godyy/ggs/internal/base/consts"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcom "github.com/godyy/ggs/pkg/protocol/pb/common"
+	sdkclient "github.com/godyy/ggs/sdk/client"
+	authjwt "github.com/godyy/ggskit/base/auth/jwt"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/godyy/ggskit/utils/ctxutils"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// session 一个玩家会话, 对应一个用户在某个服务器上登录的角色.
+type session struct {
+	cli         *Client
+	id          int    // 会话编号, 命令行中用于指定会话
+	uid         string // 用户ID
+	serverId    int64  // 服务器ID
+	characterId int64  // 角色ID
+	agentToken  string // 网关令牌
+	agentAddr   string // 网关地址
+
+	reqMtx  sync.Mutex         // 串行化请求, 同一时间只有一个请求等待响应
+	mtx     sync.Mutex         // 保护 stream
+	stream  *sdkclient.Stream  // stream
+	seqIncr uint32             // seq自增键
+	reqSeq  uint32             // 请求Seq
+	chResp  chan proto.Message // 请求响应
+	mirror  *mirror            // 玩家状态镜像
+	closed  atomic.Bool        // 是否已主动关闭
+	chClose chan struct{}      // 关闭信号
+
+	// 以下字段仅在 stream 读取协程中访问.
+	resumeTicket string // 会话恢复票据
+	recvSeq      uint32 // 登录后已收到的下行数据包数量
+}
+
+func newSession(cli *Client, id int, uid string, serverId int64) *session {
+	s := &session{
+		cli:      cli,
+		id:       id,
+		uid:      uid,
+		serverId: serverId,
+		chResp:   make(chan proto.Message, 1),
+		chClose:  make(chan struct{}),
+	}
+	s.mirror = newMirror(s.tag())
+	return s
+}
+
+// tag 会话日志标签.
+func (s *session) tag() string {
+	return fmt.Sprintf("[#%d]", s.id)
+}
+
+// String 会话描述.
+func (s *session) String() string {
+	return fmt.Sprintf("#%d uid=%s server=%d character=%d", s.id, s.uid, s.serverId, s.characterId)
+}
+
+// logf 输出带会话标签的日志.
+func (s *session) logf(format string, args ...any) {
+	log.Printf(s.tag()+" "+format, args...)
+}
+
+// prepare 通过登录服获取角色登录令牌. characterId 为 0 时选择该服务器上的
+// 第一个角色, 没有角色或 create 为 true 时创建新角色.
+func (s *session) prepare(characterId int64, create bool) error {
+	// 生成用户userToken
+	userToken, err := genUserToken(s.uid)
+	if err != nil {
+		return pkgerrors.WithMessage(err, "gen user token")
+	}
+
+	// 选择角色
+	if characterId == 0 && !create {
+		characterList, err := getCharacterList(userToken)
+		if err != nil {
+			return pkgerrors.WithMessage(err, "get character list")
+		}
+		s.logf("character list: %+v", characterList)
+		for _, v := range characterList {
+			if v.ServerID == s.serverId {
+				characterId = v.ID
+				break
+			}
+		}
+	}
+	if characterId == 0 {
+		characterId, err = createCharacter(userToken, s.serverId)
+		if err != nil {
+			return pkgerrors.WithMessage(err, "create character")
+		}
+		s.logf("character %d created on server %d", characterId, s.serverId)
+	}
+
+	// 获取登录令牌.
+	loginResp, err := loginCharacter(userToken, characterId)
+	if err != nil {
+		return pkgerrors.WithMessage(err, "login character")
+	}
+
+	s.characterId = characterId
+	s.agentToken = loginResp.Token
+	s.agentAddr = loginResp.AgentAddr
+	if s.agentAddr == "" {
+		s.agentAddr = conf.AgentAddr
+	}
+	if s.agentAddr == "" {
+		return errors.New("no agent address, login returns empty and -agent-addr is empty")
+	}
+	return nil
+}
+
+// login 连接网关并登录角色, 成功后启动心跳.
+func (s *session) login() error {
+	if err := s.connectAgent(); err != nil {
+		return pkgerrors.WithMessage(err, "connect agent")
+	}
+	s.logf("connect agent successfully.")
+
+	if _, err := sendReq[*pbc2s.LoginReq, *pbc2s.LoginResp](s, &pbc2s.LoginReq{
+		Token: s.agentToken,
+	}); err != nil {
+		s.close()
+		return pkgerrors.WithMessage(err, "login")
+	}
+	s.logf("login successfully.")
+
+	go s.tick()
+	return nil
+}
+
+// connectAgent 连接网关.
+func (s *session) connectAgent() error {
+	stream, err := sdkclient.DialStream(s.agentAddr, s)
+	if err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	s.stream = stream
+	s.mtx.Unlock()
+	return nil
+}
+
+// resume 重新连接网关并恢复会话.
+func (s *session) resume(ticket string, lastSeq uint32) {
+	if err := s.connectAgent(); err != nil {
+		s.logf("reconnect agent failed: %v", err)
+		s.cli.removeSession(s)
+		return
+	}
+
+	if _, err := sendReq[*pbc2s.ResumeReq, *pbc2s.ResumeResp](s, &pbc2s.ResumeReq{
+		Ticket:  ticket,
+		LastSeq: lastSeq,
+	}); err != nil {
+		s.logf("resume failed, %v", err)
+		s.close()
+		s.cli.removeSession(s)
+		return
+	}
+	s.logf("resume successfully, lastSeq=%d.", lastSeq)
+}
+
+// close 主动关闭会话.
+func (s *session) close() {
+	if !s.closed.CompareAndSwap(false, true) {
+		return
+	}
+	close(s.chClose)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.stream != nil {
+		s.stream.Close()
+	}
+}
+
+// genSeq 生成Seq.
+func (s *session) genSeq() uint32 {
+	s.seqIncr++
+	return s.seqIncr
+}
+
+// sendReq 发送请求, 并等待响应. 等待期间不持有 stream 锁, 不阻塞重连;
+// 连接断开时等待中的请求立即失败.
+func (s *session) sendReq(msg proto.Message) (proto.Message, error) {
+	s.reqMtx.Lock()
+	defer s.reqMtx.Unlock()
+
+	seq, err := s.send(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 等待回复.
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	select {
+	case rsp := <-s.chResp:
+		if rsp == nil {
+			return nil, errors.New("connection lost")
+		}
+		return rsp, nil
+	case <-timer.C:
+		atomic.CompareAndSwapUint32(&s.reqSeq, seq, 0)
+		return nil, errors.New("timeout")
+	case <-s.chClose:
+		return nil, pkgerrors.New("session closed")
+	}
+}
+
+// send 发送请求并登记等待响应的 seq.
+func (s *session) send(msg proto.Message) (uint32, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.stream == nil || s.closed.Load() {
+		return 0, pkgerrors.New("not connected")
+	}
+
+	// 清空
+	select {
+	case <-s.chResp:
+	default:
+	}
+
+	seq := s.genSeq()
+	s.mirror.onReq(msg)
+	atomic.StoreUint32(&s.reqSeq, seq)
+	if err := s.stream.SendReq(seq, msg); err != nil {
+		atomic.CompareAndSwapUint32(&s.reqSeq, seq, 0)
+		return 0, err
+	}
+	return seq, nil
+}
+
+// sendReq 泛型封装
+func sendReq[Req, Resp proto.Message](s *session, req Req) (r Resp, err error) {
+	var resp proto.Message
+	resp, err = s.sendReq(req)
+	if err != nil {
+		return r, err
+	}
+
+	var ok bool
+	r, ok = resp.(Resp)
+	if !ok {
+		if respErr, ok := resp.(*pbcom.Error); ok {
+			return r, fmt.Errorf("%+v", respErr)
+		}
+		return r, fmt.Errorf("resp is %T", resp)
+	}
+
+	return
+}
+
+// tick 定时发送心跳, 会话关闭后退出.
+func (s *session) tick() {
+	tickHeartbeat := time.NewTicker(consts.HeartbeatInterval)
+	defer tickHeartbeat.Stop()
+	for {
+		select {
+		case <-tickHeartbeat.C:
+			if _, err := sendReq[*pbc2s.HeartbeatReq, *pbc2s.HeartbeatResp](s, &pbc2s.HeartbeatReq{}); err != nil {
+				s.logf("send heartbeat failed, %v", err)
+			}
+		case <-s.chClose:
+			return
+		}
+	}
+}
+
+// OnStreamMsg 处理流消息.
+func (s *session) OnStreamMsg(msg sdkclient.Msg) {
+	// 网关为登录响应之后写出的全部下行数据包编号, 恢复响应与重放的数据包除外(重放的数据包沿用原编号).
+	switch m := msg.Msg.(type) {
+	case *pbc2s.LoginResp:
+		s.resumeTicket = m.ResumeTicket
+		s.recvSeq = 0
+		s.mirror.onLogin(m)
+	case *pbc2s.ResumeResp:
+		s.resumeTicket = m.Ticket
+	case *pbc2s.DisconnectPush:
+		// 服务端主动断开, 会话不可恢复.
+		s.resumeTicket = ""
+		s.recvSeq++
+	default:
+		s.recvSeq++
+	}
+
+	switch msg.Pt {
+	case codecc2s.PtResp:
+		s.logf("receive resp, seq=%d, %s{%+v}", msg.Seq, reflect.TypeOf(msg.Msg).Elem().Name(), msg.Msg)
+		if atomic.CompareAndSwapUint32(&s.reqSeq, msg.Seq, 0) {
+			// 在读取协程中更新镜像, 保证先于后续推送.
+			s.mirror.onResp(msg.Msg)
+			s.chResp <- msg.Msg
+		}
+	case codecc2s.PtPush:
+		s.logf("receive push, %s{%+v}", reflect.TypeOf(msg.Msg).Elem().Name(), msg.Msg)
+		s.mirror.onPush(msg.Msg)
+	}
+	s.cli.watches.print(s, msg.Msg)
+}
+
+// OnStreamClose 处理流关闭事件.
+func (s *session) OnStreamClose(err error) {
+	// 唤醒等待响应的请求.
+	if atomic.SwapUint32(&s.reqSeq, 0) != 0 {
+		s.chResp <- nil
+	}
+
+	if s.closed.Load() {
+		return
+	}
+
+	if s.resumeTicket == "" {
+		s.logf("stream closed: %v", err)
+		s.close()
+		s.cli.removeSession(s)
+		return
+	}
+
+	s.logf("stream closed: %v, try to resume.", err)
+	go s.resume(s.resumeTicket, s.recvSeq)
+}
+
+var (
+	signKey         any
+	onceLoadSignKey sync.Once
+)
+
+// getSignKey 获取签名密钥.
+func getSignKey() any {
+	onceLoadSignKey.Do(func() {
+		priKey, err := authjwt.LoadPrivKey(conf.SignKeyPath)
+		if err != nil {
+			log.Fatalf("load sign key failed: %v", err)
+		}
+		signKey = priKey
+	})
+	return signKey
+}
+
+// genUserToken 生成用户token.
+func genUserToken(uid string) (string, error) {
+	return utils.GenUserToken(getSignKey(), uid)
+}
+
+// getCharacterList 获取角色列表.
+func getCharacterList(token string) ([]httpproto.CharacterInfo, error) {
+	ctx, cancel := ctxutils.WithTimeout(context.Background(), consts.DefaultTimeout)
+	defer cancel()
+	return sdkclient.GetCharacterList(ctx, conf.LoginURLRoot, token)
+}
+
+// createCharacter 创建角色.
+func createCharacter(token string, serverId int64) (int64, error) {
+	ctx, cancel := ctxutils.WithTimeout(context.Background(), consts.DefaultTimeout)
+	defer cancel()
+	return sdkclient.CreateCharacter(ctx, conf.LoginURLRoot, token, serverId)
+}
+
+// loginCharacter 登录角色, 返回登录令牌与分配的网关地址.
+func loginCharacter(token string, characterId int64) (*httpproto.CharacterLoginResp, error) {
+	ctx, cancel := ctxutils.WithTimeout(context.Background(), consts.DefaultTimeout)
+	defer cancel()
+	return sdkclient.LoginCharacter(ctx, conf.LoginURLRoot, token, characterId)
+}
@@ -0,0 +1,199 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/base/nodeutil"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	"github.com/godyy/ggskit/infra/cluster"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gameStartTimeout = time.Minute      // 等待额外 Game 节点启动的时限.
+	gameStopTimeout  = 30 * time.Second // 等待额外 Game 节点退出的时限.
+)
+
+// gameProcess 额外的 Game 节点.
+//
+// Game 服务的状态是进程级的, 同一进程只能运行一个 Game 节点, 迁移与排空需要第二个节点,
+// 因此以子进程运行, 连接与进程内服务相同的替身服务.
+type gameProcess struct {
+	serverId int64  // 节点自身的服务器ID.
+	index    int    // 同一服务器下的节点序号.
+	nodeId   string // 节点ID.
+	httpAddr string // 管理接口地址.
+	cmd      *exec.Cmd
+}
+
+// startGameProcess 编译并启动服务器 serverId 下序号为 index 的 Game 节点, 节点额外托管 hosted 指定的服务器,
+// 等待其发布节点元数据.
+func startGameProcess(t *testing.T, serverId int64, index int, hosted ...int64) *gameProcess {
+	t.Helper()
+	dir := t.TempDir()
+
+	// 编译.
+	bin := filepath.Join(dir, "game")
+	build := exec.Command("go", "build", "-o", bin, "github.com/godyy/ggs/app/game")
+	build.Dir = testStack.Root
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, "build game: %s", out)
+
+	// 生成配置.
+	clusterPort, httpPort := freePort(t), freePort(t)
+	configPath := filepath.Join(dir, "game.toml")
+	writeGameConfig(t, configPath, clusterPort, httpPort, hosted)
+
+	// 启动.
+	logPath := filepath.Join(dir, "game.log")
+	logFile, err := os.Create(logPath)
+	require.NoError(t, err)
+	cmd := exec.Command(bin, "-config-path", configPath,
+		"-env-server-id", strconv.FormatInt(serverId, 10), "-env-node-index", strconv.Itoa(index))
+	cmd.Dir = testStack.Root
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	require.NoError(t, cmd.Start())
+
+	g := &gameProcess{
+		serverId: serverId,
+		index:    index,
+		nodeId:   cluster.MakeNodeID(consts.NodeGame, nodeutil.MakeIndexedServerNodeName(serverId, index)),
+		httpAddr: net.JoinHostPort("127.0.0.1", strconv.Itoa(httpPort)),
+		cmd:      cmd,
+	}
+	t.Cleanup(func() {
+		g.stop(t)
+		logFile.Close()
+		if t.Failed() {
+			if b, err := os.ReadFile(logPath); err == nil {
+				t.Logf("game %s log:\n%s", g.nodeId, b)
+			}
+		}
+	})
+
+	waitGameMeta(t, func(m *nodemeta.Meta) bool {
+		if m.NodeId() != g.nodeId {
+			return false
+		}
+		for _, id := range hosted {
+			if !m.HostsServer(id) {
+				return false
+			}
+		}
+		return true
+	})
+	return g
+}
+
+// stop 停止节点.
+func (g *gameProcess) stop(t *testing.T) {
+	if g.cmd.ProcessState != nil {
+		return
+	}
+	_ = g.cmd.Process.Signal(syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		_ = g.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(gameStopTimeout):
+		t.Errorf("game %s not stopped, kill it", g.nodeId)
+		_ = g.cmd.Process.Kill()
+		<-done
+	}
+}
+
+// writeGameConfig 以 Game 服务的 dev.toml 为模板生成配置, 替换端口与替身服务地址.
+func writeGameConfig(t *testing.T, path string, clusterPort, httpPort int, hosted []int64) {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(testStack.Root, "app/game/configs/dev.toml"))
+	require.NoError(t, err)
+
+	ids, _ := json.Marshal(append([]int64{}, hosted...))
+	for key, value := range map[string]string{
+		"ServerIds":     string(ids),
+		"HttpPort":      strconv.Itoa(httpPort),
+		"EnablePProf":   "false",
+		"Port":          strconv.Itoa(clusterPort),
+		"EtcdEndPoints": fmt.Sprintf("[%q]", testStack.EtcdEndpoint),
+		"Addrs":         fmt.Sprintf("[%q]", testStack.RedisAddr),
+		"URI":           strconv.Quote(testStack.MongoURI),
+	} {
+		re := regexp.MustCompile(`(?m)^` + key + ` = .*$`)
+		require.True(t, re.Match(b), "key %s not found in dev.toml", key)
+		b = re.ReplaceAll(b, []byte(key+" = "+value))
+	}
+	require.NoError(t, os.WriteFile(path, b, 0o644))
+}
+
+// waitGameMeta 等待满足 match 的 Game 节点元数据.
+func waitGameMeta(t *testing.T, match func(m *nodemeta.Meta) bool) {
+	t.Helper()
+	w, err := nodemeta.NewWatcher(&nodemeta.Config{
+		Endpoints: []string{testStack.EtcdEndpoint},
+		Root:      testStack.EtcdRoot,
+	}, consts.NodeGame)
+	require.NoError(t, err)
+	require.NoError(t, w.Start())
+	defer w.Stop()
+
+	require.Eventually(t, func() bool {
+		found := false
+		w.Range(func(m *nodemeta.Meta) bool {
+			found = match(m)
+			return !found
+		})
+		return found
+	}, gameStartTimeout, 100*time.Millisecond)
+}
+
+// freePort 返回一个当前可用的本地端口.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// adminDo 调用管理接口, 解析 JSON 响应至 resp, 返回状态码.
+func adminDo(t *testing.T, method, addr, path string, body, resp any) int {
+	t.Helper()
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, "http://"+addr+path, reader)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testStack.GameAdminToken)
+	req.Header.Set("Content-Type", "application/json")
+	r, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer r.Body.Close()
+	if resp != nil {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(resp))
+	}
+	return r.StatusCode
+}
@@ -0,0 +1,82 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/gdconf"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initItem 返回新角色初始拥有的道具. 配置表由进程内的 Game 服务加载.
+func initItem(t *testing.T) *gdconf.ItemCount {
+	t.Helper()
+	items := gdconf.Global().InitItems
+	require.NotEmpty(t, items, "no init items in Global table")
+	return items[0]
+}
+
+// useItem 使用 1 个道具, 断言响应与道具变更推送一致, 返回剩余数量.
+func useItem(t *testing.T, s *testSession, itemId int32) int64 {
+	t.Helper()
+	resp := request[*pbc2s.UseItemResp](t, s, &pbc2s.UseItemReq{ItemId: itemId, Num: 1})
+	assert.Equal(t, itemId, resp.ItemId)
+
+	push := waitPush[*pbc2s.ItemPush](t, s, pushTimeout)
+	require.Len(t, push.Items, 1)
+	assert.Equal(t, itemId, push.Items[0].Id)
+	assert.Equal(t, resp.LeftNum, push.Items[0].Count)
+	return resp.LeftNum
+}
+
+func TestUseItemPush(t *testing.T) {
+	item := initItem(t)
+	s := login(t, "it_use_item")
+
+	left := useItem(t, s, item.Id)
+	assert.Equal(t, int64(item.Count)-1, left)
+	assert.Equal(t, left-1, useItem(t, s, item.Id))
+}
+
+func TestDuplicateLoginKick(t *testing.T) {
+	first := login(t, "it_duplicate_login")
+	second := login(t, "it_duplicate_login")
+	require.Equal(t, first.playerId, second.playerId)
+
+	push := waitPush[*pbc2s.DisconnectPush](t, first, pushTimeout)
+	assert.Equal(t, pbc2s.DisconnectPush_AnotherLogin, push.Reason)
+
+	// 后登录的会话不受影响.
+	request[*pbc2s.HeartbeatResp](t, second, &pbc2s.HeartbeatReq{})
+}
+
+func TestResume(t *testing.T) {
+	item := initItem(t)
+	s := login(t, "it_resume")
+	left := useItem(t, s, item.Id)
+
+	// 连接中断后恢复会话, 玩家 Actor 不重新登录.
+	s.resume(t)
+	assert.Equal(t, left-1, useItem(t, s, item.Id))
+}
+
+func TestHeartbeatTimeoutLogout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the heartbeat timeout")
+	}
+
+	s := login(t, "it_heartbeat_timeout")
+
+	// 登录后不发送心跳, 超时后玩家登出, 网关断开连接.
+	waitPush[*pbc2s.DisconnectPush](t, s, consts.HeartbeatTimeout+10*time.Second)
+	select {
+	case <-s.closed:
+	case <-time.After(pushTimeout):
+		require.FailNow(t, "connection not closed after logout")
+	}
+}
@@ -0,0 +1,67 @@
+//go:build integration
+
+// Package integration 端到端集成测试.
+//
+// 在测试进程内启动 Login、Platform、Agent 与 Game 服务(见 app/devstack/stack), 监听随机端口,
+// 以客户端的登录流程驱动完整链路: 用户令牌 → 创建角色 → 登录网关 → LoginCharacterReq.
+//
+// 运行: go test -tags integration ./app/client/internal/mode/integration/
+package integration
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/godyy/ggs/app/devstack/stack"
+	authjwt "github.com/godyy/ggskit/base/auth/jwt"
+)
+
+var (
+	testStack   *stack.Stack // 进程内服务.
+	testSignKey any          // 用户令牌签名密钥.
+)
+
+func TestMain(m *testing.M) {
+	root, err := repoRoot()
+	if err != nil {
+		log.Fatalf("find repository root failed, %v", err)
+	}
+
+	testStack, err = stack.Start(&stack.Options{
+		Root:      root,
+		FreePorts: true,
+	})
+	if err != nil {
+		log.Fatalf("start stack failed, %v", err)
+	}
+
+	testSignKey, err = authjwt.LoadPrivKey(testStack.SignKeyPath)
+	if err != nil {
+		testStack.Stop()
+		log.Fatalf("load sign key failed, %v", err)
+	}
+
+	code := m.Run()
+	testStack.Stop()
+	os.Exit(code)
+}
+
+// repoRoot 自当前目录向上查找 go.mod 所在目录.
+func repoRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", os.ErrNotExist
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	migrateTimeout = 30 * time.Second // 等待迁移完成的时限.
+	drainTimeout   = time.Minute      // 等待排空完成的时限.
+	reloginTimeout = 15 * time.Second // 排空后等待重新登录成功的时限.
+)
+
+// drainStatus Game 管理接口的排空状态.
+type drainStatus struct {
+	Draining bool  `json:"draining"`
+	Done     bool  `json:"done"`
+	Players  int64 `json:"players"`
+	Summary  *struct {
+		Total  int   `json:"total"`
+		Saved  int   `json:"saved"`
+		Failed []any `json:"failed"`
+	} `json:"summary"`
+}
+
+// getDrainStatus 查询 Game 节点的排空状态.
+func getDrainStatus(t *testing.T, httpAddr string) drainStatus {
+	t.Helper()
+	var status drainStatus
+	require.Equal(t, http.StatusOK, adminDo(t, http.MethodGet, httpAddr, "/admin/drain", nil, &status))
+	return status
+}
+
+func TestMigrateAndDrain(t *testing.T) {
+	item := initItem(t)
+	uid := "it_migrate_drain"
+	s := login(t, uid)
+	left := useItem(t, s, item.Id)
+
+	// 启动该服务器的第二个 Game 节点.
+	g := startGameProcess(t, testStack.ServerID, 1)
+
+	// 迁移至第二个节点. 网关在 Actor 断开后重新连接至目标节点, 客户端连接保持不变.
+	// 源节点据节点元数据校验目标, 发现目标节点前返回 400.
+	var status int
+	require.Eventually(t, func() bool {
+		status = adminDo(t, http.MethodPost, testStack.GameHttpAddr,
+			fmt.Sprintf("/admin/players/%d/migrate", s.playerId), map[string]string{"target": g.nodeId}, nil)
+		return status != http.StatusBadRequest
+	}, migrateTimeout, 100*time.Millisecond, "target node not discovered")
+	require.Equal(t, http.StatusOK, status)
+	require.Eventually(t, func() bool {
+		return getDrainStatus(t, g.httpAddr).Players == 1
+	}, migrateTimeout, 100*time.Millisecond, "player not started on target node")
+
+	// 数据随迁移持久化并在目标节点加载.
+	left--
+	assert.Equal(t, left, useItem(t, s, item.Id))
+
+	// 排空第二个节点, 玩家被断开, 数据持久化后位置释放.
+	require.Equal(t, http.StatusAccepted, adminDo(t, http.MethodPost, g.httpAddr, "/admin/drain", nil, nil))
+	var drained drainStatus
+	require.Eventually(t, func() bool {
+		drained = getDrainStatus(t, g.httpAddr)
+		return drained.Done
+	}, drainTimeout, 100*time.Millisecond, "drain not done")
+	require.NotNil(t, drained.Summary)
+	assert.Equal(t, 1, drained.Summary.Total)
+	assert.Equal(t, 1, drained.Summary.Saved)
+	assert.Empty(t, drained.Summary.Failed)
+	push := waitPush[*pbc2s.DisconnectPush](t, s, pushTimeout)
+	assert.Equal(t, pbc2s.DisconnectPush_Reconnect, push.Reason)
+
+	// 重新登录, 由未排空的节点接管, 数据保持.
+	var relogin *testSession
+	require.Eventually(t, func() bool {
+		var err error
+		relogin, err = tryLogin(uid)
+		return err == nil
+	}, reloginTimeout, 500*time.Millisecond, "relogin after drain")
+	t.Cleanup(relogin.close)
+	left--
+	assert.Equal(t, left, useItem(t, relogin, item.Id))
+}
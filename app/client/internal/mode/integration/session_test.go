@@ -0,0 +1,267 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godyy/ggs/app/client/internal/mode/internal/utils"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	sdkclient "github.com/godyy/ggs/sdk/client"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	requestTimeout = 5 * time.Second // 等待响应的时限.
+	pushTimeout    = 5 * time.Second // 等待推送的时限.
+)
+
+// testSession 已登录网关的测试会话.
+type testSession struct {
+	playerId  int64  // 角色ID.
+	agentAddr string // 网关地址.
+
+	mtx          sync.Mutex
+	stream       *sdkclient.Stream
+	seq          uint32                        // 请求 seq 自增键.
+	waits        map[uint32]chan proto.Message // 等待响应的请求.
+	resumeTicket string                        // 会话恢复票据.
+	recvSeq      uint32                        // 登录后已收到的下行数据包数量.
+
+	pushes chan proto.Message // 收到的推送.
+	closed chan error         // 连接被对端关闭.
+}
+
+// login 以 uid 完成登录流程, 返回已登录网关的会话.
+func login(t *testing.T, uid string) *testSession {
+	t.Helper()
+	s, err := tryLogin(uid)
+	require.NoError(t, err)
+	t.Cleanup(s.close)
+	return s
+}
+
+// tryLogin 以 uid 完成登录流程: 用户令牌 → 角色列表/创建角色 → 角色登录令牌 → 连接网关并登录.
+func tryLogin(uid string) (*testSession, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	userToken, err := utils.GenUserToken(testSignKey, uid)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "gen user token")
+	}
+
+	// 选择或创建角色.
+	characters, err := sdkclient.GetCharacterList(ctx, testStack.LoginURLRoot, userToken)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "get character list")
+	}
+	var characterId int64
+	for _, c := range characters {
+		if c.ServerID == testStack.ServerID {
+			characterId = c.ID
+			break
+		}
+	}
+	if characterId == 0 {
+		if characterId, err = sdkclient.CreateCharacter(ctx, testStack.LoginURLRoot, userToken, testStack.ServerID); err != nil {
+			return nil, pkgerrors.WithMessage(err, "create character")
+		}
+	}
+
+	// 获取登录令牌.
+	loginResp, err := sdkclient.LoginCharacter(ctx, testStack.LoginURLRoot, userToken, characterId)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "login character")
+	}
+
+	s := &testSession{
+		playerId:  characterId,
+		agentAddr: loginResp.AgentAddr,
+		waits:     make(map[uint32]chan proto.Message),
+		pushes:    make(chan proto.Message, 64),
+		closed:    make(chan error, 1),
+	}
+	if s.agentAddr == "" {
+		s.agentAddr = testStack.AgentAddr
+	}
+
+	// 连接网关并登录.
+	if err := s.connect(); err != nil {
+		return nil, pkgerrors.WithMessage(err, "connect agent")
+	}
+	if _, err := call[*pbc2s.LoginResp](s, &pbc2s.LoginReq{Token: loginResp.Token}); err != nil {
+		s.close()
+		return nil, pkgerrors.WithMessage(err, "login agent")
+	}
+	return s, nil
+}
+
+// connect 连接网关.
+func (s *testSession) connect() error {
+	stream, err := sdkclient.DialStream(s.agentAddr, s)
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	s.stream = stream
+	s.mtx.Unlock()
+	return nil
+}
+
+// dropConn 直接关闭连接, 不通知服务端, 模拟网络中断.
+func (s *testSession) dropConn() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.stream != nil {
+		s.stream.Close()
+		s.stream = nil
+	}
+}
+
+// resume 重新连接网关并恢复会话.
+func (s *testSession) resume(t *testing.T) {
+	t.Helper()
+	s.dropConn()
+	s.mtx.Lock()
+	ticket, lastSeq := s.resumeTicket, s.recvSeq
+	s.mtx.Unlock()
+	require.NotEmpty(t, ticket, "no resume ticket")
+
+	require.NoError(t, s.connect())
+	_, err := call[*pbc2s.ResumeResp](s, &pbc2s.ResumeReq{
+		Ticket:  ticket,
+		LastSeq: lastSeq,
+	})
+	require.NoError(t, err)
+}
+
+// close 关闭会话.
+func (s *testSession) close() {
+	s.dropConn()
+}
+
+// send 发送请求并等待响应.
+func (s *testSession) send(req proto.Message) (proto.Message, error) {
+	s.mtx.Lock()
+	if s.stream == nil {
+		s.mtx.Unlock()
+		return nil, pkgerrors.New("not connected")
+	}
+	s.seq++
+	seq := s.seq
+	ch := make(chan proto.Message, 1)
+	s.waits[seq] = ch
+	err := s.stream.SendReq(seq, req)
+	s.mtx.Unlock()
+
+	defer func() {
+		s.mtx.Lock()
+		delete(s.waits, seq)
+		s.mtx.Unlock()
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case err := <-s.closed:
+		return nil, pkgerrors.WithMessage(err, "stream closed")
+	case <-time.After(requestTimeout):
+		return nil, pkgerrors.New("request timeout")
+	}
+}
+
+// call 发送请求并等待 Resp 类型的响应.
+func call[Resp proto.Message](s *testSession, req proto.Message) (r Resp, err error) {
+	resp, err := s.send(req)
+	if err != nil {
+		return r, err
+	}
+	r, ok := resp.(Resp)
+	if !ok {
+		if e, ok := resp.(*pbcommon.Error); ok {
+			return r, fmt.Errorf("error resp, code %d", e.Code)
+		}
+		return r, fmt.Errorf("resp is %T", resp)
+	}
+	return r, nil
+}
+
+// request 发送请求并断言收到 Resp 类型的响应.
+func request[Resp proto.Message](t *testing.T, s *testSession, req proto.Message) Resp {
+	t.Helper()
+	r, err := call[Resp](s, req)
+	require.NoError(t, err)
+	return r
+}
+
+// waitPush 等待 T 类型的推送, 跳过期间收到的其他推送.
+func waitPush[T proto.Message](t *testing.T, s *testSession, timeout time.Duration) T {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case m := <-s.pushes:
+			if v, ok := m.(T); ok {
+				return v
+			}
+		case <-deadline:
+			var zero T
+			require.FailNowf(t, "wait push timeout", "%T", zero)
+			return zero
+		}
+	}
+}
+
+// OnStreamMsg 处理流消息.
+func (s *testSession) OnStreamMsg(msg sdkclient.Msg) {
+	s.mtx.Lock()
+	switch m := msg.Msg.(type) {
+	case *pbc2s.LoginResp:
+		s.resumeTicket = m.ResumeTicket
+		s.recvSeq = 0
+	case *pbc2s.ResumeResp:
+		s.resumeTicket = m.Ticket
+	case *pbc2s.DisconnectPush:
+		// 服务端主动断开, 会话不可恢复.
+		s.resumeTicket = ""
+	default:
+		s.recvSeq++
+	}
+	ch := s.waits[msg.Seq]
+	s.mtx.Unlock()
+
+	switch msg.Pt {
+	case codecc2s.PtResp:
+		if ch != nil {
+			select {
+			case ch <- msg.Msg:
+			default:
+			}
+		}
+	case codecc2s.PtPush:
+		select {
+		case s.pushes <- msg.Msg:
+		default:
+		}
+	}
+}
+
+// OnStreamClose 处理流关闭事件.
+func (s *testSession) OnStreamClose(err error) {
+	select {
+	case s.closed <- err:
+	default:
+	}
+}
@@ -0,0 +1,204 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pbcom "github.com/godyy/ggs/pkg/protocol/pb/common"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Session 脚本执行所需的会话.
+type Session interface {
+	// Request 发送请求并等待响应.
+	Request(req proto.Message) (proto.Message, error)
+
+	// Pushes 返回推送通道.
+	Pushes() <-chan proto.Message
+}
+
+// AssertionError 断言失败.
+type AssertionError struct {
+	Step string // 步骤路径, 例如 steps[1].loop[2].steps[0].
+	Msg  string // 失败原因.
+}
+
+func (e *AssertionError) Error() string {
+	return fmt.Sprintf("%s: assertion failed: %s", e.Step, e.Msg)
+}
+
+// Run 执行脚本, 断言失败时返回 *AssertionError.
+func (s *Script) Run(ctx context.Context, sess Session) error {
+	return runSteps(ctx, sess, s.Steps, "steps")
+}
+
+// runSteps 依次执行步骤.
+func runSteps(ctx context.Context, sess Session, steps []*Step, path string) error {
+	for i, step := range steps {
+		stepPath := fmt.Sprintf("%s[%d]", path, i)
+		if err := ctx.Err(); err != nil {
+			return pkgerrors.WithMessage(err, stepPath)
+		}
+		if err := step.run(ctx, sess, stepPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run 执行步骤.
+func (st *Step) run(ctx context.Context, sess Session, path string) error {
+	switch {
+	case st.Send != "":
+		return st.runSend(sess, path)
+	case st.WaitPush != "":
+		return st.runWaitPush(ctx, sess, path)
+	case st.Sleep > 0:
+		select {
+		case <-time.After(time.Duration(st.Sleep)):
+			return nil
+		case <-ctx.Done():
+			return pkgerrors.WithMessage(ctx.Err(), path)
+		}
+	case st.Loop != nil:
+		for i := range st.Loop.Times {
+			if err := runSteps(ctx, sess, st.Loop.Steps, fmt.Sprintf("%s.loop[%d].steps", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runSend 发送请求并校验响应.
+func (st *Step) runSend(sess Session, path string) error {
+	req := st.msgType.New().Interface()
+	if len(st.Body) > 0 {
+		if err := protojson.Unmarshal(st.Body, req); err != nil {
+			return pkgerrors.WithMessagef(err, "%s: build %s", path, st.Send)
+		}
+	}
+	resp, err := sess.Request(req)
+	if err != nil {
+		return pkgerrors.WithMessagef(err, "%s: request %s", path, st.Send)
+	}
+	if st.Expect == nil {
+		return nil
+	}
+	if err := checkMessage(resp, st.Expect.Type, st.Expect.Fields); err != nil {
+		return &AssertionError{Step: path, Msg: err.Error()}
+	}
+	return nil
+}
+
+// runWaitPush 等待指定推送并校验字段.
+func (st *Step) runWaitPush(ctx context.Context, sess Session, path string) error {
+	timer := time.NewTimer(time.Duration(st.Timeout))
+	defer timer.Stop()
+	for {
+		select {
+		case push := <-sess.Pushes():
+			if messageName(push) != st.WaitPush {
+				continue
+			}
+			if err := checkMessage(push, "", st.Fields); err != nil {
+				return &AssertionError{Step: path, Msg: err.Error()}
+			}
+			return nil
+		case <-timer.C:
+			return &AssertionError{Step: path, Msg: fmt.Sprintf("no %s within %s", st.WaitPush, time.Duration(st.Timeout))}
+		case <-ctx.Done():
+			return pkgerrors.WithMessage(ctx.Err(), path)
+		}
+	}
+}
+
+// messageName 返回消息名.
+func messageName(msg proto.Message) string {
+	return string(msg.ProtoReflect().Descriptor().Name())
+}
+
+// checkMessage 校验消息类型与字段, typ 为空时不校验类型.
+func checkMessage(msg proto.Message, typ string, fields map[string]any) error {
+	name := messageName(msg)
+	if typ != "" && name != typ {
+		if e, ok := msg.(*pbcom.Error); ok {
+			return fmt.Errorf("expect %s, got Error code=%d", typ, e.Code)
+		}
+		return fmt.Errorf("expect %s, got %s", typ, name)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	v, err := toJSONValue(msg)
+	if err != nil {
+		return pkgerrors.WithMessagef(err, "marshal %s", name)
+	}
+	for path, want := range fields {
+		got, ok := lookup(v, path)
+		if !ok {
+			return fmt.Errorf("%s.%s not found", name, path)
+		}
+		if !equalValue(got, want) {
+			return fmt.Errorf("%s.%s expect %v, got %v", name, path, want, got)
+		}
+	}
+	return nil
+}
+
+// toJSONValue 将消息转换为 JSON 值, 包括零值字段.
+func toJSONValue(msg proto.Message) (any, error) {
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// lookup 按以 . 分隔的路径查找字段, 数组元素以下标表示, 例如 items.0.id.
+func lookup(v any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch x := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = x[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, false
+			}
+			v = x[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// equalValue 比较字段值. protojson 以字符串表示 64 位整数, 因此标量按字符串形式比较.
+func equalValue(got, want any) bool {
+	switch got.(type) {
+	case map[string]any, []any:
+		g, _ := json.Marshal(got)
+		w, _ := json.Marshal(want)
+		return bytes.Equal(g, w)
+	default:
+		return fmt.Sprint(got) == fmt.Sprint(want)
+	}
+}
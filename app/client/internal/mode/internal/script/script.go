@@ -0,0 +1,215 @@
+// Package script 客户端场景脚本, 以 YAML/JSON 描述请求、断言、等待推送、休眠与循环等步骤,
+// 供 script、robot 等模式执行.
+//
+// 示例:
+//
+//	name: use-item
+//	steps:
+//	  - send: UseItem
+//	    body: {itemId: 1, num: 1}
+//	    expect:
+//	      type: UseItemResp
+//	  - wait_push: ItemPush
+//	    timeout: 3s
+//	    fields: {"items.0.id": 1}
+//	  - sleep: 500ms
+//	  - loop:
+//	      times: 3
+//	      steps:
+//	        - send: Heartbeat
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// defaultPushTimeout 等待推送的默认时限.
+const defaultPushTimeout = 5 * time.Second
+
+// Script 场景脚本.
+type Script struct {
+	Name  string  `json:"name"`  // 脚本名称.
+	Steps []*Step `json:"steps"` // 步骤.
+}
+
+// Step 脚本步骤, Send、WaitPush、Sleep、Loop 有且仅有一个.
+type Step struct {
+	Send   string          `json:"send,omitempty"`   // 发送请求, 消息名可省略 Req 后缀.
+	Body   json.RawMessage `json:"body,omitempty"`   // 请求内容, protojson 格式.
+	Expect *Expect         `json:"expect,omitempty"` // 响应断言, 为空时仅要求收到响应.
+
+	WaitPush string         `json:"wait_push,omitempty"` // 等待推送, 期间收到的其它推送被忽略.
+	Fields   map[string]any `json:"fields,omitempty"`    // 推送字段断言.
+	Timeout  Duration       `json:"timeout,omitempty"`   // 等待推送的时限, 默认 5s.
+
+	Sleep Duration `json:"sleep,omitempty"` // 休眠.
+
+	Loop *Loop `json:"loop,omitempty"` // 循环执行子步骤.
+
+	msgType protoreflect.MessageType // 请求消息类型.
+}
+
+// Expect 响应断言.
+type Expect struct {
+	Type   string         `json:"type,omitempty"`   // 响应消息名, 错误响应为 Error.
+	Fields map[string]any `json:"fields,omitempty"` // 字段断言, 字段路径 -> 期望值.
+}
+
+// Loop 循环.
+type Loop struct {
+	Times int     `json:"times"` // 循环次数.
+	Steps []*Step `json:"steps"` // 子步骤.
+}
+
+// Duration 以 time.ParseDuration 格式表示的时长.
+type Duration time.Duration
+
+// UnmarshalJSON 实现 json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"1s\", got %s", b)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil || v < 0 {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Load 加载脚本文件, .yaml/.yml 按 YAML 解析, 其余按 JSON 解析.
+func Load(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "read script")
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return ParseJSON(data)
+	}
+}
+
+// ParseYAML 解析 YAML 格式的脚本.
+func ParseYAML(data []byte) (*Script, error) {
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "parse yaml")
+	}
+	return ParseJSON(j)
+}
+
+// ParseJSON 解析 JSON 格式的脚本.
+func ParseJSON(data []byte) (*Script, error) {
+	s := &Script{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(s); err != nil {
+		return nil, pkgerrors.WithMessage(err, "parse json")
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validate 校验脚本并解析请求消息类型.
+func (s *Script) validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("script %q has no steps", s.Name)
+	}
+	return validateSteps(s.Steps, "steps")
+}
+
+// validateSteps 校验步骤.
+func validateSteps(steps []*Step, path string) error {
+	for i, step := range steps {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("%s[%d]: %w", path, i, err)
+		}
+		if step.Loop != nil {
+			if err := validateSteps(step.Loop.Steps, fmt.Sprintf("%s[%d].loop.steps", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate 校验步骤.
+func (st *Step) validate() error {
+	actions := 0
+	if st.Send != "" {
+		actions++
+	}
+	if st.WaitPush != "" {
+		actions++
+	}
+	if st.Sleep > 0 {
+		actions++
+	}
+	if st.Loop != nil {
+		actions++
+	}
+	if actions != 1 {
+		return fmt.Errorf("step must have exactly one of send, wait_push, sleep, loop")
+	}
+
+	switch {
+	case st.Send != "":
+		mt, err := findReqType(st.Send)
+		if err != nil {
+			return err
+		}
+		st.msgType = mt
+		if len(st.Body) > 0 {
+			if err := protojson.Unmarshal(st.Body, mt.New().Interface()); err != nil {
+				return pkgerrors.WithMessagef(err, "invalid body of %s", st.Send)
+			}
+		}
+	case st.WaitPush != "":
+		if st.Timeout == 0 {
+			st.Timeout = Duration(defaultPushTimeout)
+		}
+	case st.Loop != nil:
+		if st.Loop.Times <= 0 {
+			return fmt.Errorf("loop times must > 0")
+		}
+		if len(st.Loop.Steps) == 0 {
+			return fmt.Errorf("loop has no steps")
+		}
+	}
+	return nil
+}
+
+// findReqType 按消息名查找已注册的请求消息类型.
+func findReqType(name string) (protoreflect.MessageType, error) {
+	if !strings.HasSuffix(name, "Req") {
+		name += "Req"
+	}
+	fullName := protoreflect.FullName("c2s." + name)
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(fullName)
+	if err != nil {
+		return nil, fmt.Errorf("message %s not found", fullName)
+	}
+	if _, ok := c2s.Registry.GetPid(mt.New().Interface()); !ok {
+		return nil, fmt.Errorf("message %s not registered", fullName)
+	}
+	return mt, nil
+}
@@ -0,0 +1,107 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcom "github.com/godyy/ggs/pkg/protocol/pb/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeSession 按请求返回预设响应, 并在响应后投递推送.
+type fakeSession struct {
+	handle func(req proto.Message) (proto.Message, []proto.Message)
+	pushes chan proto.Message
+	reqs   []proto.Message
+}
+
+func newFakeSession(handle func(req proto.Message) (proto.Message, []proto.Message)) *fakeSession {
+	return &fakeSession{handle: handle, pushes: make(chan proto.Message, 16)}
+}
+
+func (s *fakeSession) Request(req proto.Message) (proto.Message, error) {
+	s.reqs = append(s.reqs, req)
+	resp, pushes := s.handle(req)
+	for _, p := range pushes {
+		s.pushes <- p
+	}
+	return resp, nil
+}
+
+func (s *fakeSession) Pushes() <-chan proto.Message {
+	return s.pushes
+}
+
+const useItemScript = `
+name: use-item
+steps:
+  - send: UseItem
+    body: {itemId: 1, num: 2}
+    expect:
+      type: UseItemResp
+      fields: {itemId: 1, leftNum: 8}
+  - wait_push: ItemPush
+    timeout: 100ms
+    fields: {"items.0.id": 1}
+  - loop:
+      times: 3
+      steps:
+        - send: Heartbeat
+        - sleep: 1ms
+`
+
+func useItemHandler(leftNum int64) func(req proto.Message) (proto.Message, []proto.Message) {
+	return func(req proto.Message) (proto.Message, []proto.Message) {
+		switch r := req.(type) {
+		case *pbc2s.UseItemReq:
+			return &pbc2s.UseItemResp{ItemId: r.ItemId, Num: r.Num, LeftNum: leftNum},
+				[]proto.Message{&pbc2s.SystemPush{}, &pbc2s.ItemPush{Items: []*pbcom.Item{{Id: r.ItemId}}}}
+		default:
+			return &pbc2s.HeartbeatResp{}, nil
+		}
+	}
+}
+
+func TestScriptRun(t *testing.T) {
+	sc, err := ParseYAML([]byte(useItemScript))
+	require.NoError(t, err)
+	assert.Equal(t, "use-item", sc.Name)
+
+	sess := newFakeSession(useItemHandler(8))
+	require.NoError(t, sc.Run(context.Background(), sess))
+	require.Len(t, sess.reqs, 4)
+	assert.True(t, proto.Equal(&pbc2s.UseItemReq{ItemId: 1, Num: 2}, sess.reqs[0]))
+
+	// 字段断言失败.
+	err = sc.Run(context.Background(), newFakeSession(useItemHandler(7)))
+	var ae *AssertionError
+	require.True(t, errors.As(err, &ae))
+	assert.Equal(t, "steps[0]", ae.Step)
+}
+
+func TestScriptWaitPushTimeout(t *testing.T) {
+	sc, err := ParseJSON([]byte(`{"steps":[{"wait_push":"ItemPush","timeout":"10ms"}]}`))
+	require.NoError(t, err)
+	err = sc.Run(context.Background(), newFakeSession(nil))
+	var ae *AssertionError
+	require.True(t, errors.As(err, &ae))
+	assert.Equal(t, "steps[0]", ae.Step)
+}
+
+func TestScriptInvalid(t *testing.T) {
+	for _, s := range []string{
+		`{"steps":[]}`,
+		`{"steps":[{"send":"NoSuchReq"}]}`,
+		`{"steps":[{"send":"UseItem","sleep":"1s"}]}`,
+		`{"steps":[{"send":"UseItem","body":{"unknown":1}}]}`,
+		`{"steps":[{"loop":{"times":0,"steps":[{"sleep":"1s"}]}}]}`,
+		`{"steps":[{"sleep":"abc"}]}`,
+	} {
+		_, err := ParseJSON([]byte(s))
+		assert.Error(t, err, s)
+	}
+}
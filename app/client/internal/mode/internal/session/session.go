@@ -0,0 +1,222 @@
+// Package session 封装客户端与服务端的一次会话: 登录流程、请求响应与推送接收,
+// 供 robot、script 等模式复用.
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggs/app/client/internal/conf"
+	"github.com/godyy/ggs/app/client/internal/mode/internal/utils"
+	"github.com/godyy/ggs/app/login/httpproto"
+	"github.com/godyy/ggs/internal/base/consts"
+	sdkclient "github.com/godyy/ggs/sdk/client"
+	"github.com/godyy/ggskit/utils/ctxutils"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// pushBufferSize 推送缓冲数量, 超出时丢弃最早的推送.
+const pushBufferSize = 256
+
+var (
+	// ErrTimeout 请求超时.
+	ErrTimeout = errors.New("timeout")
+
+	// ErrClosed 连接已断开.
+	ErrClosed = errors.New("closed")
+)
+
+// Observer 会话观察者, 用于统计请求与推送.
+type Observer interface {
+	// RecordResp 记录收到响应的请求, code 为错误码, 0 表示成功.
+	RecordResp(name string, latency time.Duration, code int32)
+
+	// RecordFailure 记录未收到响应的请求.
+	RecordFailure(name string, reason string)
+
+	// RecordPush 记录收到的推送.
+	RecordPush(name string)
+}
+
+// nopObserver 空观察者.
+type nopObserver struct{}
+
+func (nopObserver) RecordResp(string, time.Duration, int32) {}
+func (nopObserver) RecordFailure(string, string)            {}
+func (nopObserver) RecordPush(string)                       {}
+
+// Session 客户端会话, 基于 SDK 客户端, 登录后自动发送心跳, 请求可并发发送.
+type Session struct {
+	uid      string
+	observer Observer
+	cli      *sdkclient.Client
+	pushMtx  sync.Mutex
+	pushes   chan proto.Message // 推送
+}
+
+// New 创建会话, observer 为空时不做统计.
+func New(uid string, observer Observer) *Session {
+	if observer == nil {
+		observer = nopObserver{}
+	}
+	return &Session{
+		uid:      uid,
+		observer: observer,
+		pushes:   make(chan proto.Message, pushBufferSize),
+	}
+}
+
+// UID 返回用户ID.
+func (s *Session) UID() string {
+	return s.uid
+}
+
+// Login 完成登录流程: 用户令牌 -> 角色列表/创建角色 -> 角色登录 -> 连接网关 -> LoginReq.
+func (s *Session) Login(ctx context.Context, signKey any, serverId int64) error {
+	token, err := utils.GenUserToken(signKey, s.uid)
+	if err != nil {
+		return pkgerrors.WithMessage(err, "gen user token")
+	}
+
+	// 选择或创建角色.
+	var characters []httpproto.CharacterInfo
+	if err := s.timeHttp(ctx, "http:character/list", func(ctx context.Context) (err error) {
+		characters, err = sdkclient.GetCharacterList(ctx, conf.LoginURLRoot, token)
+		return
+	}); err != nil {
+		return pkgerrors.WithMessage(err, "get character list")
+	}
+	var characterId int64
+	for _, c := range characters {
+		if c.ServerID == serverId {
+			characterId = c.ID
+			break
+		}
+	}
+	if characterId == 0 {
+		if err := s.timeHttp(ctx, "http:character/create", func(ctx context.Context) (err error) {
+			characterId, err = sdkclient.CreateCharacter(ctx, conf.LoginURLRoot, token, serverId)
+			return
+		}); err != nil {
+			return pkgerrors.WithMessage(err, "create character")
+		}
+	}
+
+	// 角色登录.
+	var loginResp *httpproto.CharacterLoginResp
+	if err := s.timeHttp(ctx, "http:character/login", func(ctx context.Context) (err error) {
+		loginResp, err = sdkclient.LoginCharacter(ctx, conf.LoginURLRoot, token, characterId)
+		return
+	}); err != nil {
+		return pkgerrors.WithMessage(err, "login character")
+	}
+	agentAddr := loginResp.AgentAddr
+	if agentAddr == "" {
+		agentAddr = conf.AgentAddr
+	}
+	if agentAddr == "" {
+		return errors.New("no agent address")
+	}
+
+	// 连接网关并登录.
+	cli, err := sdkclient.Dial(agentAddr, sdkclient.Options{})
+	if err != nil {
+		s.observer.RecordFailure("connect", err.Error())
+		return pkgerrors.WithMessage(err, "connect agent")
+	}
+	s.cli = cli
+	cli.SubscribeAll(s.onPush)
+	start := time.Now()
+	if _, err := cli.Login(ctx, loginResp.Token); err != nil {
+		s.record("LoginReq", start, err)
+		cli.Close()
+		return pkgerrors.WithMessage(err, "login agent")
+	}
+	s.observer.RecordResp("LoginReq", time.Since(start), 0)
+	return nil
+}
+
+// timeHttp 执行 HTTP 请求并记录延迟.
+func (s *Session) timeHttp(ctx context.Context, name string, f func(ctx context.Context) error) error {
+	ctx, cancel := ctxutils.WithTimeout(ctx, consts.DefaultTimeout)
+	defer cancel()
+	start := time.Now()
+	if err := f(ctx); err != nil {
+		s.observer.RecordFailure(name, "error")
+		return err
+	}
+	s.observer.RecordResp(name, time.Since(start), 0)
+	return nil
+}
+
+// Request 发送请求并等待响应, 记录延迟与错误码. 错误响应以 *pbcom.Error 返回.
+func (s *Session) Request(req proto.Message) (proto.Message, error) {
+	name := string(req.ProtoReflect().Descriptor().Name())
+	start := time.Now()
+	resp, err := s.cli.Call(context.Background(), req)
+	if err != nil {
+		var se *sdkclient.ServerError
+		if errors.As(err, &se) {
+			s.observer.RecordResp(name, time.Since(start), se.Code())
+			return se.Resp, nil
+		}
+		return nil, s.record(name, start, err)
+	}
+	s.observer.RecordResp(name, time.Since(start), 0)
+	return resp, nil
+}
+
+// record 记录失败的请求, 返回对应的会话错误.
+func (s *Session) record(name string, start time.Time, err error) error {
+	var se *sdkclient.ServerError
+	switch {
+	case errors.As(err, &se):
+		s.observer.RecordResp(name, time.Since(start), se.Code())
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		s.observer.RecordFailure(name, "timeout")
+		return ErrTimeout
+	default:
+		s.observer.RecordFailure(name, "closed")
+		return ErrClosed
+	}
+}
+
+// onPush 记录推送并放入推送缓冲, 缓冲已满时丢弃最早的推送.
+func (s *Session) onPush(m proto.Message) {
+	s.observer.RecordPush(string(m.ProtoReflect().Descriptor().Name()))
+
+	s.pushMtx.Lock()
+	defer s.pushMtx.Unlock()
+	for {
+		select {
+		case s.pushes <- m:
+			return
+		default:
+		}
+		select {
+		case <-s.pushes:
+		default:
+		}
+	}
+}
+
+// Pushes 返回推送通道.
+func (s *Session) Pushes() <-chan proto.Message {
+	return s.pushes
+}
+
+// Closed 返回连接断开通知.
+func (s *Session) Closed() <-chan struct{} {
+	return s.cli.Done()
+}
+
+// Close 关闭会话.
+func (s *Session) Close() {
+	if s.cli != nil {
+		s.cli.Close()
+	}
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/godyy/ggs/internal/models"
+	authjwt "github.com/godyy/ggskit/base/auth/jwt"
+	"github.com/godyy/ggskit/base/env"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// GenUserToken 使用签名密钥为 uid 生成用户token.
+func GenUserToken(signKey any, uid string) (string, error) {
+	info := &models.UserInfo{
+		UID: uid,
+	}
+	sub, err := json.Marshal(info)
+	if err != nil {
+		return "", pkgerrors.WithMessage(err, "marshal user info")
+	}
+	return authjwt.SignToken(signKey, env.Get().Stage(), string(sub), 5*time.Minute, time.Now())
+}
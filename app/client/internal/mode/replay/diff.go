@@ -0,0 +1,124 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// diffMessages 比较原始响应与回放响应, 返回差异描述.
+// ignore 中的字段不参与比较, 字段以 path 或 Msg.path 表示, 例如 LoginResp.serverTime.
+func diffMessages(want, got proto.Message, ignore map[string]bool) ([]string, error) {
+	wantName := string(want.ProtoReflect().Descriptor().Name())
+	gotName := string(got.ProtoReflect().Descriptor().Name())
+	if wantName != gotName {
+		return []string{fmt.Sprintf("type: want %s, got %s", wantName, gotName)}, nil
+	}
+
+	w, err := toJSONValue(want)
+	if err != nil {
+		return nil, err
+	}
+	g, err := toJSONValue(got)
+	if err != nil {
+		return nil, err
+	}
+	d := &differ{name: wantName, ignore: ignore}
+	d.diff("", w, g)
+	return d.diffs, nil
+}
+
+// toJSONValue 将消息转换为 JSON 值, 包括零值字段.
+func toJSONValue(msg proto.Message) (any, error) {
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// differ 逐字段比较 JSON 值.
+type differ struct {
+	name   string
+	ignore map[string]bool
+	diffs  []string
+}
+
+// ignored 返回字段是否不参与比较.
+func (d *differ) ignored(path string) bool {
+	return d.ignore[path] || d.ignore[d.name+"."+path]
+}
+
+// diff 比较 path 处的值.
+func (d *differ) diff(path string, want, got any) {
+	if path != "" && d.ignored(path) {
+		return
+	}
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			d.add(path, want, got)
+			return
+		}
+		keys := make(map[string]struct{}, len(w)+len(g))
+		for k := range w {
+			keys[k] = struct{}{}
+		}
+		for k := range g {
+			keys[k] = struct{}{}
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			d.diff(joinPath(path, k), w[k], g[k])
+		}
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			d.add(path, want, got)
+			return
+		}
+		if len(w) != len(g) {
+			d.diffs = append(d.diffs, fmt.Sprintf("%s: want %d elements, got %d", path, len(w), len(g)))
+			return
+		}
+		for i := range w {
+			d.diff(joinPath(path, strconv.Itoa(i)), w[i], g[i])
+		}
+	default:
+		if fmt.Sprint(want) != fmt.Sprint(got) {
+			d.add(path, want, got)
+		}
+	}
+}
+
+// add 记录差异.
+func (d *differ) add(path string, want, got any) {
+	if path == "" {
+		path = "."
+	}
+	d.diffs = append(d.diffs, fmt.Sprintf("%s: want %v, got %v", path, want, got))
+}
+
+// joinPath 拼接字段路径.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,51 @@
+package replay
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/godyy/ggskit/base/flags"
+)
+
+var (
+	filePath string          // 录制文件路径
+	uid      string          // 回放使用的用户ID
+	serverId int64           // 服务器ID
+	speed    float64         // 回放速度倍数, 0 表示不等待原始间隔
+	ignore   map[string]bool // 不参与比较的字段
+)
+
+func init() {
+	flags.String("replay-file", "", "recording file captured by agent")
+	flags.String("replay-uid", "replay", "user id to replay the recording")
+	flags.Int64("replay-server-id", 1, "server id to replay the recording")
+	flags.String("replay-speed", "1", "replay speed multiplier, 0 means no delay between requests")
+	flags.String("replay-ignore", "", "fields excluded from diff, [Msg.]path[,[Msg.]path...]")
+}
+
+func applyFlags() {
+	filePath, _ = flags.GetValue[string]("replay-file")
+	if filePath == "" {
+		log.Fatal("-replay-file is empty")
+	}
+	uid, _ = flags.GetValue[string]("replay-uid")
+	if uid == "" {
+		log.Fatal("-replay-uid is empty")
+	}
+	serverId, _ = flags.GetValue[int64]("replay-server-id")
+
+	s, _ := flags.GetValue[string]("replay-speed")
+	var err error
+	if speed, err = strconv.ParseFloat(s, 64); err != nil || speed < 0 {
+		log.Fatalf("-replay-speed is invalid: %s", s)
+	}
+
+	ignore = make(map[string]bool)
+	s, _ = flags.GetValue[string]("replay-ignore")
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			ignore[field] = true
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggs/app/client/internal/conf"
+	"github.com/godyy/ggs/app/client/internal/mode"
+	"github.com/godyy/ggs/app/client/internal/mode/internal/session"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/record"
+	authjwt "github.com/godyy/ggskit/base/auth/jwt"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// skipReqs 回放时跳过的请求, 登录与会话恢复由回放会话自行完成.
+var skipReqs = map[string]bool{
+	"LoginReq":  true,
+	"ResumeReq": true,
+}
+
+// step 回放步骤, 即录制中的一个请求及其原始响应.
+type step struct {
+	entry *record.Entry // 请求.
+	req   proto.Message // 请求消息.
+	resp  proto.Message // 原始响应, 录制中缺失时为 nil.
+}
+
+// plan 回放计划.
+type plan struct {
+	steps  []*step
+	pushes map[string]int // 原始推送数量, 消息名 -> 数量.
+}
+
+// buildPlan 由录制条目构建回放计划, 请求按 seq 匹配其后的第一个响应.
+func buildPlan(entries []*record.Entry) (*plan, error) {
+	p := &plan{pushes: make(map[string]int)}
+	pending := make(map[uint32]*step)
+	for i, e := range entries {
+		switch {
+		case e.Dir == record.DirC2S && e.Pt == codecc2s.PtReq:
+			if skipReqs[e.ShortName()] {
+				continue
+			}
+			req, err := e.Message()
+			if err != nil {
+				return nil, pkgerrors.WithMessagef(err, "entry %d", i)
+			}
+			s := &step{entry: e, req: req}
+			p.steps = append(p.steps, s)
+			pending[e.Seq] = s
+		case e.Dir == record.DirS2C && e.Pt == codecc2s.PtResp:
+			s := pending[e.Seq]
+			if s == nil {
+				continue
+			}
+			delete(pending, e.Seq)
+			resp, err := e.Message()
+			if err != nil {
+				return nil, pkgerrors.WithMessagef(err, "entry %d", i)
+			}
+			s.resp = resp
+		case e.Dir == record.DirS2C && e.Pt == codecc2s.PtPush:
+			p.pushes[e.ShortName()]++
+		}
+	}
+	return p, nil
+}
+
+// Replay 回放模式, 将 Agent 录制的玩家请求按原始间隔重新发送至测试环境, 并与原始响应比较.
+// 存在差异或请求失败时以非零状态码退出.
+type Replay struct {
+	signKey any
+	plan    *plan
+	sess    *session.Session
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	pushMtx sync.Mutex
+	pushes  map[string]int // 回放收到的推送数量, 消息名 -> 数量.
+}
+
+func init() {
+	// 注册模块
+	mode.RegisterMode("replay", func() mode.Mode {
+		applyFlags()
+		entries, err := record.ReadFile(filePath)
+		if err != nil {
+			log.Fatalf("read -replay-file failed: %v", err)
+		}
+		p, err := buildPlan(entries)
+		if err != nil {
+			log.Fatalf("build replay plan failed: %v", err)
+		}
+		signKey, err := authjwt.LoadPrivKey(conf.SignKeyPath)
+		if err != nil {
+			log.Fatalf("load sign key failed: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		return &Replay{
+			signKey: signKey,
+			plan:    p,
+			sess:    session.New(uid, nil),
+			ctx:     ctx,
+			cancel:  cancel,
+			pushes:  make(map[string]int),
+		}
+	})
+}
+
+// Start 启动
+func (r *Replay) Start() {
+	log.Printf("replay %s start, requests=%d uid=%s server-id=%d speed=%g",
+		filePath, len(r.plan.steps), uid, serverId, speed)
+	r.wg.Add(1)
+	go r.run()
+}
+
+// Stop 停止
+func (r *Replay) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// run 登录并回放, 结束后输出报告并退出进程.
+func (r *Replay) run() {
+	defer r.wg.Done()
+
+	if err := r.sess.Login(r.ctx, r.signKey, serverId); err != nil {
+		if r.ctx.Err() != nil {
+			return
+		}
+		log.Fatalf("replay login failed: %v", err)
+	}
+	defer r.sess.Close()
+	go r.countPushes()
+
+	failed := 0
+	var last time.Time
+	for i, s := range r.plan.steps {
+		if !last.IsZero() && speed > 0 {
+			select {
+			case <-time.After(time.Duration(float64(s.entry.Time.Sub(last)) / speed)):
+			case <-r.ctx.Done():
+				return
+			}
+		}
+		last = s.entry.Time
+
+		if !r.replayStep(i, s) {
+			failed++
+		}
+	}
+
+	r.report(log.Writer(), failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// replayStep 回放单个请求并与原始响应比较, 返回是否一致.
+func (r *Replay) replayStep(i int, s *step) bool {
+	name := s.entry.ShortName()
+	resp, err := r.sess.Request(s.req)
+	if err != nil {
+		log.Printf("[%d] %s seq=%d: request failed, %v", i, name, s.entry.Seq, err)
+		return false
+	}
+	if s.resp == nil {
+		log.Printf("[%d] %s seq=%d: no original response, skip diff", i, name, s.entry.Seq)
+		return true
+	}
+	diffs, err := diffMessages(s.resp, resp, ignore)
+	if err != nil {
+		log.Printf("[%d] %s seq=%d: diff failed, %v", i, name, s.entry.Seq, err)
+		return false
+	}
+	if len(diffs) == 0 {
+		return true
+	}
+	log.Printf("[%d] %s seq=%d: %d differences", i, name, s.entry.Seq, len(diffs))
+	for _, d := range diffs {
+		log.Printf("    %s", d)
+	}
+	return false
+}
+
+// countPushes 统计回放收到的推送.
+func (r *Replay) countPushes() {
+	for {
+		select {
+		case push := <-r.sess.Pushes():
+			r.pushMtx.Lock()
+			r.pushes[string(push.ProtoReflect().Descriptor().Name())]++
+			r.pushMtx.Unlock()
+		case <-r.sess.Closed():
+			return
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// report 输出回放报告.
+func (r *Replay) report(w io.Writer, failed int) {
+	fmt.Fprintf(w, "replay report: requests=%d matched=%d mismatched=%d\n",
+		len(r.plan.steps), len(r.plan.steps)-failed, failed)
+
+	r.pushMtx.Lock()
+	defer r.pushMtx.Unlock()
+	names := make(map[string]struct{})
+	for name := range r.plan.pushes {
+		names[name] = struct{}{}
+	}
+	for name := range r.pushes {
+		names[name] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		fmt.Fprintf(w, "push: %s original=%d replay=%d\n", name, r.plan.pushes[name], r.pushes[name])
+	}
+}
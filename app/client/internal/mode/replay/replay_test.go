@@ -0,0 +1,59 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godyy/ggs/internal/infra/actor/protocol/record"
+	c2sregistry "github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcom "github.com/godyy/ggs/pkg/protocol/pb/common"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func entry(t *testing.T, dir record.Dir, pt int8, seq uint32, msg proto.Message) *record.Entry {
+	p, err := codecc2s.EncodePacket(c2sregistry.Registry, pt, seq, msg)
+	require.NoError(t, err)
+	return record.NewEntry(time.Now(), dir, p)
+}
+
+func TestBuildPlan(t *testing.T) {
+	entries := []*record.Entry{
+		entry(t, record.DirC2S, codecc2s.PtReq, 1, &pbc2s.LoginReq{Token: "t"}),
+		entry(t, record.DirS2C, codecc2s.PtResp, 1, &pbc2s.LoginResp{}),
+		entry(t, record.DirC2S, codecc2s.PtReq, 2, &pbc2s.UseItemReq{ItemId: 1, Num: 1}),
+		entry(t, record.DirS2C, codecc2s.PtPush, 0, &pbc2s.ItemPush{}),
+		entry(t, record.DirS2C, codecc2s.PtResp, 2, &pbc2s.UseItemResp{ItemId: 1, Num: 1, LeftNum: 9}),
+		entry(t, record.DirC2S, codecc2s.PtReq, 3, &pbc2s.HeartbeatReq{}),
+	}
+	p, err := buildPlan(entries)
+	require.NoError(t, err)
+	require.Len(t, p.steps, 2)
+	assert.True(t, proto.Equal(&pbc2s.UseItemReq{ItemId: 1, Num: 1}, p.steps[0].req))
+	assert.True(t, proto.Equal(&pbc2s.UseItemResp{ItemId: 1, Num: 1, LeftNum: 9}, p.steps[0].resp))
+	assert.Nil(t, p.steps[1].resp)
+	assert.Equal(t, map[string]int{"ItemPush": 1}, p.pushes)
+}
+
+func TestDiffMessages(t *testing.T) {
+	want := &pbc2s.UseItemResp{ItemId: 1, Num: 1, LeftNum: 9}
+
+	diffs, err := diffMessages(want, proto.Clone(want), nil)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	diffs, err = diffMessages(want, &pbc2s.UseItemResp{ItemId: 1, Num: 1, LeftNum: 8}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leftNum: want 9, got 8"}, diffs)
+
+	diffs, err = diffMessages(want, &pbc2s.UseItemResp{ItemId: 1, Num: 1, LeftNum: 8}, map[string]bool{"UseItemResp.leftNum": true})
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	diffs, err = diffMessages(want, &pbcom.Error{Code: 1}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"type: want UseItemResp, got Error"}, diffs)
+}
@@ -0,0 +1,84 @@
+package robot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/godyy/ggs/app/client/internal/mode/internal/session"
+)
+
+// bot 模拟玩家, 完成登录流程后按场景循环发送请求, 或执行一次场景脚本.
+type bot struct {
+	robot *Robot
+	uid   string
+	sess  *session.Session
+}
+
+func newBot(r *Robot, index int64) *bot {
+	uid := fmt.Sprintf("%s%d", uidPrefix, index)
+	return &bot{
+		robot: r,
+		uid:   uid,
+		sess:  session.New(uid, r.stats),
+	}
+}
+
+// run 运行机器人, 直至 ctx 结束、连接断开或脚本执行结束.
+func (b *bot) run(ctx context.Context) {
+	stats := b.robot.stats
+	stats.robotStarted()
+	if err := b.sess.Login(ctx, b.robot.signKey, serverId); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("robot %s login failed, %v", b.uid, err)
+			stats.robotFailed()
+			if b.robot.script != nil {
+				stats.scriptDone(false)
+			}
+		}
+		return
+	}
+	defer b.sess.Close()
+	stats.robotOnline()
+
+	if b.robot.script != nil {
+		b.runScript(ctx)
+		return
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.sess.Closed():
+			log.Printf("robot %s disconnected", b.uid)
+			stats.robotFailed()
+			return
+		case <-timer.C:
+		}
+
+		req := b.robot.scenario.pick().build(b)
+		if _, err := b.sess.Request(req); errors.Is(err, session.ErrClosed) {
+			log.Printf("robot %s disconnected", b.uid)
+			stats.robotFailed()
+			return
+		}
+		timer.Reset(interval)
+	}
+}
+
+// runScript 执行场景脚本并记录结果, 因 ctx 结束而中断时不计入结果.
+func (b *bot) runScript(ctx context.Context) {
+	err := b.robot.script.Run(ctx, b.sess)
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		log.Printf("robot %s script failed, %v", b.uid, err)
+	}
+	b.robot.stats.scriptDone(err == nil)
+}
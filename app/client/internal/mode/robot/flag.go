@@ -0,0 +1,60 @@
+package robot
+
+import (
+	"log"
+	"time"
+
+	"github.com/godyy/ggskit/base/flags"
+)
+
+var (
+	count      int64         // 机器人数量
+	uidPrefix  string        // 机器人用户ID前缀
+	serverId   int64         // 服务器ID
+	rampUp     time.Duration // 全部机器人启动完成的时长
+	duration   time.Duration // 压测时长, 为 0 表示持续至进程退出
+	interval   time.Duration // 机器人两次请求之间的间隔
+	scenario   string        // 请求场景, 例如 HeartbeatReq=1,UseItemReq=3
+	itemId     int64         // UseItemReq 使用的道具ID
+	scriptPath string        // 场景脚本路径, 指定时每个机器人执行一次脚本
+)
+
+func init() {
+	flags.Int64("robot-count", 10, "robot count")
+	flags.String("robot-uid-prefix", "robot", "robot uid prefix, uid is <prefix><index>")
+	flags.Int64("robot-server-id", 1, "robot server id")
+	flags.String("robot-ramp-up", "10s", "duration to start all robots")
+	flags.String("robot-duration", "1m", "test duration, 0 means until shutdown")
+	flags.String("robot-interval", "200ms", "interval between requests of each robot")
+	flags.String("robot-scenario", defaultScenario, "weighted requests, name=weight[,name=weight...]")
+	flags.Int64("robot-item-id", 1, "item id used by UseItemReq")
+	flags.String("robot-script", "", "scenario script (yaml/json) run once by each robot instead of -robot-scenario")
+}
+
+func applyFlags() {
+	count, _ = flags.GetValue[int64]("robot-count")
+	if count <= 0 {
+		log.Fatal("-robot-count must > 0")
+	}
+	uidPrefix, _ = flags.GetValue[string]("robot-uid-prefix")
+	serverId, _ = flags.GetValue[int64]("robot-server-id")
+	rampUp = durationFlag("robot-ramp-up")
+	duration = durationFlag("robot-duration")
+	interval = durationFlag("robot-interval")
+	scenario, _ = flags.GetValue[string]("robot-scenario")
+	itemId, _ = flags.GetValue[int64]("robot-item-id")
+	scriptPath, _ = flags.GetValue[string]("robot-script")
+}
+
+// durationFlag 解析时长类型的 flag.
+func durationFlag(name string) time.Duration {
+	s, _ := flags.GetValue[string](name)
+	if s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		log.Fatalf("-%s is invalid: %s", name, s)
+	}
+	return d
+}
@@ -1 +1,133 @@
 package robot
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggs/app/client/internal/conf"
+	"github.com/godyy/ggs/app/client/internal/mode"
+	"github.com/godyy/ggs/app/client/internal/mode/internal/script"
+	authjwt "github.com/godyy/ggskit/base/auth/jwt"
+)
+
+// Robot 压测模式, 按设定的速率启动多个模拟玩家, 结束时输出吞吐、各消息类型的延迟分位数与错误码.
+// 指定场景脚本时, 每个模拟玩家执行一次脚本, 全部执行结束后退出, 存在失败时以非零状态码退出.
+type Robot struct {
+	signKey  any
+	scenario *Scenario
+	script   *script.Script
+	stats    *Stats
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	reportOnce sync.Once
+}
+
+func init() {
+	// 注册模块
+	mode.RegisterMode("robot", func() mode.Mode {
+		applyFlags()
+		sc, err := ParseScenario(scenario)
+		if err != nil {
+			log.Fatalf("parse -robot-scenario failed: %v", err)
+		}
+		var sp *script.Script
+		if scriptPath != "" {
+			if sp, err = script.Load(scriptPath); err != nil {
+				log.Fatalf("load -robot-script failed: %v", err)
+			}
+		}
+		signKey, err := authjwt.LoadPrivKey(conf.SignKeyPath)
+		if err != nil {
+			log.Fatalf("load sign key failed: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		return &Robot{
+			signKey:  signKey,
+			scenario: sc,
+			script:   sp,
+			stats:    NewStats(),
+			ctx:      ctx,
+			cancel:   cancel,
+		}
+	})
+}
+
+// Start 启动
+func (r *Robot) Start() {
+	log.Printf("robot start, count=%d ramp-up=%s duration=%s interval=%s scenario=%s script=%s",
+		count, rampUp, duration, interval, scenario, scriptPath)
+	r.wg.Add(1)
+	go r.spawn()
+	if r.script != nil {
+		// 全部机器人执行脚本结束后退出.
+		go func() {
+			r.wg.Wait()
+			r.finish()
+			os.Exit(r.exitCode())
+		}()
+	}
+	if duration > 0 {
+		go func() {
+			select {
+			case <-time.After(duration):
+				r.finish()
+				os.Exit(r.exitCode())
+			case <-r.ctx.Done():
+			}
+		}()
+	}
+}
+
+// exitCode 返回进程退出码, 存在脚本执行失败的机器人时返回 1.
+func (r *Robot) exitCode() int {
+	if r.stats.ScriptsFailed() > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Stop 停止
+func (r *Robot) Stop() {
+	r.finish()
+}
+
+// spawn 在 rampUp 时长内均匀启动全部机器人.
+func (r *Robot) spawn() {
+	defer r.wg.Done()
+
+	var step time.Duration
+	if count > 1 {
+		step = rampUp / time.Duration(count-1)
+	}
+	for i := range count {
+		if i > 0 && step > 0 {
+			select {
+			case <-time.After(step):
+			case <-r.ctx.Done():
+				return
+			}
+		}
+		if r.ctx.Err() != nil {
+			return
+		}
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			newBot(r, i+1).run(r.ctx)
+		}()
+	}
+}
+
+// finish 停止全部机器人并输出报告, 仅执行一次.
+func (r *Robot) finish() {
+	r.reportOnce.Do(func() {
+		r.cancel()
+		r.wg.Wait()
+		r.stats.Report(log.Writer())
+	})
+}
@@ -0,0 +1,48 @@
+package robot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScenario(t *testing.T) {
+	sc, err := ParseScenario("Heartbeat=1, UseItemReq=3,ModifyNameReq=0")
+	require.NoError(t, err)
+	assert.Equal(t, 4, sc.total)
+	require.Len(t, sc.entries, 2)
+	assert.Equal(t, "HeartbeatReq", sc.entries[0].name)
+	assert.Equal(t, "UseItemReq", sc.entries[1].name)
+
+	_, err = ParseScenario("UseItemReq")
+	assert.Error(t, err)
+	_, err = ParseScenario("UseItemReq=-1")
+	assert.Error(t, err)
+	_, err = ParseScenario("ModifyNameReq=0")
+	assert.Error(t, err)
+}
+
+func TestScenarioPickWeighted(t *testing.T) {
+	sc, err := ParseScenario("HeartbeatReq=1,UseItemReq=3")
+	require.NoError(t, err)
+
+	counts := make(map[string]int)
+	for range 4000 {
+		counts[sc.pick().name]++
+	}
+	assert.InDelta(t, 1000, counts["HeartbeatReq"], 200)
+	assert.InDelta(t, 3000, counts["UseItemReq"], 200)
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 0.5))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 0.99))
+	assert.Equal(t, 100*time.Millisecond, percentile(latencies, 1))
+	assert.Equal(t, time.Duration(0), percentile(nil, 0.5))
+}
@@ -0,0 +1,110 @@
+package robot
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// defaultScenario 默认请求场景.
+const defaultScenario = "HeartbeatReq=1,UseItemReq=3,ModifyNameReq=1"
+
+// reqBuilder 构造请求消息.
+type reqBuilder func(b *bot) proto.Message
+
+// reqBuilders 需要填充参数的请求, 其余请求以空消息发送.
+var reqBuilders = map[string]reqBuilder{
+	"HeartbeatReq": func(b *bot) proto.Message {
+		return &pbc2s.HeartbeatReq{}
+	},
+	"UseItemReq": func(b *bot) proto.Message {
+		return &pbc2s.UseItemReq{ItemId: int32(itemId), Num: 1}
+	},
+	"ModifyNameReq": func(b *bot) proto.Message {
+		return &pbc2s.ModifyNameReq{Name: fmt.Sprintf("%s_%d", b.uid, rand.IntN(10000))}
+	},
+}
+
+// scenarioEntry 场景中的一种请求.
+type scenarioEntry struct {
+	name   string
+	weight int
+	build  reqBuilder
+}
+
+// Scenario 按权重随机选择请求的场景.
+type Scenario struct {
+	entries []scenarioEntry
+	total   int
+}
+
+// ParseScenario 解析场景, 格式为 name=weight[,name=weight...], name 可省略 Req 后缀.
+func ParseScenario(s string) (*Scenario, error) {
+	sc := &Scenario{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weightStr, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid scenario entry %q", part)
+		}
+		name = strings.TrimSpace(name)
+		if !strings.HasSuffix(name, "Req") {
+			name += "Req"
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight of %s: %q", name, weightStr)
+		}
+		if weight == 0 {
+			continue
+		}
+		build, err := findReqBuilder(name)
+		if err != nil {
+			return nil, err
+		}
+		sc.entries = append(sc.entries, scenarioEntry{name: name, weight: weight, build: build})
+		sc.total += weight
+	}
+	if sc.total == 0 {
+		return nil, fmt.Errorf("scenario is empty")
+	}
+	return sc, nil
+}
+
+// findReqBuilder 查找请求构造器, 未预置的请求按消息名从协议注册表中查找.
+func findReqBuilder(name string) (reqBuilder, error) {
+	if build, ok := reqBuilders[name]; ok {
+		return build, nil
+	}
+	fullName := protoreflect.FullName("c2s." + name)
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(fullName)
+	if err != nil {
+		return nil, fmt.Errorf("message %s not found", fullName)
+	}
+	if _, ok := c2s.Registry.GetPid(mt.New().Interface()); !ok {
+		return nil, fmt.Errorf("message %s not registered", fullName)
+	}
+	return func(*bot) proto.Message { return mt.New().Interface() }, nil
+}
+
+// pick 按权重随机选择请求.
+func (sc *Scenario) pick() *scenarioEntry {
+	n := rand.IntN(sc.total)
+	for i := range sc.entries {
+		if n < sc.entries[i].weight {
+			return &sc.entries[i]
+		}
+		n -= sc.entries[i].weight
+	}
+	return &sc.entries[len(sc.entries)-1]
+}
@@ -0,0 +1,184 @@
+package robot
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stats 压测统计, 按消息类型统计请求数量、延迟与错误码.
+type Stats struct {
+	mtx   sync.Mutex
+	start time.Time
+	types map[string]*typeStats
+
+	robotsStarted int // 已启动的机器人数量
+	robotsOnline  int // 已登录的机器人数量
+	robotsFailed  int // 异常结束的机器人数量
+	scriptsPassed int // 脚本执行通过的机器人数量
+	scriptsFailed int // 脚本执行失败的机器人数量
+}
+
+// typeStats 单个消息类型的统计.
+type typeStats struct {
+	latencies []time.Duration // 成功响应的延迟, 包括返回错误码的响应
+	errCodes  map[int32]int   // 错误码 -> 次数
+	failures  map[string]int  // 请求失败原因 -> 次数, 例如超时、连接断开
+	pushes    int             // 收到的推送数量
+}
+
+// NewStats 创建统计.
+func NewStats() *Stats {
+	return &Stats{
+		start: time.Now(),
+		types: make(map[string]*typeStats),
+	}
+}
+
+// getType 返回消息类型的统计, 须持有锁.
+func (s *Stats) getType(name string) *typeStats {
+	t := s.types[name]
+	if t == nil {
+		t = &typeStats{
+			errCodes: make(map[int32]int),
+			failures: make(map[string]int),
+		}
+		s.types[name] = t
+	}
+	return t
+}
+
+// RecordResp 记录收到响应的请求, code 为 0 表示成功.
+func (s *Stats) RecordResp(name string, latency time.Duration, code int32) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	t := s.getType(name)
+	t.latencies = append(t.latencies, latency)
+	if code != 0 {
+		t.errCodes[code]++
+	}
+}
+
+// RecordFailure 记录未收到响应的请求.
+func (s *Stats) RecordFailure(name string, reason string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.getType(name).failures[reason]++
+}
+
+// RecordPush 记录收到的推送.
+func (s *Stats) RecordPush(name string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.getType(name).pushes++
+}
+
+// robotStarted 记录机器人启动.
+func (s *Stats) robotStarted() {
+	s.mtx.Lock()
+	s.robotsStarted++
+	s.mtx.Unlock()
+}
+
+// robotOnline 记录机器人登录成功.
+func (s *Stats) robotOnline() {
+	s.mtx.Lock()
+	s.robotsOnline++
+	s.mtx.Unlock()
+}
+
+// robotFailed 记录机器人异常结束.
+func (s *Stats) robotFailed() {
+	s.mtx.Lock()
+	s.robotsFailed++
+	s.mtx.Unlock()
+}
+
+// scriptDone 记录机器人脚本执行结果.
+func (s *Stats) scriptDone(passed bool) {
+	s.mtx.Lock()
+	if passed {
+		s.scriptsPassed++
+	} else {
+		s.scriptsFailed++
+	}
+	s.mtx.Unlock()
+}
+
+// ScriptsFailed 返回脚本执行失败的机器人数量.
+func (s *Stats) ScriptsFailed() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.scriptsFailed
+}
+
+// percentile 返回已排序延迟的 p 分位数(0~1).
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// Report 输出统计报告.
+func (s *Stats) Report(w io.Writer) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	elapsed := time.Since(s.start)
+	fmt.Fprintf(w, "robot report: elapsed=%s robots started=%d online=%d failed=%d\n",
+		elapsed.Round(time.Millisecond), s.robotsStarted, s.robotsOnline, s.robotsFailed)
+	if s.scriptsPassed+s.scriptsFailed > 0 {
+		fmt.Fprintf(w, "script report: passed=%d failed=%d\n", s.scriptsPassed, s.scriptsFailed)
+	}
+
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "%-24s %8s %8s %8s %10s %10s %10s %10s %10s %8s\n",
+		"type", "count", "errors", "failures", "qps", "p50", "p90", "p99", "max", "pushes")
+	for _, name := range names {
+		t := s.types[name]
+		latencies := slices.Clone(t.latencies)
+		slices.Sort(latencies)
+		errors, failures := 0, 0
+		for _, n := range t.errCodes {
+			errors += n
+		}
+		for _, n := range t.failures {
+			failures += n
+		}
+		qps := float64(len(latencies)) / elapsed.Seconds()
+		fmt.Fprintf(w, "%-24s %8d %8d %8d %10.1f %10s %10s %10s %10s %8d\n",
+			name, len(latencies), errors, failures, qps,
+			percentile(latencies, 0.5), percentile(latencies, 0.9), percentile(latencies, 0.99),
+			percentile(latencies, 1), t.pushes)
+	}
+
+	for _, name := range names {
+		t := s.types[name]
+		codes := make([]int32, 0, len(t.errCodes))
+		for code := range t.errCodes {
+			codes = append(codes, code)
+		}
+		slices.Sort(codes)
+		for _, code := range codes {
+			fmt.Fprintf(w, "error code: %s code=%d count=%d\n", name, code, t.errCodes[code])
+		}
+		reasons := make([]string, 0, len(t.failures))
+		for reason := range t.failures {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Fprintf(w, "failure: %s reason=%s count=%d\n", name, reason, t.failures[reason])
+		}
+	}
+}
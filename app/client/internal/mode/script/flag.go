@@ -0,0 +1,31 @@
+package script
+
+import (
+	"log"
+
+	"github.com/godyy/ggskit/base/flags"
+)
+
+var (
+	scriptPath string // 场景脚本路径
+	uid        string // 用户ID
+	serverId   int64  // 服务器ID
+)
+
+func init() {
+	flags.String("script", "", "scenario script path (yaml/json)")
+	flags.String("script-uid", "script", "user id to run the script")
+	flags.Int64("script-server-id", 1, "server id to run the script")
+}
+
+func applyFlags() {
+	scriptPath, _ = flags.GetValue[string]("script")
+	if scriptPath == "" {
+		log.Fatal("-script is empty")
+	}
+	uid, _ = flags.GetValue[string]("script-uid")
+	if uid == "" {
+		log.Fatal("-script-uid is empty")
+	}
+	serverId, _ = flags.GetValue[int64]("script-server-id")
+}
@@ -0,0 +1,86 @@
+package script
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/godyy/ggs/app/client/internal/conf"
+	"github.com/godyy/ggs/app/client/internal/mode"
+	iscript "github.com/godyy/ggs/app/client/internal/mode/internal/script"
+	"github.com/godyy/ggs/app/client/internal/mode/internal/session"
+	authjwt "github.com/godyy/ggskit/base/auth/jwt"
+)
+
+// Script 脚本模式, 登录后执行场景脚本, 执行结束后退出.
+// 断言失败或执行出错时以非零状态码退出, 可用于发布前的冒烟检查.
+type Script struct {
+	signKey any
+	script  *iscript.Script
+	sess    *session.Session
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func init() {
+	// 注册模块
+	mode.RegisterMode("script", func() mode.Mode {
+		applyFlags()
+		sp, err := iscript.Load(scriptPath)
+		if err != nil {
+			log.Fatalf("load -script failed: %v", err)
+		}
+		signKey, err := authjwt.LoadPrivKey(conf.SignKeyPath)
+		if err != nil {
+			log.Fatalf("load sign key failed: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		return &Script{
+			signKey: signKey,
+			script:  sp,
+			sess:    session.New(uid, nil),
+			ctx:     ctx,
+			cancel:  cancel,
+		}
+	})
+}
+
+// Start 启动
+func (s *Script) Start() {
+	log.Printf("script %q start, uid=%s server-id=%d", s.script.Name, uid, serverId)
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop 停止
+func (s *Script) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run 登录并执行脚本, 结束后退出进程.
+func (s *Script) run() {
+	defer s.wg.Done()
+
+	if err := s.sess.Login(s.ctx, s.signKey, serverId); err != nil {
+		if s.ctx.Err() != nil {
+			return
+		}
+		log.Fatalf("script %q login failed: %v", s.script.Name, err)
+	}
+	defer s.sess.Close()
+
+	err := s.script.Run(s.ctx, s.sess)
+	switch {
+	case err == nil:
+		log.Printf("script %q passed", s.script.Name)
+		os.Exit(0)
+	case s.ctx.Err() != nil:
+		log.Printf("script %q interrupted", s.script.Name)
+	default:
+		log.Fatalf("script %q failed: %v", s.script.Name, err)
+	}
+}
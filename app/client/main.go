@@ -4,7 +4,9 @@ import (
 	"github.com/godyy/ggs/app/client/internal/conf"
 	"github.com/godyy/ggs/app/client/internal/mode"
 	_ "github.com/godyy/ggs/app/client/internal/mode/client"
+	_ "github.com/godyy/ggs/app/client/internal/mode/replay"
 	_ "github.com/godyy/ggs/app/client/internal/mode/robot"
+	_ "github.com/godyy/ggs/app/client/internal/mode/script"
 	"github.com/godyy/ggs/internal/base/logger"
 	"github.com/godyy/ggskit/base/flags"
 	baselogger "github.com/godyy/ggskit/base/logger"
@@ -0,0 +1,38 @@
+// devstack 在同一进程中运行 Login、Platform、Agent 与 Game 服务, 使用本地替身代替
+// Redis、etcd 与 MongoDB, 无需任何外部服务即可登录客户端.
+package main
+
+import (
+	"log"
+
+	"github.com/godyy/ggs/app/devstack/stack"
+	"github.com/godyy/ggs/internal/base/appflags"
+	"github.com/godyy/ggskit/base/flags"
+	"github.com/godyy/ggskit/utils"
+)
+
+func init() {
+	flags.String("root", ".", "repository root, used to locate configs, keys and excels")
+	flags.String("data-dir", "", "standin data dir, empty means a temp dir removed on exit")
+}
+
+func main() {
+	flags.Parse()
+
+	opts := &stack.Options{}
+	opts.Root, _ = flags.GetValue[string]("root")
+	opts.DataDir, _ = flags.GetValue[string]("data-dir")
+	if serverId, ok := appflags.ServerID(); ok {
+		opts.ServerID = serverId
+	}
+	s, err := stack.Start(opts)
+	flags.Reset()
+	if err != nil {
+		log.Fatalf("start devstack failed, %v", err)
+	}
+	log.Printf("devstack ready, login %s, platform %s, agent %s, server %d",
+		s.LoginURLRoot, s.PlatformURLRoot, s.AgentAddr, s.ServerID)
+
+	utils.ListenShutdown()
+	s.Stop()
+}
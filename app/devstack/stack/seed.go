@@ -0,0 +1,76 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/godyy/gexcels"
+	exportdata "github.com/godyy/gexcels/export/data"
+	"github.com/godyy/gexcels/parse"
+	pkgerrors "github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// gdconfDB 配置表数据库名称, 与 Game 服务读取的数据库一致.
+const gdconfDB = "gdconf"
+
+// seedGdconf 解析 root/excels 下的配置表并导入 MongoDB 替身, 与 make gdconf 的数据导出一致.
+func seedGdconf(root, mongoURI string) error {
+	excelPath := filepath.Join(root, "excels")
+	parser, err := parse.Parse(excelPath, &parse.Options{
+		Tags:        []gexcels.Tag{"s"},
+		EnumFiles:   []string{filepath.Join(excelPath, "枚举定义.xlsx")},
+		StructFiles: []string{filepath.Join(excelPath, "结构体定义.xlsx")},
+	})
+	if err != nil {
+		return pkgerrors.WithMessagef(err, "parse excel at %s", excelPath)
+	}
+
+	cli, err := mongo.Connect(options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		return pkgerrors.WithMessage(err, "connect mongo")
+	}
+	defer cli.Disconnect(context.Background())
+
+	if err := exportdata.ExportBson(parser, cli.Database(gdconfDB)); err != nil {
+		return pkgerrors.WithMessage(err, "export bson")
+	}
+	return nil
+}
+
+// commonResp 登录、平台服务的通用响应.
+type commonResp struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// createServer 通过平台服务创建服务器.
+func createServer(platformURLRoot string, serverId int64) error {
+	body, err := json.Marshal(map[string]any{
+		"id":   serverId,
+		"name": fmt.Sprintf("devstack-%d", serverId),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(platformURLRoot+"/server/create", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return pkgerrors.WithMessage(err, "post server create")
+	}
+	defer resp.Body.Close()
+
+	var r commonResp
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return pkgerrors.WithMessagef(err, "decode server create resp, status %d", resp.StatusCode)
+	}
+	if r.Code != 0 {
+		return fmt.Errorf("server create failed, code %d, %s", r.Code, r.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+// Package stack 在同一进程中启动 Login、Platform、Agent 与 Game 服务.
+//
+// Redis、etcd 与 MongoDB 由本地替身代替: Redis 使用 miniredis, etcd 使用内嵌 etcd,
+// MongoDB 使用以 SQLite 为后端的 FerretDB. Actor 注册表、服务器存储与分布式锁仍走
+// Redis 协议, 集群节点间仍通过本地回环 TCP 通信, 与独立部署时的代码路径一致.
+package stack
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	agentboot "github.com/godyy/ggs/app/agent/boot"
+	gameboot "github.com/godyy/ggs/app/game/boot"
+	loginboot "github.com/godyy/ggs/app/login/boot"
+	platformboot "github.com/godyy/ggs/app/platform/boot"
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	readyTimeout     = 30 * time.Second                   // 等待服务就绪的时限.
+	userTokenKeyPath = "configs/secret_key/auth_priv.pem" // 用户令牌签名私钥, 与登录服务的鉴权公钥配对.
+)
+
+// Options 启动选项.
+type Options struct {
+	// Root 仓库根目录, 用于定位配置文件、密钥与配置表, 默认为当前目录.
+	Root string
+
+	// ServerID 服务器ID, 默认为 1.
+	ServerID int64
+
+	// DataDir 替身服务的数据目录, 为空时使用临时目录并在停止时删除.
+	DataDir string
+
+	// FreePorts 为 true 时所有服务监听随机的空闲端口, 否则使用各服务 dev.toml 中的端口.
+	FreePorts bool
+}
+
+// Stack 运行中的服务集合.
+type Stack struct {
+	LoginURLRoot    string // 登录服务 API 根路径.
+	PlatformURLRoot string // 平台服务 API 根路径.
+	AgentAddr       string // Agent 对客户端的监听地址.
+	AgentHttpAddr   string // Agent 管理接口地址, 为空表示未启用.
+	GameHttpAddr    string // Game 管理接口地址, 为空表示未启用.
+	GameAdminToken  string // Game 管理令牌.
+	SignKeyPath     string // 用户令牌签名私钥路径.
+	ServerID        int64  // 服务器ID.
+	Root            string // 仓库根目录.
+	RedisAddr       string // Redis 替身地址.
+	MongoURI        string // MongoDB 替身地址.
+	EtcdEndpoint    string // 内嵌 etcd 的客户端地址.
+	EtcdRoot        string // 集群在 etcd 中的根路径.
+
+	standins *standins
+	dataDir  string // 需在停止时删除的临时数据目录.
+	appStops []func()
+}
+
+// Start 启动替身服务以及 Platform、Login、Game、Agent 服务, 并等待 Agent、Game 节点就绪.
+// 各服务启动失败时终止进程.
+func Start(opts *Options) (*Stack, error) {
+	o := Options{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Root == "" {
+		o.Root = "."
+	}
+	if o.ServerID <= 0 {
+		o.ServerID = 1
+	}
+	root, err := filepath.Abs(o.Root)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "root path")
+	}
+
+	s := &Stack{ServerID: o.ServerID, Root: root}
+
+	// 准备数据目录.
+	dataDir := o.DataDir
+	if dataDir == "" {
+		if dataDir, err = os.MkdirTemp("", "ggs-devstack-"); err != nil {
+			return nil, pkgerrors.WithMessage(err, "make data dir")
+		}
+		s.dataDir = dataDir
+	}
+
+	// 启动替身服务.
+	if s.standins, err = startStandins(dataDir); err != nil {
+		s.Stop()
+		return nil, pkgerrors.WithMessage(err, "start standins")
+	}
+	s.RedisAddr = s.standins.redisAddr()
+	s.MongoURI = s.standins.mongoURI
+	s.EtcdEndpoint = s.standins.etcdEndpoint()
+	if err := seedGdconf(root, s.standins.mongoURI); err != nil {
+		s.Stop()
+		return nil, pkgerrors.WithMessage(err, "seed gdconf")
+	}
+
+	if err := s.startApps(root, &o); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	return s, nil
+}
+
+// startApps 按依赖顺序启动各服务.
+func (s *Stack) startApps(root string, o *Options) error {
+	ports := func(port int) (int, error) {
+		if !o.FreePorts || port <= 0 {
+			return port, nil
+		}
+		return freePort()
+	}
+
+	// Platform, 创建服务器并预注册服务器 Actor.
+	platformCfg, err := platformboot.LoadConfig(filepath.Join(root, "app/platform/configs/dev.toml"))
+	if err != nil {
+		return pkgerrors.WithMessage(err, "load platform config")
+	}
+	if platformCfg.Port, err = ports(platformCfg.Port); err != nil {
+		return pkgerrors.WithMessage(err, "platform port")
+	}
+	platformCfg.DB.Redis.Addrs = []string{s.RedisAddr}
+	platformCfg.DB.Mongo.URI = s.MongoURI
+	platformboot.Start(&platformboot.Options{Config: platformCfg})
+	s.appStops = append(s.appStops, platformboot.Stop)
+	s.PlatformURLRoot = "http://" + localAddr(platformCfg.Port) + "/api/v1"
+	if err := waitListening(localAddr(platformCfg.Port)); err != nil {
+		return pkgerrors.WithMessage(err, "wait platform")
+	}
+	if err := createServer(s.PlatformURLRoot, o.ServerID); err != nil {
+		return pkgerrors.WithMessage(err, "create server")
+	}
+
+	// Login.
+	loginCfg, err := loginboot.LoadConfig(filepath.Join(root, "app/login/configs/dev.toml"))
+	if err != nil {
+		return pkgerrors.WithMessage(err, "load login config")
+	}
+	if loginCfg.Port, err = ports(loginCfg.Port); err != nil {
+		return pkgerrors.WithMessage(err, "login port")
+	}
+	loginCfg.AuthKeyPath = rootPath(root, loginCfg.AuthKeyPath)
+	loginCfg.SignKeyPath = rootPath(root, loginCfg.SignKeyPath)
+	loginCfg.DB.Redis.Addrs = []string{s.RedisAddr}
+	loginCfg.DB.Mongo.URI = s.MongoURI
+	loginCfg.NodeMeta.Endpoints = []string{s.EtcdEndpoint}
+	loginboot.Start(&loginboot.Options{Config: loginCfg})
+	s.appStops = append(s.appStops, loginboot.Stop)
+	s.LoginURLRoot = "http://" + localAddr(loginCfg.Port) + "/api/v1"
+	s.SignKeyPath = rootPath(root, userTokenKeyPath)
+	if err := waitListening(localAddr(loginCfg.Port)); err != nil {
+		return pkgerrors.WithMessage(err, "wait login")
+	}
+
+	// Game.
+	gameCfg, err := gameboot.LoadConfig(filepath.Join(root, "app/game/configs/dev.toml"))
+	if err != nil {
+		return pkgerrors.WithMessage(err, "load game config")
+	}
+	if gameCfg.Cluster.Port, err = ports(gameCfg.Cluster.Port); err != nil {
+		return pkgerrors.WithMessage(err, "game cluster port")
+	}
+	if gameCfg.HttpPort, err = ports(gameCfg.HttpPort); err != nil {
+		return pkgerrors.WithMessage(err, "game http port")
+	}
+	gameCfg.Cluster.Core.EtcdEndPoints = []string{s.EtcdEndpoint}
+	gameCfg.DB.Redis.Addrs = []string{s.RedisAddr}
+	gameCfg.DB.Mongo.URI = s.MongoURI
+	gameCfg.EnablePProf = false
+	gameboot.Start(&gameboot.Options{Config: gameCfg, ServerID: o.ServerID})
+	s.appStops = append(s.appStops, gameboot.Stop)
+	if gameCfg.HttpPort > 0 && gameCfg.Admin.Token != "" {
+		s.GameHttpAddr = localAddr(gameCfg.HttpPort)
+		s.GameAdminToken = gameCfg.Admin.Token
+	}
+
+	// Agent.
+	agentCfg, err := agentboot.LoadConfig(filepath.Join(root, "app/agent/configs/dev.toml"))
+	if err != nil {
+		return pkgerrors.WithMessage(err, "load agent config")
+	}
+	if agentCfg.Port, err = ports(agentCfg.Port); err != nil {
+		return pkgerrors.WithMessage(err, "agent port")
+	}
+	if agentCfg.Cluster.Port, err = ports(agentCfg.Cluster.Port); err != nil {
+		return pkgerrors.WithMessage(err, "agent cluster port")
+	}
+	if agentCfg.HttpPort, err = ports(agentCfg.HttpPort); err != nil {
+		return pkgerrors.WithMessage(err, "agent http port")
+	}
+	if agentCfg.WebSocket.Port, err = ports(agentCfg.WebSocket.Port); err != nil {
+		return pkgerrors.WithMessage(err, "agent websocket port")
+	}
+	agentCfg.PublicAddr = localAddr(agentCfg.Port)
+	agentCfg.TokenKeyPath = rootPath(root, agentCfg.TokenKeyPath)
+	agentCfg.TLS.CertFile = rootPath(root, agentCfg.TLS.CertFile)
+	agentCfg.TLS.KeyFile = rootPath(root, agentCfg.TLS.KeyFile)
+	agentCfg.Cluster.Core.EtcdEndPoints = []string{s.EtcdEndpoint}
+	agentCfg.DB.Redis.Addrs = []string{s.RedisAddr}
+	agentCfg.EnablePProf = false
+	agentboot.Start(&agentboot.Options{Config: agentCfg, ServerID: o.ServerID})
+	s.appStops = append(s.appStops, agentboot.Stop)
+	s.AgentAddr = agentCfg.PublicAddr
+	if agentCfg.HttpPort > 0 && agentCfg.Admin.Token != "" {
+		s.AgentHttpAddr = localAddr(agentCfg.HttpPort)
+	}
+
+	// 等待节点发布元数据, 此后登录服务可以分配网关, 网关可以选择 Game 节点.
+	s.EtcdRoot = agentCfg.Cluster.Core.EtcdRoot
+	metaCfg := &nodemeta.Config{
+		Endpoints: []string{s.EtcdEndpoint},
+		Root:      s.EtcdRoot,
+	}
+	for _, category := range []string{consts.NodeGame, consts.NodeAgent} {
+		if err := waitNodeMeta(metaCfg, category, o.ServerID); err != nil {
+			return pkgerrors.WithMessagef(err, "wait %s node", category)
+		}
+	}
+
+	return nil
+}
+
+// Stop 按启动的逆序停止各服务与替身服务, 并删除临时数据目录.
+func (s *Stack) Stop() {
+	for i := len(s.appStops) - 1; i >= 0; i-- {
+		s.appStops[i]()
+	}
+	s.appStops = nil
+	if s.standins != nil {
+		s.standins.stop()
+		s.standins = nil
+	}
+	if s.dataDir != "" {
+		_ = os.RemoveAll(s.dataDir)
+		s.dataDir = ""
+	}
+}
+
+// rootPath 将相对路径转换为 root 下的路径.
+func rootPath(root, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// localAddr 返回本地端口地址.
+func localAddr(port int) string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+}
+
+// waitListening 等待 addr 开始接受连接.
+func waitListening(addr string) error {
+	deadline := time.Now().Add(readyTimeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn.Close()
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// waitNodeMeta 等待 category 类别下托管 serverId 的节点发布元数据.
+func waitNodeMeta(cfg *nodemeta.Config, category string, serverId int64) error {
+	w, err := nodemeta.NewWatcher(cfg, category)
+	if err != nil {
+		return pkgerrors.WithMessage(err, "new watcher")
+	}
+	if err := w.Start(); err != nil {
+		return pkgerrors.WithMessage(err, "start watcher")
+	}
+	defer w.Stop()
+
+	deadline := time.Now().Add(readyTimeout)
+	for {
+		found := false
+		w.Range(func(m *nodemeta.Meta) bool {
+			found = m.HostsServer(serverId)
+			return !found
+		})
+		if found {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return pkgerrors.New("timeout")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,165 @@
+package stack
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/FerretDB/FerretDB/ferretdb"
+	"github.com/alicebob/miniredis/v2"
+	pkgerrors "github.com/pkg/errors"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// standinStartTimeout 替身服务启动时限.
+const standinStartTimeout = 10 * time.Second
+
+// standins 本地替身服务, 替代 Redis、etcd 与 MongoDB.
+type standins struct {
+	redis *miniredis.Miniredis // Redis 替身, 承载 Actor 注册表、服务器存储与分布式锁.
+	etcd  *embed.Etcd          // 内嵌 etcd, 承载集群节点发现与节点元数据.
+
+	mongoURI    string             // MongoDB 替身地址.
+	mongoCancel context.CancelFunc // 停止 MongoDB 替身.
+	mongoDone   chan struct{}      // MongoDB 替身已停止.
+}
+
+// startStandins 启动替身服务, 数据保存在 dataDir 下.
+func startStandins(dataDir string) (*standins, error) {
+	s := &standins{}
+	if err := s.startRedis(); err != nil {
+		s.stop()
+		return nil, err
+	}
+	if err := s.startEtcd(filepath.Join(dataDir, "etcd")); err != nil {
+		s.stop()
+		return nil, err
+	}
+	if err := s.startMongo(filepath.Join(dataDir, "mongo")); err != nil {
+		s.stop()
+		return nil, err
+	}
+	return s, nil
+}
+
+// startRedis 启动 Redis 替身.
+func (s *standins) startRedis() error {
+	r := miniredis.NewMiniRedis()
+	if err := r.StartAddr("127.0.0.1:0"); err != nil {
+		return pkgerrors.WithMessage(err, "start redis")
+	}
+	s.redis = r
+	return nil
+}
+
+// startEtcd 启动内嵌 etcd.
+func (s *standins) startEtcd(dir string) error {
+	clientPort, err := freePort()
+	if err != nil {
+		return pkgerrors.WithMessage(err, "etcd client port")
+	}
+	peerPort, err := freePort()
+	if err != nil {
+		return pkgerrors.WithMessage(err, "etcd peer port")
+	}
+	clientURL := localURL("http", clientPort)
+	peerURL := localURL("http", peerPort)
+
+	cfg := embed.NewConfig()
+	cfg.Name = "devstack"
+	cfg.Dir = dir
+	cfg.LogLevel = "error"
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		return pkgerrors.WithMessage(err, "start etcd")
+	}
+	s.etcd = e
+
+	select {
+	case <-e.Server.ReadyNotify():
+		return nil
+	case err := <-e.Err():
+		return pkgerrors.WithMessage(err, "etcd")
+	case <-time.After(standinStartTimeout):
+		return pkgerrors.New("etcd start timeout")
+	}
+}
+
+// startMongo 启动以 SQLite 为后端的 MongoDB 替身.
+func (s *standins) startMongo(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return pkgerrors.WithMessage(err, "make mongo dir")
+	}
+
+	f, err := ferretdb.New(&ferretdb.Config{
+		Listener:  ferretdb.ListenerConfig{TCP: "127.0.0.1:0"},
+		Logger:    slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		Handler:   "sqlite",
+		SQLiteURL: "file:" + filepath.ToSlash(dir) + "/",
+	})
+	if err != nil {
+		return pkgerrors.WithMessage(err, "new ferretdb")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mongoCancel = cancel
+	s.mongoDone = make(chan struct{})
+	go func() {
+		defer close(s.mongoDone)
+		_ = f.Run(ctx)
+	}()
+
+	// MongoDBURI 在监听就绪后返回.
+	s.mongoURI = f.MongoDBURI()
+	return nil
+}
+
+// redisAddr 返回 Redis 替身地址.
+func (s *standins) redisAddr() string {
+	return s.redis.Addr()
+}
+
+// etcdEndpoint 返回内嵌 etcd 的客户端地址.
+func (s *standins) etcdEndpoint() string {
+	return s.etcd.Clients[0].Addr().String()
+}
+
+// stop 停止替身服务.
+func (s *standins) stop() {
+	if s.mongoCancel != nil {
+		s.mongoCancel()
+		<-s.mongoDone
+	}
+	if s.etcd != nil {
+		s.etcd.Close()
+	}
+	if s.redis != nil {
+		s.redis.Close()
+	}
+}
+
+// freePort 返回一个当前可用的本地端口.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// localURL 返回本地地址的 URL.
+func localURL(scheme string, port int) url.URL {
+	return url.URL{Scheme: scheme, Host: net.JoinHostPort("127.0.0.1", strconv.Itoa(port))}
+}
@@ -0,0 +1,29 @@
+// Package boot 在当前进程中启动 Game 服务, 供一体化开发环境与集成测试使用.
+package boot
+
+import (
+	_ "github.com/godyy/ggs/app/game/internal" // 注册 Actor 定义与消息处理器.
+	"github.com/godyy/ggs/app/game/internal/app"
+	"github.com/godyy/ggs/app/game/internal/base/config"
+)
+
+// Config Game 服务配置.
+type Config = config.Config
+
+// Options 启动选项.
+type Options = app.Options
+
+// LoadConfig 加载配置文件.
+func LoadConfig(path string) (*Config, error) {
+	return config.Load(path)
+}
+
+// Start 启动 Game 服务, 失败时终止进程.
+func Start(opts *Options) {
+	app.Start(opts)
+}
+
+// Stop 停止 Game 服务, 按配置先排空.
+func Stop() {
+	app.Stop()
+}
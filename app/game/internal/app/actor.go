@@ -28,8 +28,14 @@ func ActorService() *actor.Service {
 	return appInst.actorService
 }
 
+// ActorRegistry 返回 Actor 注册表.
+func ActorRegistry() gactor.ActorRegistry {
+	return appInst.actorRegistry
+}
+
 func (a *app) startActor() error {
-	selfNodeId := cluster.MakeNodeID(consts.NodeGame, nodeutil.MakeServerNodeName(Env().ServerID()))
+	selfNodeId := cluster.MakeNodeID(consts.NodeGame, Env().NodeName())
+	a.selfNodeId = selfNodeId
 
 	a.actorProtoReg = &actor.ProtoRegistry{
 		C2S: c2s.Registry,
@@ -40,6 +46,7 @@ func (a *app) startActor() error {
 	iactor.Init(&iactor.InitConfig{
 		Persist:           &persist.InitConfig{BD: a.mongobd},
 		DB:                a.env.DB(),
+		ActorDB:           a.actorDB,
 		AsyncSaveCallback: a.actorAsyncSaveCallback,
 		ProtoRegistry:     a.actorProtoReg,
 	})
@@ -122,17 +129,23 @@ func (a *app) startActor() error {
 	return nil
 }
 
-// startGlobalActors 启动全局Actor.
+// startGlobalActors 启动全局Actor. 服务器 Actor 固定在序号为 0 的节点, 其他序号的节点跳过.
 func (a *app) startGlobalActors() error {
+	if a.env.NodeIndex() != 0 {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*1)
 	defer cancel()
 
-	// 启动Server
-	if err := a.actorService.StartActor(ctx, gactor.ActorUID{
-		Category: iactor.CategoryServer.ActorCategory(),
-		ID:       a.env.ServerID(),
-	}); err != nil {
-		return pkgerrors.WithMessage(err, "start server actor")
+	// 启动托管的各服务器.
+	for _, serverId := range a.serverIds {
+		if err := a.actorService.StartActor(ctx, gactor.ActorUID{
+			Category: iactor.CategoryServer.ActorCategory(),
+			ID:       serverId,
+		}); err != nil {
+			return pkgerrors.WithMessagef(err, "start server actor %d", serverId)
+		}
 	}
 
 	return nil
@@ -201,7 +214,7 @@ func getNodeGroup(node *cluster.Node) (string, bool) {
 func getActorFixedNode(uid gactor.ActorUID) (string, bool) {
 	switch iactor.Category(uid.Category) {
 	case iactor.CategoryServer:
-		return cluster.MakeNodeID(consts.NodeGame, nodeutil.MakeServerNodeName(uid.ID)), true
+		return cluster.MakeNodeID(consts.NodeGame, nodeutil.MakeServerNodeName(appInst.serverHost(uid.ID))), true
 	default:
 		return "", false
 	}
@@ -215,7 +228,7 @@ func getActorNodeGroup(uid gactor.ActorUID) (string, bool) {
 		if !ok {
 			return "", false
 		}
-		return makeNodeGroup(consts.NodeGame, serverID), true
+		return makeNodeGroup(consts.NodeGame, appInst.serverHost(serverID)), true
 	default:
 		return "", false
 	}
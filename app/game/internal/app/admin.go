@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/actor/actors"
+)
+
+// adminBasePath 管理接口路径前缀.
+const adminBasePath = "/admin"
+
+// adminEnabled 返回是否启用管理接口.
+func (a *app) adminEnabled() bool {
+	return a.config.Admin.Token != ""
+}
+
+// registerAdminHttp 注册管理接口.
+func (a *app) registerAdminHttp(mux *http.ServeMux) {
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, a.adminAuth(h))
+	}
+
+	handle("POST "+adminBasePath+"/players/{playerId}/migrate", a.handleAdminMigratePlayer)
+	handle("POST "+adminBasePath+"/migrate", a.handleAdminMigratePlayers)
+	handle("GET "+adminBasePath+"/drain", a.handleAdminDrainStatus)
+	handle("POST "+adminBasePath+"/drain", a.handleAdminDrain)
+}
+
+// adminAuth 校验管理令牌, 令牌通过 Authorization: Bearer <token> 请求头传递.
+func (a *app) adminAuth(next http.Handler) http.Handler {
+	token := []byte(a.config.Admin.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), token) != 1 {
+			writeAdminError(w, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminMigrateReq 迁移玩家请求.
+type adminMigrateReq struct {
+	Target    string  `json:"target"`     // 目标节点ID.
+	PlayerIds []int64 `json:"player_ids"` // 批量迁移的玩家ID, 为空表示当前节点所有已启动的玩家.
+}
+
+// handleAdminMigratePlayer 迁移单个玩家.
+func (a *app) handleAdminMigratePlayer(w http.ResponseWriter, r *http.Request) {
+	playerId, err := strconv.ParseInt(r.PathValue("playerId"), 10, 64)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, "invalid playerId")
+		return
+	}
+	req, ok := decodeAdminMigrateReq(w, r)
+	if !ok {
+		return
+	}
+
+	logger.Get().Infof("admin migrate player %d to %s", playerId, req.Target)
+	if err := a.migratePlayer(r.Context(), playerId, req.Target); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrMigrateTarget):
+			status = http.StatusBadRequest
+		case errors.Is(err, ErrPlayerNotActive):
+			status = http.StatusNotFound
+		case errors.Is(err, actors.ErrMigrating):
+			status = http.StatusConflict
+		}
+		writeAdminError(w, status, err.Error())
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, MigrateSummary{Total: 1, Migrated: 1, Failed: make([]PlayerFailure, 0)})
+}
+
+// handleAdminMigratePlayers 批量迁移玩家.
+func (a *app) handleAdminMigratePlayers(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeAdminMigrateReq(w, r)
+	if !ok {
+		return
+	}
+	if _, err := a.checkMigrateTarget(req.Target); err != nil {
+		writeAdminError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	logger.Get().Infof("admin migrate players to %s, count=%d", req.Target, len(req.PlayerIds))
+	// 批量迁移耗时较长, 不随请求取消而中断.
+	summary := a.migratePlayers(context.WithoutCancel(r.Context()), req.PlayerIds, req.Target)
+	writeAdminJSON(w, http.StatusOK, summary)
+}
+
+// adminDrainResp 排空状态响应.
+type adminDrainResp struct {
+	Draining bool          `json:"draining"`          // 是否排空中.
+	Done     bool          `json:"done"`              // 排空是否已完成.
+	Players  int64         `json:"players"`           // 剩余已启动的玩家数量.
+	Summary  *DrainSummary `json:"summary,omitempty"` // 排空结果, 完成后有效.
+}
+
+// handleAdminDrainStatus 查询排空状态.
+func (a *app) handleAdminDrainStatus(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.drainStatus())
+}
+
+// handleAdminDrain 发起排空.
+func (a *app) handleAdminDrain(w http.ResponseWriter, r *http.Request) {
+	logger.Get().Info("admin drain requested")
+	a.drain()
+	writeAdminJSON(w, http.StatusAccepted, a.drainStatus())
+}
+
+// drainStatus 返回排空状态.
+func (a *app) drainStatus() adminDrainResp {
+	resp := adminDrainResp{
+		Draining: actors.Draining(),
+		Players:  actors.ActivePlayers(),
+	}
+	select {
+	case <-a.drainDone:
+		resp.Done = true
+		resp.Summary = a.drainSummary.Load()
+	default:
+	}
+	return resp
+}
+
+// decodeAdminMigrateReq 解析迁移请求, 失败时写出错误响应.
+func decodeAdminMigrateReq(w http.ResponseWriter, r *http.Request) (*adminMigrateReq, bool) {
+	req := &adminMigrateReq{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+		return nil, false
+	}
+	if req.Target == "" {
+		writeAdminError(w, http.StatusBadRequest, "target is empty")
+		return nil, false
+	}
+	return req, true
+}
+
+// writeAdminJSON 写出 JSON 响应.
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Get().Errorf("write admin response failed, %v", err)
+	}
+}
+
+// writeAdminError 写出错误响应.
+func writeAdminError(w http.ResponseWriter, status int, msg string) {
+	writeAdminJSON(w, status, map[string]string{"error": msg})
+}
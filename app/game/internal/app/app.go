@@ -4,18 +4,21 @@ import (
 	"context"
 	"net/http"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/godyy/gactor"
 	"github.com/godyy/ggs/app/game/internal/base/config"
 	"github.com/godyy/ggs/app/game/internal/base/env"
+	"github.com/godyy/ggs/internal/base/appflags"
 	applifecycle "github.com/godyy/ggs/internal/base/lifecycle"
 	"github.com/godyy/ggs/internal/base/logger"
 	"github.com/godyy/ggs/internal/gdconf"
 	imongobd "github.com/godyy/ggs/internal/infra/mongobd"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
 	"github.com/godyy/ggskit/base/db/mongo"
 	"github.com/godyy/ggskit/base/db/redis"
-	"github.com/godyy/ggskit/base/flags"
 	"github.com/godyy/ggskit/infra/actor"
 	"github.com/godyy/ggskit/infra/cluster"
 	"github.com/godyy/ggskit/infra/mongobd"
@@ -27,13 +30,15 @@ import (
 type app struct {
 	config *config.Config // 配置
 
-	env *env.Env // 环境变量管理器.
+	env       *env.Env // 环境变量管理器.
+	serverIds []int64  // 托管的服务器ID.
 
 	redisClient redis.Client  // redis 客户端.
 	mongoClient *mongo.Client // mongo 客户端.
 	mongobd     *imongobd.BD  // mongo 后台.
 
-	cluster *cluster.Service // cluster.
+	cluster    *cluster.Service // cluster.
+	selfNodeId string           // 自身节点ID.
 
 	actorProtoReg    *actor.ProtoRegistry // actor 协议注册表
 	actorCodec       *actor.Codec         // actor编解码
@@ -43,33 +48,71 @@ type app struct {
 	actorService     *actor.Service       // actor 服务
 
 	httpServer *http.Server // http 服务
+
+	// 节点元数据.
+	meta     *nodemeta.Publisher
+	gameMeta *nodemeta.Watcher // Game 节点元数据, 用于查找托管服务器的节点.
+	metaStop chan struct{}
+	metaDone chan struct{}
+
+	// 排空.
+	drainOnce    sync.Once
+	drainDone    chan struct{}
+	drainSummary atomic.Pointer[DrainSummary]
 }
 
-var appInst *app
+var (
+	appInst *app
+	hooks   applifecycle.Hooks // 生命周期回调
+)
+
+// RegisterBeforeStart 注册在启动前调用的回调函数.
+func RegisterBeforeStart(cb applifecycle.Callback) {
+	hooks.RegisterBeforeStart(cb)
+}
+
+// Options 启动选项.
+type Options struct {
+	// Config 配置, 为空时加载 config-path 指定的配置文件.
+	Config *config.Config
+
+	// ServerID 服务器ID, 为 0 时使用 env-server-id 与 env-node-index.
+	ServerID int64
+
+	// NodeIndex 同一服务器下的节点序号, 仅在指定 ServerID 时生效.
+	NodeIndex int
+}
 
-func Start() {
-	appInst = &app{}
+// Start 启动应用. opts 为空时按命令行参数启动, 须先解析 flags.
+func Start(opts *Options) {
+	if opts == nil {
+		opts = &Options{}
+	}
 
-	// 解析flag
-	flags.Parse()
-	defer flags.Reset()
+	appInst = &app{
+		drainDone: make(chan struct{}),
+	}
 
 	// 加载配置表
-	cfg, err := config.Load(configPath())
-	if err != nil {
-		panic(pkgerrors.WithMessage(err, "load config"))
+	cfg := opts.Config
+	if cfg == nil {
+		var err error
+		if cfg, err = config.Load(appflags.ConfigPath()); err != nil {
+			panic(pkgerrors.WithMessage(err, "load config"))
+		}
 	}
 	appInst.config = cfg
 
 	// 初始化环境变量.
 	appInst.env = env.NewEnv()
-	appInst.env.Init()
+	appInst.env.Init(opts.ServerID, opts.NodeIndex)
+	appInst.initServerIds()
 
 	// 初始化日志工具.
 	logger.Init(cfg.Log)
 
 	// 启动前回调.
-	applifecycle.BeforeStart()
+	hooks.BeforeStart()
 
 	// 初始化 redis.
 	redisClient, err := redis.NewClient(cfg.DB.Redis)
@@ -119,14 +162,27 @@ func Start() {
 		logger.Get().Fatalf("start cluster failed, %v", err)
 	}
 
+	// 发布节点元数据.
+	if err := appInst.startMeta(); err != nil {
+		logger.Get().Fatalf("start node meta failed, %v", err)
+	}
+
 	// 启动http服务.
 	appInst.startHttp()
 }
 
 func Stop() {
+	// 排空, 持久化所有玩家数据. 未启用时仅等待已发起的排空完成.
+	if appInst.config.Drain.OnStop || Draining() {
+		<-appInst.drain()
+	}
+
 	// 停止 http 服务.
 	appInst.stopHttp()
 
+	// 撤销节点元数据.
+	appInst.stopMeta()
+
 	// 停止 Actor 服务.
 	appInst.stopActor()
 
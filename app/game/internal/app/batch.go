@@ -0,0 +1,81 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"github.com/godyy/gactor"
+	"github.com/godyy/ggs/internal/base/logger"
+	iactor "github.com/godyy/ggs/internal/infra/actor"
+	"github.com/godyy/ggs/internal/infra/actor/actors"
+)
+
+const (
+	batchConcurrency = 8               // 批量操作并发数.
+	batchLogInterval = 5 * time.Second // 批量操作进度日志间隔.
+)
+
+// PlayerFailure 批量操作中失败的玩家.
+type PlayerFailure struct {
+	PlayerId int64  `json:"player_id"` // 玩家ID.
+	Error    string `json:"error"`     // 失败原因.
+}
+
+// activePlayerIds 返回当前节点所有已启动的玩家ID.
+func activePlayerIds() []int64 {
+	var ids []int64
+	actors.RangeActivePlayers(func(id int64) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return ids
+}
+
+// runPlayerBatch 以有限并发对 playerIds 执行 f, 并定期打印进度日志.
+// 返回成功数量与失败的玩家.
+func runPlayerBatch(action string, playerIds []int64, f func(id int64) error) (int, []PlayerFailure) {
+	var (
+		mtx       sync.Mutex
+		wg        sync.WaitGroup
+		chIds     = make(chan int64)
+		succeeded = 0
+		failed    = make([]PlayerFailure, 0)
+		lastLog   = time.Now()
+	)
+	for range min(batchConcurrency, len(playerIds)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range chIds {
+				err := f(id)
+
+				mtx.Lock()
+				if err != nil {
+					logger.Get().Errorf("%s player %d failed, %v", action, id, err)
+					failed = append(failed, PlayerFailure{PlayerId: id, Error: err.Error()})
+				} else {
+					succeeded++
+				}
+				if time.Since(lastLog) >= batchLogInterval {
+					logger.Get().Infof("%s progress %d/%d, failed=%d", action, succeeded+len(failed), len(playerIds), len(failed))
+					lastLog = time.Now()
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+	for _, id := range playerIds {
+		chIds <- id
+	}
+	close(chIds)
+	wg.Wait()
+	return succeeded, failed
+}
+
+// playerActorUID 返回玩家 Actor UID.
+func playerActorUID(playerId int64) gactor.ActorUID {
+	return gactor.ActorUID{
+		Category: iactor.CategoryPlayer.ActorCategory(),
+		ID:       playerId,
+	}
+}
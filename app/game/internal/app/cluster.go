@@ -77,5 +77,7 @@ func (a *app) OnNodesSync(nodes []*cluster.Node) {
 }
 
 func selfNode(addr string) *cluster.Node {
-	return nodeutil.NewServerNode(consts.NodeGame, Env().ServerID(), addr)
+	node := nodeutil.NewServerNode(consts.NodeGame, Env().ServerID(), addr)
+	node.Name = Env().NodeName()
+	return node
 }
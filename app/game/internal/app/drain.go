@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/godyy/gactor"
+	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/actor/actors"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	"github.com/godyy/ggskit/infra/monitor/probe"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const defaultDrainTimeout = time.Minute
+
+// DrainSummary 排空结果.
+type DrainSummary struct {
+	Total  int             `json:"total"`  // 排空开始时已启动的玩家数量.
+	Saved  int             `json:"saved"`  // 持久化成功数量.
+	Failed []PlayerFailure `json:"failed"` // 持久化失败的玩家.
+}
+
+// Draining 返回节点是否排空中.
+func Draining() bool {
+	return actors.Draining()
+}
+
+// Drain 发起排空, 返回排空完成通知. 重复调用不会重复发起.
+//
+// 排空时拒绝启动新的玩家 Actor, 将 /probe 就绪状态置为 false, 在节点元数据中标记排空,
+// 以有限并发停止所有玩家 Actor 并持久化脏数据, 持久化成功后将玩家位置移交给托管同一服务器的其他节点.
+func Drain() <-chan struct{} {
+	return appInst.drain()
+}
+
+// ListenDrainSignal 监听排空信号 SIGUSR1.
+func ListenDrainSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+	go func() {
+		<-ch
+		logger.Get().Info("receive drain signal")
+		Drain()
+	}()
+}
+
+// drain 发起排空.
+func (a *app) drain() <-chan struct{} {
+	a.drainOnce.Do(func() {
+		actors.SetDraining(true)
+		go a.drainLoop()
+	})
+	return a.drainDone
+}
+
+// drainTimeout 返回排空时限.
+func (a *app) drainTimeout() time.Duration {
+	if d := a.config.Drain.Timeout; d > 0 {
+		return d
+	}
+	return defaultDrainTimeout
+}
+
+// drainLoop 排空流程.
+func (a *app) drainLoop() {
+	defer close(a.drainDone)
+
+	// 不再接收新流量.
+	probe.SetReady(false)
+	if a.meta != nil {
+		if err := a.meta.Update(func(m *nodemeta.Meta) { m.Draining = true }); err != nil {
+			logger.Get().Errorf("mark node meta draining failed, %v", err)
+		}
+	}
+
+	timeout := a.drainTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// 停止玩家 Actor 并持久化.
+	playerIds := activePlayerIds()
+	logger.Get().Infof("game draining, players=%d, timeout=%s", len(playerIds), timeout)
+	saved, failed := runPlayerBatch("drain", playerIds, func(id int64) error {
+		return a.drainPlayer(ctx, id)
+	})
+
+	summary := &DrainSummary{Total: len(playerIds), Saved: saved, Failed: failed}
+	a.drainSummary.Store(summary)
+	if len(failed) > 0 {
+		logger.Get().Errorf("game drained with failures, total=%d saved=%d failed=%d", summary.Total, summary.Saved, len(failed))
+		for _, f := range failed {
+			logger.Get().Errorf("drain player %d not saved, %s", f.PlayerId, f.Error)
+		}
+	} else {
+		logger.Get().Infof("game drained, total=%d saved=%d", summary.Total, summary.Saved)
+	}
+}
+
+// drainPlayer 停止玩家 Actor, 持久化成功后移交其位置.
+func (a *app) drainPlayer(ctx context.Context, playerId int64) error {
+	w := actors.WatchStop(playerId)
+	defer actors.UnwatchStop(playerId)
+
+	// 期间已停止.
+	if !actors.IsPlayerActive(playerId) {
+		return nil
+	}
+
+	// 停止 Actor, 等待持久化结果.
+	uid := playerActorUID(playerId)
+	if err := a.actorService.StopActor(ctx, uid); err != nil {
+		return pkgerrors.WithMessage(err, "stop actor")
+	}
+	select {
+	case <-w.Done():
+	case <-ctx.Done():
+		return pkgerrors.WithMessage(ctx.Err(), "wait actor stopped")
+	}
+	if err := w.Err(); err != nil {
+		return pkgerrors.WithMessage(err, "save model")
+	}
+
+	// 移交位置, 与迁移相同, 目标节点启动 Actor 后续期.
+	// 没有其他节点托管该服务器时保留位置, 由重启后的同名节点接管.
+	target, ok := a.drainHandoffTarget(uid)
+	if !ok {
+		logger.Get().Warnf("drain player %d, no handoff target, location kept", playerId)
+		return nil
+	}
+	if _, err := a.actorRegistry.RegisterActor(gactor.ActorRegisterParams{
+		UID:     uid,
+		NodeId:  target,
+		LeaseId: a.actorRegistry.MakeLeaseID(),
+		TTL:     migrateLocationTTL,
+	}); err != nil {
+		return pkgerrors.WithMessage(err, "hand off actor location")
+	}
+	return nil
+}
+
+// drainHandoffTarget 选择接管玩家的节点: 托管玩家所属服务器且未排空的其他 Game 节点中负载最低者.
+func (a *app) drainHandoffTarget(uid gactor.ActorUID) (string, bool) {
+	serverId, ok := getActorServerID(uid)
+	if !ok || a.gameMeta == nil {
+		return "", false
+	}
+
+	var target *nodemeta.Meta
+	a.gameMeta.Range(func(m *nodemeta.Meta) bool {
+		if m.Draining || m.NodeId() == a.selfNodeId || !m.HostsServer(serverId) {
+			return true
+		}
+		if target == nil || m.Load < target.Load {
+			target = m
+		}
+		return true
+	})
+	if target == nil {
+		return "", false
+	}
+	return target.NodeId(), true
+}
@@ -24,6 +24,9 @@ func (a *app) startHttp() {
 	if a.config.EnablePProf {
 		monitor.RegisterPProfHttp(mux, "")
 	}
+	if a.adminEnabled() {
+		a.registerAdminHttp(mux)
+	}
 
 	a.httpServer = &http.Server{
 		Addr:    ":" + strconv.Itoa(port),
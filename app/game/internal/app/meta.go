@@ -0,0 +1,123 @@
+package app
+
+import (
+	"runtime/metrics"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/actor/actors"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+)
+
+// metaUpdateInterval 节点负载发布间隔.
+const metaUpdateInterval = 5 * time.Second
+
+// startMeta 发布节点元数据, 包括托管的服务器, 并定期发布活跃玩家数量与 CPU 使用率作为负载;
+// 同时监听 Game 节点元数据, 用于查找托管其他服务器的节点.
+func (a *app) startMeta() error {
+	metaCfg := nodemeta.ConfigFromCluster(&a.config.Cluster.Core)
+	gameMeta, err := nodemeta.NewWatcher(metaCfg, consts.NodeGame)
+	if err != nil {
+		return err
+	}
+	if err := gameMeta.Start(); err != nil {
+		return err
+	}
+	a.gameMeta = gameMeta
+
+	publisher, err := nodemeta.NewPublisher(metaCfg, nodemeta.Meta{
+		Category:  consts.NodeGame,
+		Name:      Env().NodeName(),
+		ServerId:  Env().ServerID(),
+		ServerIds: a.serverIds,
+	})
+	if err != nil {
+		a.gameMeta.Stop()
+		return err
+	}
+	if err := publisher.Start(); err != nil {
+		a.gameMeta.Stop()
+		return err
+	}
+	a.meta = publisher
+	a.metaStop = make(chan struct{})
+	a.metaDone = make(chan struct{})
+	go a.metaLoop()
+	return nil
+}
+
+// stopMeta 撤销节点元数据.
+func (a *app) stopMeta() {
+	if a.meta != nil {
+		close(a.metaStop)
+		<-a.metaDone
+		a.meta.Stop()
+		a.gameMeta.Stop()
+	}
+}
+
+// metaLoop 定期发布负载. 每次都会发布, 以便订阅方据更新时间识别新的负载.
+func (a *app) metaLoop() {
+	defer close(a.metaDone)
+
+	cpu := newCPUSampler()
+	ticker := time.NewTicker(metaUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.metaStop:
+			return
+		case <-ticker.C:
+		}
+
+		players, usage := actors.ActivePlayers(), cpu.sample()
+		if err := a.meta.Update(func(m *nodemeta.Meta) {
+			m.Load = players
+			m.CPU = usage
+		}); err != nil {
+			logger.Get().Errorf("update node meta load failed, %v", err)
+		}
+	}
+}
+
+// cpuSampler 根据 runtime/metrics 估算进程在 GOMAXPROCS 范围内的 CPU 使用率.
+type cpuSampler struct {
+	samples     []metrics.Sample
+	total, idle float64
+}
+
+func newCPUSampler() *cpuSampler {
+	s := &cpuSampler{
+		samples: []metrics.Sample{
+			{Name: "/cpu/classes/total:cpu-seconds"},
+			{Name: "/cpu/classes/idle:cpu-seconds"},
+		},
+	}
+	s.total, s.idle = s.read()
+	return s
+}
+
+// read 读取累计可用 CPU 时间与空闲 CPU 时间.
+func (s *cpuSampler) read() (float64, float64) {
+	metrics.Read(s.samples)
+	var total, idle float64
+	if s.samples[0].Value.Kind() == metrics.KindFloat64 {
+		total = s.samples[0].Value.Float64()
+	}
+	if s.samples[1].Value.Kind() == metrics.KindFloat64 {
+		idle = s.samples[1].Value.Float64()
+	}
+	return total, idle
+}
+
+// sample 返回自上次采样以来的 CPU 使用率(0~1).
+func (s *cpuSampler) sample() float64 {
+	total, idle := s.read()
+	dTotal, dIdle := total-s.total, idle-s.idle
+	s.total, s.idle = total, idle
+	if dTotal <= 0 {
+		return 0
+	}
+	return min(max((dTotal-dIdle)/dTotal, 0), 1)
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/godyy/gactor"
+	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/actor/actors"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	migrateLocationTTL = int64(30)        // 目标节点位置注册时长(秒), 目标节点启动 Actor 后续期.
+	migrateTimeout     = 30 * time.Second // 单个玩家迁移时限.
+)
+
+var (
+	// ErrMigrateTarget 迁移目标节点无效.
+	ErrMigrateTarget = pkgerrors.New("invalid migrate target")
+
+	// ErrPlayerNotActive 玩家 Actor 未在当前节点启动.
+	ErrPlayerNotActive = pkgerrors.New("player not active on this node")
+)
+
+// MigratePlayer 将玩家 Actor 迁移至 target 节点.
+//
+// 迁移时停止当前节点上的玩家 Actor, 停止过程中通知已连接的网关并持久化数据,
+// 持久化成功后将玩家位置注册至目标节点. 网关在 Actor 断开后重新连接, 由目标节点启动 Actor.
+func MigratePlayer(ctx context.Context, playerId int64, target string) error {
+	return appInst.migratePlayer(ctx, playerId, target)
+}
+
+// migratePlayer 迁移玩家 Actor.
+func (a *app) migratePlayer(ctx context.Context, playerId int64, target string) error {
+	meta, err := a.checkMigrateTarget(target)
+	if err != nil {
+		return err
+	}
+	if !actors.IsPlayerActive(playerId) {
+		return ErrPlayerNotActive
+	}
+
+	// 目标节点须托管玩家所属的服务器.
+	uid := playerActorUID(playerId)
+	serverId, ok := getActorServerID(uid)
+	if !ok {
+		return ErrActorServerUnknown
+	}
+	if !meta.HostsServer(serverId) {
+		return pkgerrors.WithMessagef(ErrMigrateTarget, "%s does not host server %d", target, serverId)
+	}
+
+	m, err := actors.BeginMigration(playerId, target)
+	if err != nil {
+		return err
+	}
+	defer actors.EndMigration(playerId)
+
+	ctx, cancel := context.WithTimeout(ctx, migrateTimeout)
+	defer cancel()
+
+	// 停止 Actor, 等待持久化结果.
+	if err := a.actorService.StopActor(ctx, uid); err != nil {
+		return pkgerrors.WithMessage(err, "stop actor")
+	}
+	select {
+	case <-m.Done():
+	case <-ctx.Done():
+		return pkgerrors.WithMessage(ctx.Err(), "wait actor stopped")
+	}
+	if err := m.Err(); err != nil {
+		return pkgerrors.WithMessage(err, "save model")
+	}
+
+	// 更新位置.
+	if _, err := a.actorRegistry.RegisterActor(gactor.ActorRegisterParams{
+		UID:     uid,
+		NodeId:  target,
+		LeaseId: a.actorRegistry.MakeLeaseID(),
+		TTL:     migrateLocationTTL,
+	}); err != nil {
+		return pkgerrors.WithMessage(err, "register actor location")
+	}
+
+	logger.Get().Infof("player %d migrated to %s", playerId, target)
+	return nil
+}
+
+// checkMigrateTarget 根据 Game 节点元数据检查迁移目标节点, 目标须存活且未排空, 返回其元数据.
+func (a *app) checkMigrateTarget(target string) (*nodemeta.Meta, error) {
+	if target == "" || target == a.selfNodeId {
+		return nil, ErrMigrateTarget
+	}
+
+	var meta *nodemeta.Meta
+	if a.gameMeta != nil {
+		a.gameMeta.Range(func(m *nodemeta.Meta) bool {
+			if m.NodeId() == target {
+				meta = m
+				return false
+			}
+			return true
+		})
+	}
+	if meta == nil {
+		return nil, pkgerrors.WithMessagef(ErrMigrateTarget, "%s not found", target)
+	}
+	if meta.Draining {
+		return nil, pkgerrors.WithMessagef(ErrMigrateTarget, "%s is draining", target)
+	}
+	return meta, nil
+}
+
+// MigrateSummary 批量迁移结果.
+type MigrateSummary struct {
+	Total    int             `json:"total"`    // 待迁移数量.
+	Migrated int             `json:"migrated"` // 迁移成功数量.
+	Failed   []PlayerFailure `json:"failed"`   // 迁移失败的玩家.
+}
+
+// MigratePlayers 批量迁移玩家 Actor 至 target 节点, playerIds 为空时迁移当前节点所有已启动的玩家.
+func MigratePlayers(ctx context.Context, playerIds []int64, target string) MigrateSummary {
+	return appInst.migratePlayers(ctx, playerIds, target)
+}
+
+// migratePlayers 以有限并发批量迁移玩家 Actor.
+func (a *app) migratePlayers(ctx context.Context, playerIds []int64, target string) MigrateSummary {
+	if len(playerIds) == 0 {
+		playerIds = activePlayerIds()
+	}
+
+	logger.Get().Infof("migrate %d players to %s", len(playerIds), target)
+	migrated, failed := runPlayerBatch("migrate", playerIds, func(id int64) error {
+		return a.migratePlayer(ctx, id, target)
+	})
+	logger.Get().Infof("migrate players to %s finished, total=%d migrated=%d failed=%d", target, len(playerIds), migrated, len(failed))
+	return MigrateSummary{Total: len(playerIds), Migrated: migrated, Failed: failed}
+}
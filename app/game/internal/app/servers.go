@@ -0,0 +1,67 @@
+package app
+
+import (
+	"slices"
+
+	"github.com/godyy/gactor"
+	"github.com/godyy/ggs/app/game/internal/base/env"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ErrActorServerUnknown 无法获取 Actor 所属服务器.
+var ErrActorServerUnknown = pkgerrors.New("actor server unknown")
+
+// initServerIds 整理节点托管的服务器ID, env-server-id 指定的服务器始终位于首位.
+func (a *app) initServerIds() {
+	ids := []int64{a.env.ServerID()}
+	for _, id := range a.config.ServerIds {
+		if id > 0 && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	a.serverIds = ids
+}
+
+// ServerIDs 返回节点托管的服务器ID, 首位为 env-server-id 指定的服务器.
+func ServerIDs() []int64 {
+	return appInst.serverIds
+}
+
+// hostsServer 返回节点是否托管 serverId 指定的服务器.
+func (a *app) hostsServer(serverId int64) bool {
+	return slices.Contains(a.serverIds, serverId)
+}
+
+// serverHost 返回托管 serverId 的 Game 节点自身的服务器ID, 节点名据此生成.
+// 根据 Game 节点元数据查找, 未找到时视为由同名节点托管.
+func (a *app) serverHost(serverId int64) int64 {
+	if a.hostsServer(serverId) {
+		return a.env.ServerID()
+	}
+	host := serverId
+	if a.gameMeta != nil {
+		a.gameMeta.Range(func(m *nodemeta.Meta) bool {
+			if slices.Contains(m.ServerIds, serverId) {
+				host = m.ServerId
+				return false
+			}
+			return true
+		})
+	}
+	return host
+}
+
+// ActorServerID 返回 Actor 所属服务器ID.
+func ActorServerID(uid gactor.ActorUID) (int64, bool) {
+	return getActorServerID(uid)
+}
+
+// actorDB 解析 Actor 数据所在的数据库, 按 Actor 所属服务器区分.
+func (a *app) actorDB(uid gactor.ActorUID) (string, error) {
+	serverId, ok := getActorServerID(uid)
+	if !ok {
+		return "", ErrActorServerUnknown
+	}
+	return env.MakeServerDB(serverId), nil
+}
@@ -31,15 +31,34 @@ type Config struct {
 		Mongo *mongo.Config
 	}
 
+	// ServerIds 节点额外托管的服务器ID, 节点始终托管 env-server-id 指定的服务器.
+	ServerIds []int64
+
 	// HttpPort HTTP端口.
 	HttpPort int
 
 	// EnablePProf 是否启用pprof.
 	EnablePProf bool
 
+	// Admin 管理接口配置, 管理接口挂载在 HttpPort 上.
+	Admin struct {
+		// Token 管理令牌, 为空表示不启用管理接口.
+		Token string
+	}
+
 	// Log 日志配置
 	Log *logger.Config
 
+	// Drain 排空配置.
+	Drain struct {
+		// Timeout 停止并持久化所有玩家的时限, 默认 1 分钟.
+		Timeout time.Duration
+
+		// OnStop 停止服务时是否先排空. 未启用时停止 Actor 服务同样持久化玩家数据,
+		// 但不移交玩家位置, 仅等待已发起的排空完成.
+		OnStop bool
+	}
+
 	// Actor Actor相关配置.
 	Actor struct {
 		// SaveDelay 保存延迟.
@@ -1,15 +1,10 @@
 package config
 
 import (
+	_ "github.com/godyy/ggs/internal/base/appflags" // 注册共用参数 cluster-port, http-port, enable-pprof.
 	"github.com/godyy/ggskit/base/config"
 )
 
-func init() {
-	config.AddFlag("cluster-port", 0, "cluster port, must > 0")
-	config.AddFlag("http-port", 0, "http port, 0 means disable http server")
-	config.AddFlag("enable-pprof", false, "enable pprof")
-}
-
 func (c *Config) ApplyFlags() error {
 	if port, ok := config.GetFlagValue[int]("cluster-port"); ok && port > 0 {
 		c.Cluster.Port = port
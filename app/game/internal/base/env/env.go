@@ -1,6 +1,9 @@
 package env
 
 import (
+	"fmt"
+
+	"github.com/godyy/ggs/internal/base/nodeutil"
 	"github.com/godyy/ggskit/base/env"
 )
 
@@ -8,8 +11,9 @@ import (
 type Env struct {
 	env.Env
 
-	serverId int64  // 服务器ID
-	db       string // 服务器数据库名称
+	serverId  int64  // 服务器ID
+	nodeIndex int    // 同一服务器下的节点序号
+	db        string // 服务器数据库名称
 }
 
 // NewEnv 创建环境变量管理器.
@@ -19,17 +23,43 @@ func NewEnv() *Env {
 	}
 }
 
-// Init 初始化环境变量.
-func (e *Env) Init() {
+// Init 初始化环境变量, serverId 为 0 时使用 env-server-id 与 env-node-index.
+func (e *Env) Init(serverId int64, nodeIndex int) {
+	if serverId > 0 {
+		e.setServerId(serverId)
+		e.nodeIndex = nodeIndex
+		return
+	}
 	e.applyFlags()
 }
 
+// setServerId 设置服务器ID.
+func (e *Env) setServerId(serverId int64) {
+	e.serverId = serverId
+	e.db = MakeServerDB(serverId)
+}
+
 // ServerID 服务器ID
 func (e *Env) ServerID() int64 {
 	return e.serverId
 }
 
+// NodeIndex 返回同一服务器下的节点序号.
+func (e *Env) NodeIndex() int {
+	return e.nodeIndex
+}
+
+// NodeName 返回集群节点名. 序号为 0 的节点承载服务器 Actor.
+func (e *Env) NodeName() string {
+	return nodeutil.MakeIndexedServerNodeName(e.serverId, e.nodeIndex)
+}
+
 // DB 服务器数据库名称
 func (e *Env) DB() string {
 	return e.db
 }
+
+// MakeServerDB 返回服务器数据库名称.
+func MakeServerDB(serverId int64) string {
+	return fmt.Sprintf("game_%d", serverId)
+}
@@ -1,21 +1,20 @@
 package env
 
 import (
-	"fmt"
-
-	"github.com/godyy/ggskit/base/env"
+	"github.com/godyy/ggs/internal/base/appflags"
 )
 
 func (e *Env) applyFlags() {
-	sid, ok := env.GetFlagValue[int64]("server-id")
+	sid, ok := appflags.ServerID()
 	if ok && sid > 0 {
-		e.serverId = sid
-		e.db = fmt.Sprintf("game_%d", sid)
+		e.setServerId(sid)
 	} else {
 		panic("env: env-server-id is required and must > 0")
 	}
-}
 
-func init() {
-	env.AddFlag("server-id", int64(0), "server id")
+	nodeIndex, _ := appflags.NodeIndex()
+	if nodeIndex < 0 {
+		panic("env: env-node-index must >= 0")
+	}
+	e.nodeIndex = nodeIndex
 }
@@ -3,7 +3,8 @@ package player
 import (
 	"github.com/godyy/ggs/app/game/internal/handler"
 	actorhandler "github.com/godyy/ggs/internal/infra/actor/handler"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
+	pbs2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -20,6 +21,7 @@ func initC2SHandler() {
 }
 
 func initS2SHandler() {
+	registerS2SFunc((*pbs2s.PlayerPushNtf)(nil), actorhandler.WrapCastFunc(handlePlayerPush))
 }
 
 func registerC2SFunc(msg proto.Message, checkLogin bool, f ...actorhandler.HandlerFunc) {
@@ -8,8 +8,9 @@ import (
 	"github.com/godyy/ggs/internal/base/logger"
 	"github.com/godyy/ggs/internal/infra/actor"
 	"github.com/godyy/ggs/internal/infra/actor/actors"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
+	"github.com/godyy/ggs/internal/infra/actor/modsync"
 	"github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
 	pkgerrors "github.com/pkg/errors"
 )
 
@@ -23,14 +24,23 @@ func handleLoginCharacter(ctx *actor.Context, req *pbc2s.LoginCharacterReq) (*pb
 
 	player.SetLogin()
 
-	getServerNameResp, err := player.Sugared().RPCWithTimeout(actor.ActorUID{Category: actor.CategoryServer.ActorCategory(), ID: app.Env().ServerID()},
+	serverId, ok := app.ActorServerID(player.ActorUID())
+	if !ok {
+		return nil, app.ErrActorServerUnknown
+	}
+	getServerNameResp, err := player.Sugared().RPCWithTimeout(actor.ActorUID{Category: actor.CategoryServer.ActorCategory(), ID: serverId},
 		&s2s.GetServerNameReq{}, 5*time.Second)
 	if err != nil {
 		return nil, pkgerrors.WithMessage(err, "get server name")
 	}
 	logger.Get().Info("get server name success, server name: %s", getServerNameResp.(*s2s.GetServerNameResp).ServerName)
 
-	return &pbc2s.LoginCharacterResp{}, nil
+	// 离线期间暂存的推送随登录响应之后下发.
+	systems.Push.FlushQueued(ctx, player)
+
+	return &pbc2s.LoginCharacterResp{
+		Modules: modsync.Snapshots(player),
+	}, nil
 }
 
 // handleHearbeat 处理心跳.
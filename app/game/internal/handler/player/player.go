@@ -5,7 +5,8 @@ import (
 	"github.com/godyy/ggs/internal/infra/actor"
 	"github.com/godyy/ggs/internal/infra/actor/actors"
 	"github.com/godyy/ggs/internal/infra/actor/model/player"
-	"github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
+	"github.com/godyy/ggs/internal/infra/actor/modsync"
+	"github.com/godyy/ggs/pkg/protocol/pb/c2s"
 )
 
 // handleModifyName 修改玩家名称
@@ -15,6 +16,7 @@ func handleModifyName(c *actor.Context, req *c2s.ModifyNameReq) (*c2s.ModifyName
 	oldName := m.Name
 	m.Name = req.Name
 	p.SetDirtyModules(m)
+	modsync.Changed(c, m)
 	logger.Get().Debugf("player %d modify name %s to %s", p.ID(), oldName, m.Name)
 	return &c2s.ModifyNameResp{Name: req.Name}, nil
 }
@@ -0,0 +1,13 @@
+package player
+
+import (
+	"github.com/godyy/ggs/app/game/internal/systems"
+	"github.com/godyy/ggs/internal/infra/actor"
+	pbs2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
+)
+
+// handlePlayerPush 处理其他 Actor 投递的推送通知.
+func handlePlayerPush(ctx *actor.Context, ntf *pbs2s.PlayerPushNtf) bool {
+	systems.Push.Deliver(ctx, ntf)
+	return true
+}
@@ -1,9 +1,9 @@
 package internal
 
-import applifecycle "github.com/godyy/ggs/internal/base/lifecycle"
+import "github.com/godyy/ggs/app/game/internal/app"
 
 func init() {
-	applifecycle.RegisterBeforeStart(beforeAppStart)
+	app.RegisterBeforeStart(beforeAppStart)
 }
 
 func beforeAppStart() {
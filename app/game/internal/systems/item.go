@@ -7,13 +7,45 @@ import (
 	"github.com/godyy/ggs/internal/infra/actor/convert"
 	"github.com/godyy/ggs/internal/infra/actor/handler"
 	"github.com/godyy/ggs/internal/infra/actor/model/player"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
+	"github.com/godyy/ggs/internal/infra/actor/modsync"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	"google.golang.org/protobuf/proto"
 )
 
 type itemsModule struct{}
 
 var Items = &itemsModule{}
 
+func init() {
+	modsync.Register(modsync.Syncer[*player.Items]{
+		Snapshot: Items.snapshot,
+		Update:   Items.update,
+	})
+	handler.RegisterPushMerger(handler.PushMergeFunc[*pbc2s.ItemPush](Items.mergePush))
+}
+
+// snapshot 道具模块快照.
+func (m *itemsModule) snapshot(items *player.Items) *pbcommon.ModuleSnapshot {
+	return &pbcommon.ModuleSnapshot{
+		Module: &pbcommon.ModuleSnapshot_Items{
+			Items: &pbcommon.ItemsSnapshot{Items: convert.ItemsModule2PB(items)},
+		},
+	}
+}
+
+// update 道具变更推送, keys 为变更的道具ID, 已移除的道具推送数量为 0.
+func (m *itemsModule) update(items *player.Items, keys []int64) proto.Message {
+	if len(keys) == 0 {
+		return &pbc2s.ItemPush{Items: convert.ItemsModule2PB(items)}
+	}
+	changed := make([]player.Item, 0, len(keys))
+	for _, id := range keys {
+		changed = append(changed, player.Item{ID: int32(id), Num: items.GetNum(int32(id))})
+	}
+	return &pbc2s.ItemPush{Items: convert.Items2PB(changed)}
+}
+
 func (m *itemsModule) init(p *actors.Player) {
 	items := actor.GetActorModule[*player.Items](p, true)
 	for _, item := range gdconf.Global().InitItems {
@@ -21,6 +53,22 @@ func (m *itemsModule) init(p *actors.Player) {
 	}
 }
 
+// mergePush 按道具ID合并道具变更推送, 后写覆盖先写.
+func (m *itemsModule) mergePush(dst, src *pbc2s.ItemPush) {
+	idx := make(map[int32]int, len(dst.Items))
+	for i, item := range dst.Items {
+		idx[item.Id] = i
+	}
+	for _, item := range src.Items {
+		if i, ok := idx[item.Id]; ok {
+			dst.Items[i] = item
+			continue
+		}
+		idx[item.Id] = len(dst.Items)
+		dst.Items = append(dst.Items, item)
+	}
+}
+
 func (m *itemsModule) UseItem(ctx *actor.Context, itemId int32, num int64) (left int64, ok bool) {
 	if itemId == 0 || num <= 0 {
 		return 0, false
@@ -31,10 +79,7 @@ func (m *itemsModule) UseItem(ctx *actor.Context, itemId int32, num int64) (left
 	left, ok = items.Sub(itemId, num)
 	if ok {
 		p.SetDirtyModules(items)
-		item, _ := items.GetItem(itemId)
-		handler.AppendPushMsg(ctx, &pbc2s.ItemPush{
-			Items: convert.Items2PB([]player.Item{item}),
-		})
+		modsync.Changed(ctx, items, int64(itemId))
 	}
 
 	return
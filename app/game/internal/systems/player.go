@@ -6,8 +6,14 @@ import (
 	"github.com/godyy/ggs/internal/base/consts"
 	"github.com/godyy/ggs/internal/infra/actor"
 	"github.com/godyy/ggs/internal/infra/actor/actors"
+	"github.com/godyy/ggs/internal/infra/actor/convert"
+	"github.com/godyy/ggs/internal/infra/actor/handler"
 	"github.com/godyy/ggs/internal/infra/actor/lifecycle"
 	"github.com/godyy/ggs/internal/infra/actor/model/player"
+	"github.com/godyy/ggs/internal/infra/actor/modsync"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	"google.golang.org/protobuf/proto"
 )
 
 type playerModule struct{}
@@ -16,6 +22,20 @@ var Player = &playerModule{}
 
 func init() {
 	lifecycle.RegisterCHandler[*actors.Player](actor.CategoryPlayer.ActorCategory(), Player)
+	modsync.Register(modsync.Syncer[*player.BaseInfo]{
+		Snapshot: func(base *player.BaseInfo) *pbcommon.ModuleSnapshot {
+			return &pbcommon.ModuleSnapshot{
+				Module: &pbcommon.ModuleSnapshot_Base{Base: convert.PlayerBase2PB(base)},
+			}
+		},
+		Update: func(base *player.BaseInfo, _ []int64) proto.Message {
+			return &pbc2s.PlayerBasePush{Base: convert.PlayerBase2PB(base)}
+		},
+	})
+	// 基础信息推送携带完整信息, 保留最后一条.
+	handler.RegisterPushMerger(handler.PushMergeFunc[*pbc2s.PlayerBasePush](func(dst, src *pbc2s.PlayerBasePush) {
+		dst.Base = src.Base
+	}))
 }
 
 // OnStart Player OnStart回调.
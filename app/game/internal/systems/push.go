@@ -0,0 +1,198 @@
+package systems
+
+import (
+	"errors"
+	"time"
+
+	"github.com/godyy/gactor"
+	"github.com/godyy/ggs/app/game/internal/app"
+	"github.com/godyy/ggs/internal/base/logger"
+	"github.com/godyy/ggs/internal/infra/actor"
+	"github.com/godyy/ggs/internal/infra/actor/actors"
+	"github.com/godyy/ggs/internal/infra/actor/handler"
+	"github.com/godyy/ggs/internal/infra/actor/model/player"
+	pbs2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// PushPolicy 玩家离线时的推送策略.
+type PushPolicy int8
+
+const (
+	PushPolicyDrop  PushPolicy = 0 // 丢弃, 默认策略.
+	PushPolicyQueue PushPolicy = 1 // 暂存, 玩家登录后下发.
+)
+
+// maxQueuedPushes 每个玩家最多暂存的推送数量, 超出时丢弃最早的推送.
+const maxQueuedPushes = 100
+
+// Caster 投递 S2S 消息, actor.ContextSugared 与 actor.Service 均满足.
+type Caster interface {
+	Cast(to actor.ActorUID, msg proto.Message) error
+}
+
+var _ Caster = actor.ContextSugared{}
+
+type pushModule struct {
+	policies map[protoreflect.FullName]PushPolicy // 消息全名 -> 离线推送策略
+}
+
+// Push 向玩家推送消息, 供不在玩家上下文中的 Actor 或系统使用.
+var Push = &pushModule{
+	policies: make(map[protoreflect.FullName]PushPolicy),
+}
+
+func init() {
+	// 系统消息离线时暂存, 登录后下发.
+	Push.SetPolicy((*pbc2s.SystemPush)(nil), PushPolicyQueue)
+}
+
+// SetPolicy 设置推送消息在玩家离线时的策略, 须在 init 阶段调用.
+func (m *pushModule) SetPolicy(msg proto.Message, policy PushPolicy) {
+	m.policies[msg.ProtoReflect().Descriptor().FullName()] = policy
+}
+
+// policy 获取推送消息在玩家离线时的策略.
+func (m *pushModule) policy(name protoreflect.FullName) PushPolicy {
+	return m.policies[name]
+}
+
+// ToPlayer 向玩家推送 c2s 消息. 消息经 S2S Cast 投递给玩家 Actor, 由其转为推送下发,
+// 玩家离线时按消息策略丢弃或暂存. 丢弃策略的消息在玩家 Actor 不在线时不投递, 避免激活 Actor.
+func (m *pushModule) ToPlayer(c Caster, playerId int64, msg proto.Message) error {
+	drop := m.policy(msg.ProtoReflect().Descriptor().FullName()) == PushPolicyDrop
+	if drop && !playerActorOnline(playerId) {
+		return nil
+	}
+	ntf, err := newPlayerPushNtf(msg)
+	if err != nil {
+		return err
+	}
+	return c.Cast(playerActorUID(playerId), ntf)
+}
+
+// ToPlayers 向一组玩家推送 c2s 消息, 消息只编码一次. 返回投递失败的玩家的错误.
+// 不逐个查询玩家 Actor 是否在线, 以免阻塞调用方, 离线策略由玩家 Actor 在 Deliver 中执行.
+func (m *pushModule) ToPlayers(c Caster, playerIds []int64, msg proto.Message) error {
+	ntf, err := newPlayerPushNtf(msg)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, playerId := range playerIds {
+		if err := c.Cast(playerActorUID(playerId), ntf); err != nil {
+			errs = append(errs, pkgerrors.WithMessagef(err, "player %d", playerId))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Deliver 玩家 Actor 处理推送通知: 已登录时下发, 否则按消息策略丢弃或暂存.
+func (m *pushModule) Deliver(ctx *actor.Context, ntf *pbs2s.PlayerPushNtf) {
+	p := actor.CtxActor[*actors.Player](ctx)
+	if ntf.Msg == nil {
+		return
+	}
+
+	if p.IsLogin() {
+		msg, err := ntf.Msg.UnmarshalNew()
+		if err != nil {
+			logger.Get().Errorf("player %d unmarshal push %s failed, %v", p.ID(), ntf.Msg.TypeUrl, err)
+			return
+		}
+		err = p.Sugared().PushRawMessage(msg)
+		if err == nil {
+			return
+		}
+		logger.Get().Debugf("player %d push %s failed, %v", p.ID(), ntf.Msg.TypeUrl, err)
+	}
+
+	if m.policy(ntf.Msg.MessageName()) != PushPolicyQueue {
+		return
+	}
+	queue := actor.GetActorModule[*player.PushQueue](p, true)
+	if dropped := queue.Append(&player.QueuedPush{
+		TypeURL: ntf.Msg.TypeUrl,
+		Value:   ntf.Msg.Value,
+		Time:    time.Now().UnixMilli(),
+	}, maxQueuedPushes); dropped > 0 {
+		logger.Get().Warnf("player %d push queue full, %d dropped", p.ID(), dropped)
+	}
+	p.SetDirtyModules(queue)
+}
+
+// FlushQueued 下发暂存的推送, 在登录请求中调用, 推送随登录响应之后下发.
+// 推送全部发送成功后才从暂存队列中移除, 否则保留至下次登录.
+func (m *pushModule) FlushQueued(ctx *actor.Context, p *actors.Player) {
+	queue := actor.GetActorModule[*player.PushQueue](p, true)
+	n := len(queue.Pushes)
+	if n == 0 {
+		return
+	}
+
+	for _, push := range queue.Pushes {
+		msg, err := (&anypb.Any{TypeUrl: push.TypeURL, Value: push.Value}).UnmarshalNew()
+		if err != nil {
+			logger.Get().Errorf("player %d unmarshal queued push %s failed, %v", p.ID(), push.TypeURL, err)
+			continue
+		}
+		handler.AppendPushMsg(ctx, msg)
+	}
+
+	handler.OnPushSent(ctx, func(err error) {
+		if err != nil {
+			logger.Get().Warnf("player %d flush queued pushes failed, kept for next login, %v", p.ID(), err)
+			return
+		}
+		queue.Remove(n)
+		p.SetDirtyModules(queue)
+	})
+}
+
+// newPlayerPushNtf 构造玩家推送通知.
+func newPlayerPushNtf(msg proto.Message) (*pbs2s.PlayerPushNtf, error) {
+	a, err := anypb.New(msg)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "pack push")
+	}
+	return &pbs2s.PlayerPushNtf{Msg: a}, nil
+}
+
+// maxUnixSeconds 秒级时间戳的上限(约公元 5138 年), 超出时视为毫秒.
+const maxUnixSeconds = int64(1e11)
+
+// playerActorOnline 玩家 Actor 是否在线, 即注册表中存在未过期的位置信息.
+// 查询失败时视为在线, 交由玩家 Actor 处理.
+func playerActorOnline(playerId int64) bool {
+	location, err := app.ActorRegistry().GetActorLocation(playerActorUID(playerId))
+	if err != nil {
+		if errors.Is(err, gactor.ErrActorNotExists) {
+			return false
+		}
+		logger.Get().Warnf("get player %d location failed, %v", playerId, err)
+		return true
+	}
+	return location.NodeId != "" && !locationExpired(playerId, location.ExpireAt, time.Now())
+}
+
+// locationExpired 返回位置是否已过期. expireAt 为 Unix 秒, 与注册位置时的 TTL(秒)一致, <=0 表示不过期.
+// 超出秒级范围的值按毫秒处理并告警, 避免单位不一致时将玩家一律判为在线.
+func locationExpired(playerId int64, expireAt int64, now time.Time) bool {
+	if expireAt <= 0 {
+		return false
+	}
+	if expireAt > maxUnixSeconds {
+		logger.Get().Warnf("player %d location expire_at %d not in unix seconds, treat as milliseconds", playerId, expireAt)
+		expireAt /= 1000
+	}
+	return expireAt <= now.Unix()
+}
+
+// playerActorUID 玩家 Actor UID.
+func playerActorUID(playerId int64) actor.ActorUID {
+	return actor.ActorUID{Category: actor.CategoryPlayer.ActorCategory(), ID: playerId}
+}
@@ -2,13 +2,17 @@ package main
 
 import (
 	"github.com/godyy/ggs/app/game/internal/app"
+	"github.com/godyy/ggskit/base/flags"
 	"github.com/godyy/ggskit/utils"
 
 	_ "github.com/godyy/ggs/app/game/internal"
 )
 
 func main() {
-	app.Start()
+	flags.Parse()
+	app.Start(nil)
+	flags.Reset()
+	app.ListenDrainSignal()
 	utils.ListenShutdown()
 	app.Stop()
 }
@@ -61,5 +61,6 @@ type CharacterLoginReq struct {
 
 // CharacterLoginResp
 type CharacterLoginResp struct {
-	Token string `json:"token"` // 用于登录网管的令牌.
+	Token     string `json:"token"`      // 用于登录网管的令牌.
+	AgentAddr string `json:"agent_addr"` // 分配的网关地址.
 }
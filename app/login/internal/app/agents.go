@@ -0,0 +1,68 @@
+package app
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/infra/nodemeta"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// agentMetaStaleAfter 网关节点元数据的过期时长, 为网关负载发布间隔(5s)的 3 倍.
+// 节点失联但租约尚未过期时, 元数据不再更新, 超过该时长的节点不参与分配.
+const agentMetaStaleAfter = 15 * time.Second
+
+var agentWatcher *nodemeta.Watcher // 网关节点元数据监听器
+
+// startAgentWatcher 启动网关节点发现.
+func startAgentWatcher() error {
+	watcher, err := nodemeta.NewWatcher(&cfg.NodeMeta, consts.NodeAgent)
+	if err != nil {
+		return pkgerrors.WithMessage(err, "new agent watcher")
+	}
+	if err := watcher.Start(); err != nil {
+		return pkgerrors.WithMessage(err, "start agent watcher")
+	}
+	agentWatcher = watcher
+	return nil
+}
+
+// stopAgentWatcher 停止网关节点发现.
+func stopAgentWatcher() {
+	if agentWatcher != nil {
+		agentWatcher.Stop()
+	}
+}
+
+// PickAgent 为 serverId 分配网关节点.
+//
+// 仅在存活、未排空且元数据未过期的节点中选择. 节点负载定期发布, 存在延迟, 为避免同一时段的登录
+// 集中到同一节点, 随机抽取两个候选节点并取负载较低者.
+func PickAgent(serverId int64) (nodemeta.Meta, bool) {
+	var candidates []nodemeta.Meta
+	staleBefore := time.Now().Add(-agentMetaStaleAfter).Unix()
+	agentWatcher.Range(func(m *nodemeta.Meta) bool {
+		if m.ServerId == serverId && !m.Draining && m.Addr != "" && m.UpdateAt >= staleBefore {
+			candidates = append(candidates, *m)
+		}
+		return true
+	})
+
+	switch len(candidates) {
+	case 0:
+		return nodemeta.Meta{}, false
+	case 1:
+		return candidates[0], true
+	}
+
+	i := rand.IntN(len(candidates))
+	j := rand.IntN(len(candidates) - 1)
+	if j >= i {
+		j++
+	}
+	if candidates[j].Load < candidates[i].Load {
+		i = j
+	}
+	return candidates[i], true
+}
@@ -5,13 +5,13 @@ import (
 
 	"github.com/godyy/ggs/app/login/internal/base/config"
 	"github.com/godyy/ggs/app/login/internal/infra/repo"
+	"github.com/godyy/ggs/internal/base/appflags"
 	applifecycle "github.com/godyy/ggs/internal/base/lifecycle"
 	"github.com/godyy/ggs/internal/base/logger"
 	"github.com/godyy/ggskit/base/db/mongo"
 	"github.com/godyy/ggskit/base/db/redis"
 	"github.com/godyy/ggskit/base/env"
 	_ "github.com/godyy/ggskit/base/env"
-	"github.com/godyy/ggskit/base/flags"
 	pkgerrors "github.com/pkg/errors"
 )
 
@@ -21,14 +21,26 @@ var (
 	mongoClient *mongo.Client  // mongo 客户端
 )
 
-// Start 启动
-func Start() {
-	// 解析flag
-	flags.Parse()
-	defer flags.Reset()
+// hooks 生命周期回调.
+var hooks applifecycle.Hooks
 
+// RegisterBeforeStart 注册在启动前调用的回调函数.
+func RegisterBeforeStart(cb applifecycle.Callback) {
+	hooks.RegisterBeforeStart(cb)
+}
+
+// Options 启动选项.
+type Options struct {
+	// Config 配置, 为空时加载 config-path 指定的配置文件.
+	Config *config.Config
+}
+
+// Start 启动. opts 为空时按命令行参数启动, 须先解析 flags.
+func Start(opts *Options) {
 	// 加载配置.
-	if c, err := config.Load(configPath()); err != nil {
+	if opts != nil && opts.Config != nil {
+		cfg = opts.Config
+	} else if c, err := config.Load(appflags.ConfigPath()); err != nil {
 		panic(pkgerrors.WithMessage(err, "load config"))
 	} else {
 		cfg = c
@@ -38,7 +50,7 @@ func Start() {
 	logger.Init(cfg.Log)
 
 	// 启动前回调.
-	applifecycle.BeforeStart()
+	hooks.BeforeStart()
 
 	// 初始化 redis.
 	redisCli, err := redis.NewClient(cfg.DB.Redis)
@@ -62,6 +74,11 @@ func Start() {
 		logger.Get().Fatalf("start actor failed, %v", err)
 	}
 
+	// 启动网关节点发现.
+	if err := startAgentWatcher(); err != nil {
+		logger.Get().Fatalf("start agent watcher failed, %v", err)
+	}
+
 	// 启动http服务.
 	startHttp()
 }
@@ -69,6 +86,7 @@ func Start() {
 // Stop 停机.
 func Stop() {
 	stopHttp()
+	stopAgentWatcher()
 	if mongoClient != nil {
 		if err := mongoClient.Disconnect(context.Background()); err != nil {
 			logger.Get().Errorf("disconnect mongo failed, %v", err)
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/godyy/ggs/internal/infra/nodemeta"
 	"github.com/godyy/ggskit/base/config"
 	"github.com/godyy/ggskit/base/db/mongo"
 	"github.com/godyy/ggskit/base/db/redis"
@@ -28,6 +29,9 @@ type Config struct {
 		Mongo *mongo.Config
 	}
 
+	// NodeMeta 节点元数据配置, 用于发现网关节点, Root 需与集群配置的 EtcdRoot 一致.
+	NodeMeta nodemeta.Config
+
 	// Log 日志配置
 	Log *logger.Config
 }
@@ -30,6 +30,7 @@ const (
 	ErrCodeCharacterCountLimited       = ErrCode(4) // 角色数量达到上限
 	ErrCodeServerCharacterCountLimited = ErrCode(5) // 服务器角色数量达到上限
 	ErrCodeCharacterNotExist           = ErrCode(6) // 角色不存在
+	ErrCodeAgentUnavailable            = ErrCode(7) // 无可用网关
 )
 
 // ErrCodeStrings 错误码字符串映射
@@ -41,4 +42,5 @@ var errCodeStrings = map[ErrCode]string{
 	ErrCodeCharacterCountLimited:       "character count limited",
 	ErrCodeServerCharacterCountLimited: "server character count limited",
 	ErrCodeCharacterNotExist:           "character not exist",
+	ErrCodeAgentUnavailable:            "agent unavailable",
 }
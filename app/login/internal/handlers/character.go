@@ -137,6 +137,13 @@ func (h *characterHandler) handleCharacterLogin(c *gin.Context, req *httpproto.C
 
 	// todo 其它检查
 
+	// 分配网关
+	agent, ok := app.PickAgent(character.ServerID)
+	if !ok {
+		logger.Get().Errorf("handler [CharacterLogin], no agent available for server %d", character.ServerID)
+		return errs.ErrCodeAgentUnavailable
+	}
+
 	// 生成登录网关令牌
 	tokenInfo := &models.TokenInfo{
 		UID:         account.User.UID,
@@ -150,6 +157,7 @@ func (h *characterHandler) handleCharacterLogin(c *gin.Context, req *httpproto.C
 		return errs.ErrCodeInternalError
 	}
 	resp.Token = token
+	resp.AgentAddr = agent.Addr
 
 	return nil
 }
@@ -3,11 +3,14 @@ package main
 import (
 	"github.com/godyy/ggs/app/login/internal/app"
 	_ "github.com/godyy/ggs/app/login/internal/handlers"
+	"github.com/godyy/ggskit/base/flags"
 	"github.com/godyy/ggskit/utils"
 )
 
 func main() {
-	app.Start()
+	flags.Parse()
+	app.Start(nil)
+	flags.Reset()
 	utils.ListenShutdown()
 	app.Stop()
 }
@@ -36,7 +36,11 @@ func (s *serverHandler) setupRoutes(root *gin.RouterGroup, version string) {
 }
 
 func (s *serverHandler) handleServerCreate(c *gin.Context, req *httpproto.ServerCreateReq) error {
-	nodeId := cluster.MakeNodeID(consts.NodeGame, nodeutil.MakeServerNodeName(req.ID))
+	hostServerId := req.ID
+	if req.HostServerId > 0 {
+		hostServerId = req.HostServerId
+	}
+	nodeId := cluster.MakeNodeID(consts.NodeGame, nodeutil.MakeServerNodeName(hostServerId))
 
 	// 创建服务器.
 	server := &mongomodels.Server{
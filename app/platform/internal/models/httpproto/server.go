@@ -1,6 +1,7 @@
 package httpproto
 
 type ServerCreateReq struct {
-	ID   int64  `json:"id"`   // 服务器ID
-	Name string `json:"name"` // 服务器名称
+	ID           int64  `json:"id"`             // 服务器ID
+	Name         string `json:"name"`           // 服务器名称
+	HostServerId int64  `json:"host_server_id"` // 托管该服务器的 Game 节点自身的服务器ID, 为 0 表示由同名节点托管
 }
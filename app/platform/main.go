@@ -3,11 +3,14 @@ package main
 import (
 	"github.com/godyy/ggs/app/platform/internal/app"
 	_ "github.com/godyy/ggs/app/platform/internal/handlers"
+	"github.com/godyy/ggskit/base/flags"
 	"github.com/godyy/ggskit/utils"
 )
 
 func main() {
-	app.Start()
+	flags.Parse()
+	app.Start(nil)
+	flags.Reset()
 	utils.ListenShutdown()
 	app.Stop()
 }
@@ -0,0 +1,35 @@
+// Package appflags 注册各应用共用的命令行参数.
+//
+// 同名参数只能注册一次, 集中在此注册, 使多个应用可以链接到同一进程.
+package appflags
+
+import (
+	"github.com/godyy/ggskit/base/config"
+	"github.com/godyy/ggskit/base/env"
+	"github.com/godyy/ggskit/base/flags"
+)
+
+func init() {
+	flags.String("config-path", "./configs/dev.toml", "config path")
+	env.AddFlag("server-id", int64(0), "server id")
+	env.AddFlag("node-index", 0, "node index under the same server, used to run several nodes per server")
+	config.AddFlag("cluster-port", 0, "cluster port, must > 0")
+	config.AddFlag("http-port", 0, "http port, 0 means disable http server")
+	config.AddFlag("enable-pprof", false, "enable pprof")
+}
+
+// ConfigPath 返回 config-path 指定的配置文件路径.
+func ConfigPath() string {
+	path, _ := flags.GetValue[string]("config-path")
+	return path
+}
+
+// ServerID 返回 env-server-id 指定的服务器ID.
+func ServerID() (int64, bool) {
+	return env.GetFlagValue[int64]("server-id")
+}
+
+// NodeIndex 返回 env-node-index 指定的同一服务器下的节点序号.
+func NodeIndex() (int, bool) {
+	return env.GetFlagValue[int]("node-index")
+}
@@ -2,18 +2,20 @@ package lifecycle
 
 type Callback func()
 
-var (
+// Hooks 应用生命周期回调.
+// 每个应用持有各自的回调, 多个应用运行在同一进程中时互不触发.
+type Hooks struct {
 	beforeStartCallbacks []Callback
-)
+}
 
 // RegisterBeforeStart 注册在启动前调用的回调函数.
-func RegisterBeforeStart(cb Callback) {
-	beforeStartCallbacks = append(beforeStartCallbacks, cb)
+func (h *Hooks) RegisterBeforeStart(cb Callback) {
+	h.beforeStartCallbacks = append(h.beforeStartCallbacks, cb)
 }
 
 // BeforeStart 调用启动前回调函数.
-func BeforeStart() {
-	for _, cb := range beforeStartCallbacks {
+func (h *Hooks) BeforeStart() {
+	for _, cb := range h.beforeStartCallbacks {
 		cb()
 	}
 }
@@ -0,0 +1,302 @@
+package net
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	proxyProtoV1MaxLen  = 107 // v1 头最大长度, 含 CRLF.
+	proxyProtoV2HeadLen = 16  // v2 固定头长度.
+)
+
+var (
+	proxyProtoV1Prefix = []byte("PROXY ")
+	proxyProtoV2Sig    = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// ErrProxyProtoHeader PROXY protocol 头错误.
+var ErrProxyProtoHeader = pkgerrors.New("invalid proxy protocol header")
+
+// ProxyProtoListener 解析 PROXY protocol v1/v2 头的监听器.
+// 来自受信任地址的连接必须携带 PROXY 头, 其余连接按直连处理, 不解析其 PROXY 头.
+type ProxyProtoListener struct {
+	net.Listener
+	trusted []*net.IPNet  // 受信任的来源网段.
+	timeout time.Duration // 读取 PROXY 头超时.
+}
+
+// ErrNoTrustedCIDRs 未配置受信任的来源网段.
+var ErrNoTrustedCIDRs = pkgerrors.New("proxy protocol requires trusted cidrs")
+
+// NewProxyProtoListener 创建 ProxyProtoListener. trustedCIDRs 不能为空,
+// 否则任意客户端均可通过伪造 PROXY 头冒充来源地址.
+func NewProxyProtoListener(l net.Listener, trustedCIDRs []string, timeout time.Duration) (*ProxyProtoListener, error) {
+	if len(trustedCIDRs) == 0 {
+		return nil, ErrNoTrustedCIDRs
+	}
+
+	trusted := make([]*net.IPNet, 0, len(trustedCIDRs))
+	for _, cidr := range trustedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, pkgerrors.WithMessagef(err, "parse trusted cidr %s", cidr)
+		}
+		trusted = append(trusted, ipNet)
+	}
+
+	return &ProxyProtoListener{
+		Listener: l,
+		trusted:  trusted,
+		timeout:  timeout,
+	}, nil
+}
+
+// Accept 接收连接. PROXY 头在首次读取或获取地址时解析, 不会阻塞 Accept.
+func (l *ProxyProtoListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.isTrusted(conn.RemoteAddr()) {
+		return conn, nil
+	}
+
+	return &proxyProtoConn{
+		Conn:    conn,
+		r:       bufio.NewReader(conn),
+		timeout: l.timeout,
+	}, nil
+}
+
+// isTrusted 返回来源地址是否受信任.
+func (l *ProxyProtoListener) isTrusted(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ipNet := range l.trusted {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyProtoConn 携带 PROXY 头的连接.
+type proxyProtoConn struct {
+	net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	once       sync.Once
+	err        error
+	remoteAddr net.Addr // PROXY 头中的源地址.
+	localAddr  net.Addr // PROXY 头中的目标地址.
+
+	mtx          sync.Mutex
+	readDeadline time.Time // 使用方设置的读超时, 解析 PROXY 头后恢复.
+}
+
+// SetDeadline 设置读写超时.
+func (c *proxyProtoConn) SetDeadline(t time.Time) error {
+	c.mtx.Lock()
+	c.readDeadline = t
+	c.mtx.Unlock()
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline 设置读超时.
+func (c *proxyProtoConn) SetReadDeadline(t time.Time) error {
+	c.mtx.Lock()
+	c.readDeadline = t
+	c.mtx.Unlock()
+	return c.Conn.SetReadDeadline(t)
+}
+
+// Read 读取数据.
+func (c *proxyProtoConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(b)
+}
+
+// RemoteAddr 返回真实的客户端地址.
+func (c *proxyProtoConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr 返回客户端连接的目标地址.
+func (c *proxyProtoConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.localAddr != nil {
+		return c.localAddr
+	}
+	return c.Conn.LocalAddr()
+}
+
+// readHeader 读取并解析 PROXY 头.
+func (c *proxyProtoConn) readHeader() {
+	c.mtx.Lock()
+	readDeadline := c.readDeadline
+	c.mtx.Unlock()
+
+	headerDeadline := time.Now().Add(c.timeout)
+	if !readDeadline.IsZero() && readDeadline.Before(headerDeadline) {
+		headerDeadline = readDeadline
+	}
+	c.Conn.SetReadDeadline(headerDeadline)
+	defer c.Conn.SetReadDeadline(readDeadline)
+
+	sig, err := c.r.Peek(len(proxyProtoV1Prefix))
+	if err != nil {
+		c.err = pkgerrors.WithMessage(err, "read proxy protocol header")
+		return
+	}
+
+	if bytes.Equal(sig, proxyProtoV1Prefix) {
+		c.err = c.readHeaderV1()
+		return
+	}
+
+	if sig, err = c.r.Peek(len(proxyProtoV2Sig)); err == nil && bytes.Equal(sig, proxyProtoV2Sig) {
+		c.err = c.readHeaderV2()
+		return
+	}
+
+	c.err = ErrProxyProtoHeader
+}
+
+// readHeaderV1 解析文本格式的 v1 头, 例如 "PROXY TCP4 1.2.3.4 5.6.7.8 1234 443\r\n".
+func (c *proxyProtoConn) readHeaderV1() error {
+	line := make([]byte, 0, proxyProtoV1MaxLen)
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return pkgerrors.WithMessage(err, "read proxy protocol v1 header")
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= proxyProtoV1MaxLen {
+			return ErrProxyProtoHeader
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return ErrProxyProtoHeader
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) < 2 {
+		return ErrProxyProtoHeader
+	}
+	switch fields[1] {
+	case "UNKNOWN":
+		// 未知协议, 使用连接本身的地址.
+		return nil
+	case "TCP4", "TCP6":
+		if len(fields) != 6 {
+			return ErrProxyProtoHeader
+		}
+	default:
+		return ErrProxyProtoHeader
+	}
+
+	src, err := parseProxyProtoV1Addr(fields[2], fields[4])
+	if err != nil {
+		return err
+	}
+	dst, err := parseProxyProtoV1Addr(fields[3], fields[5])
+	if err != nil {
+		return err
+	}
+	c.remoteAddr, c.localAddr = src, dst
+	return nil
+}
+
+// parseProxyProtoV1Addr 解析 v1 头中的地址.
+func parseProxyProtoV1Addr(ip, port string) (*net.TCPAddr, error) {
+	addr := &net.TCPAddr{IP: net.ParseIP(ip)}
+	if addr.IP == nil {
+		return nil, ErrProxyProtoHeader
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, ErrProxyProtoHeader
+	}
+	addr.Port = int(p)
+	return addr, nil
+}
+
+// readHeaderV2 解析二进制格式的 v2 头.
+func (c *proxyProtoConn) readHeaderV2() error {
+	var head [proxyProtoV2HeadLen]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		return pkgerrors.WithMessage(err, "read proxy protocol v2 header")
+	}
+
+	verCmd, fam := head[12], head[13]
+	if verCmd>>4 != 2 {
+		return ErrProxyProtoHeader
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(head[14:16]))
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return pkgerrors.WithMessage(err, "read proxy protocol v2 addresses")
+	}
+
+	switch verCmd & 0x0f {
+	case 0x0:
+		// LOCAL, 例如负载均衡器的健康检查, 使用连接本身的地址.
+		return nil
+	case 0x1:
+		// PROXY.
+	default:
+		return ErrProxyProtoHeader
+	}
+
+	// 仅处理 TCP, 其余协议族使用连接本身的地址.
+	if fam&0x0f != 0x1 {
+		return nil
+	}
+
+	var ipLen int
+	switch fam >> 4 {
+	case 0x1:
+		ipLen = net.IPv4len
+	case 0x2:
+		ipLen = net.IPv6len
+	default:
+		return nil
+	}
+	if len(payload) < 2*ipLen+4 {
+		return ErrProxyProtoHeader
+	}
+
+	c.remoteAddr = &net.TCPAddr{
+		IP:   net.IP(payload[:ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen:])),
+	}
+	c.localAddr = &net.TCPAddr{
+		IP:   net.IP(payload[ipLen : 2*ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen+2:])),
+	}
+	return nil
+}
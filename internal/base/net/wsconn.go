@@ -0,0 +1,119 @@
+package net
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ErrWSMessageType WebSocket 消息类型错误.
+var ErrWSMessageType = pkgerrors.New("websocket message type must be binary")
+
+// WSConn 将 WebSocket 连接适配为 net.Conn, 以复用基于流的数据包读写逻辑.
+//
+// 读取时将收到的二进制帧视为连续的字节流; 写入时按 4 字节长度前缀切分数据包,
+// 每个完整的数据包(长度 + 负载)作为一个二进制帧发送.
+type WSConn struct {
+	ws *websocket.Conn
+
+	rmtx   sync.Mutex
+	reader io.Reader // 当前正在读取的帧.
+
+	wmtx sync.Mutex
+	wbuf bytes.Buffer // 未凑齐一个完整数据包的写入数据.
+}
+
+// NewWSConn 创建 WSConn.
+func NewWSConn(ws *websocket.Conn) *WSConn {
+	return &WSConn{ws: ws}
+}
+
+// Read 读取数据.
+func (c *WSConn) Read(b []byte) (int, error) {
+	c.rmtx.Lock()
+	defer c.rmtx.Unlock()
+
+	for {
+		if c.reader == nil {
+			mt, r, err := c.ws.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			if mt != websocket.BinaryMessage {
+				return 0, ErrWSMessageType
+			}
+			c.reader = r
+		}
+
+		n, err := c.reader.Read(b)
+		if err == io.EOF {
+			c.reader = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Write 写入数据, 每凑齐一个完整数据包即发送一个二进制帧.
+func (c *WSConn) Write(b []byte) (int, error) {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+
+	c.wbuf.Write(b)
+	for c.wbuf.Len() >= 4 {
+		n := int(binary.BigEndian.Uint32(c.wbuf.Bytes()[:4]))
+		if c.wbuf.Len() < 4+n {
+			break
+		}
+		if err := c.ws.WriteMessage(websocket.BinaryMessage, c.wbuf.Next(4+n)); err != nil {
+			return 0, err
+		}
+	}
+	if c.wbuf.Len() == 0 {
+		c.wbuf.Reset()
+	}
+
+	return len(b), nil
+}
+
+// Close 关闭连接.
+func (c *WSConn) Close() error {
+	return c.ws.Close()
+}
+
+// LocalAddr 返回本地地址.
+func (c *WSConn) LocalAddr() net.Addr {
+	return c.ws.LocalAddr()
+}
+
+// RemoteAddr 返回远端地址.
+func (c *WSConn) RemoteAddr() net.Addr {
+	return c.ws.RemoteAddr()
+}
+
+// SetDeadline 设置读写超时.
+func (c *WSConn) SetDeadline(t time.Time) error {
+	if err := c.ws.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.ws.SetWriteDeadline(t)
+}
+
+// SetReadDeadline 设置读超时.
+func (c *WSConn) SetReadDeadline(t time.Time) error {
+	return c.ws.SetReadDeadline(t)
+}
+
+// SetWriteDeadline 设置写超时.
+func (c *WSConn) SetWriteDeadline(t time.Time) error {
+	return c.ws.SetWriteDeadline(t)
+}
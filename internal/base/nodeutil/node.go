@@ -20,3 +20,12 @@ func NewServerNode(category string, serverId int64, addr string) *cluster.Node {
 		ServerId: serverId,
 	}
 }
+
+// MakeIndexedServerNodeName 使用 serverId 与节点序号构造节点名,
+// 用于同一服务器下部署多个同类节点. 序号为 0 时与 MakeServerNodeName 相同.
+func MakeIndexedServerNodeName(serverId int64, index int) string {
+	if index == 0 {
+		return MakeServerNodeName(serverId)
+	}
+	return MakeServerNodeName(serverId) + "-" + strconv.Itoa(index)
+}
@@ -0,0 +1,23 @@
+package actors
+
+import (
+	"sync/atomic"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ErrDraining 节点排空中, 拒绝启动新的玩家Actor.
+var ErrDraining = pkgerrors.New("node is draining")
+
+// draining 节点是否排空中.
+var draining atomic.Bool
+
+// SetDraining 设置节点是否排空中, 排空中拒绝启动新的玩家Actor.
+func SetDraining(v bool) {
+	draining.Store(v)
+}
+
+// Draining 返回节点是否排空中.
+func Draining() bool {
+	return draining.Load()
+}
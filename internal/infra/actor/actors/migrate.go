@@ -0,0 +1,94 @@
+package actors
+
+import (
+	"sync"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ErrMigrating 玩家已处于迁移中.
+var ErrMigrating = pkgerrors.New("player is migrating")
+
+// StopWatch 玩家Actor停止观察, 回报停止时的持久化结果.
+type StopWatch struct {
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// Done 返回玩家Actor停止通知.
+func (w *StopWatch) Done() <-chan struct{} {
+	return w.done
+}
+
+// Err 返回玩家Actor停止时的持久化结果, 在 Done 之后有效.
+func (w *StopWatch) Err() error {
+	return w.err
+}
+
+// finish 回报持久化结果.
+func (w *StopWatch) finish(err error) {
+	w.once.Do(func() {
+		w.err = err
+		close(w.done)
+	})
+}
+
+// stopWatches 玩家Actor停止观察, 玩家ID -> *StopWatch.
+var stopWatches sync.Map
+
+// WatchStop 观察玩家Actor停止, 已被观察时返回同一个 StopWatch.
+// 观察结束后须调用 UnwatchStop.
+func WatchStop(id int64) *StopWatch {
+	w := &StopWatch{done: make(chan struct{})}
+	v, _ := stopWatches.LoadOrStore(id, w)
+	return v.(*StopWatch)
+}
+
+// UnwatchStop 取消观察玩家Actor停止.
+func UnwatchStop(id int64) {
+	stopWatches.Delete(id)
+}
+
+// getStopWatch 返回玩家Actor停止观察.
+func getStopWatch(id int64) *StopWatch {
+	if v, ok := stopWatches.Load(id); ok {
+		return v.(*StopWatch)
+	}
+	return nil
+}
+
+// Migration 玩家迁移. 迁移期间玩家Actor停止时推送迁移通知, 并回报持久化结果.
+type Migration struct {
+	StopWatch
+	Target string // 目标节点ID.
+}
+
+// migrations 进行中的迁移, 玩家ID -> *Migration.
+var migrations sync.Map
+
+// BeginMigration 登记玩家迁移, 玩家已处于迁移中时返回 ErrMigrating.
+// 迁移结束后须调用 EndMigration.
+func BeginMigration(id int64, target string) (*Migration, error) {
+	m := &Migration{
+		StopWatch: StopWatch{done: make(chan struct{})},
+		Target:    target,
+	}
+	if _, loaded := migrations.LoadOrStore(id, m); loaded {
+		return nil, ErrMigrating
+	}
+	return m, nil
+}
+
+// EndMigration 注销玩家迁移.
+func EndMigration(id int64) {
+	migrations.Delete(id)
+}
+
+// getMigration 返回玩家进行中的迁移.
+func getMigration(id int64) *Migration {
+	if v, ok := migrations.Load(id); ok {
+		return v.(*Migration)
+	}
+	return nil
+}
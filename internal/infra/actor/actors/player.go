@@ -1,6 +1,8 @@
 package actors
 
 import (
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/godyy/gactor"
@@ -9,6 +11,7 @@ import (
 	actors "github.com/godyy/ggs/internal/infra/actor"
 	"github.com/godyy/ggs/internal/infra/actor/lifecycle"
 	"github.com/godyy/ggs/internal/infra/actor/model/player"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
 	"github.com/godyy/ggskit/infra/actor"
 )
 
@@ -16,11 +19,36 @@ import (
 type Player struct {
 	actors.CActorWithModule[*player.Model] // 集成携带数据模型的Actor封装
 
+	started           bool           // 是否已启动, 计入活跃玩家数量.
 	isLogin           bool           // 是否已登录.
 	heartbeatTimerId  gactor.TimerId // 心跳定时器ID.
 	lastHeartbeatTime int64          // 上一次心跳处理时间.
 }
 
+// activePlayers 当前进程中已启动的玩家Actor.
+var activePlayers struct {
+	count atomic.Int64
+	ids   sync.Map // 玩家ID -> struct{}
+}
+
+// ActivePlayers 返回当前进程中已启动的玩家Actor数量.
+func ActivePlayers() int64 {
+	return activePlayers.count.Load()
+}
+
+// IsPlayerActive 返回玩家Actor是否已在当前进程中启动.
+func IsPlayerActive(id int64) bool {
+	_, ok := activePlayers.ids.Load(id)
+	return ok
+}
+
+// RangeActivePlayers 遍历当前进程中已启动的玩家Actor ID, f 返回 false 时停止遍历.
+func RangeActivePlayers(f func(id int64) bool) {
+	activePlayers.ids.Range(func(k, _ any) bool {
+		return f(k.(int64))
+	})
+}
+
 // NewPlayer 构造玩家Actor.
 func NewPlayer(actor actor.CActor) *Player {
 	p := &Player{
@@ -34,19 +62,62 @@ func (p *Player) OnStart() error {
 	// 构造model
 	p.Model = player.New(p.ActorUID().ID)
 
+	// 排空中, 拒绝启动.
+	if Draining() {
+		return ErrDraining
+	}
+
 	if err := p.CActorWithModule.OnStart(); err != nil {
 		return err
 	}
 
-	return lifecycle.OnStart(p)
+	if err := lifecycle.OnStart(p); err != nil {
+		return err
+	}
+
+	p.started = true
+	activePlayers.ids.Store(p.ID(), struct{}{})
+	activePlayers.count.Add(1)
+	return nil
 }
 
 // OnStop 停机行为.
 func (p *Player) OnStop() error {
+	if p.started {
+		p.started = false
+		activePlayers.ids.Delete(p.ID())
+		activePlayers.count.Add(-1)
+	}
+
+	// 迁移中, 通知网关重新连接至目标节点; 排空中, 通知网关以重新登录的原因断开客户端.
+	m := getMigration(p.ID())
+	if m != nil {
+		if err := p.Sugared().PushRawMessage(&pbc2s.PlayerMigratePush{TargetNodeId: m.Target}); err != nil {
+			logger.Get().Errorf("player %d push migrate failed, %v", p.ID(), err)
+		}
+	} else if Draining() && p.IsLogin() {
+		if err := p.Sugared().PushRawMessage(&pbc2s.PlayerStopPush{Reason: pbc2s.DisconnectPush_Reconnect}); err != nil {
+			logger.Get().Errorf("player %d push stop failed, %v", p.ID(), err)
+		}
+	}
+
 	// 调用生命周期回调
 	lifecycle.OnStop(p)
 
-	return p.CActorWithModule.OnStop()
+	// 持久化, 迁移须在持久化成功后才能更新位置.
+	err := p.CActorWithModule.OnStop()
+	if m != nil {
+		if err != nil {
+			if err := p.Sugared().PushRawMessage(&pbc2s.PlayerMigratePush{TargetNodeId: m.Target, Aborted: true}); err != nil {
+				logger.Get().Errorf("player %d push migrate aborted failed, %v", p.ID(), err)
+			}
+		}
+		m.finish(err)
+	}
+	if w := getStopWatch(p.ID()); w != nil {
+		w.finish(err)
+	}
+	return err
 }
 
 // OnConnected 已连接行为.
@@ -1,8 +1,11 @@
 package convert
 
 import (
+	"cmp"
+	"slices"
+
 	"github.com/godyy/ggs/internal/infra/actor/model/player"
-	pbcommon "github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
 )
 
 func Item2PB(item player.Item) *pbcommon.Item {
@@ -19,3 +22,15 @@ func Items2PB(items []player.Item) []*pbcommon.Item {
 	}
 	return pb
 }
+
+// ItemsModule2PB 将道具模块转换为按道具ID排序的道具列表.
+func ItemsModule2PB(m *player.Items) []*pbcommon.Item {
+	pb := make([]*pbcommon.Item, 0, len(m.Items))
+	for _, item := range m.Items {
+		pb = append(pb, Item2PB(*item))
+	}
+	slices.SortFunc(pb, func(a, b *pbcommon.Item) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return pb
+}
@@ -0,0 +1,12 @@
+package convert
+
+import (
+	"github.com/godyy/ggs/internal/infra/actor/model/player"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+)
+
+func PlayerBase2PB(base *player.BaseInfo) *pbcommon.PlayerBase {
+	return &pbcommon.PlayerBase{
+		Name: base.Name,
+	}
+}
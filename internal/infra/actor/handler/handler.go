@@ -4,11 +4,11 @@ import (
 	"reflect"
 
 	iactor "github.com/godyy/ggs/internal/infra/actor"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
-	pbcommon "github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
 	pbs2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
 	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
 	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/s2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
 	"github.com/godyy/ggskit/infra/actor"
 	pkgerrors "github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// PushMerger 推送合并器.
+// 同一请求内产生的同类型推送在发送前依次合并到首条推送中, 合并后的推送位于首条推送的位置.
+type PushMerger[T proto.Message] interface {
+	// Merge 将后产生的推送 src 合并到 dst.
+	Merge(dst, src T)
+}
+
+// PushMergeFunc 函数形式的推送合并器.
+type PushMergeFunc[T proto.Message] func(dst, src T)
+
+// Merge 实现 PushMerger.
+func (f PushMergeFunc[T]) Merge(dst, src T) {
+	f(dst, src)
+}
+
+// pushMergers 推送类型 -> 合并函数.
+var pushMergers = map[reflect.Type]func(dst, src proto.Message){}
+
+// RegisterPushMerger 注册推送合并器. 须在 init 阶段调用, 重复注册 panic.
+func RegisterPushMerger[T proto.Message](merger PushMerger[T]) {
+	typ := reflect.TypeFor[T]()
+	if _, ok := pushMergers[typ]; ok {
+		panic(fmt.Errorf("push merger of %s already registered", typ))
+	}
+	pushMergers[typ] = func(dst, src proto.Message) {
+		merger.Merge(dst.(T), src.(T))
+	}
+}
+
+// coalescePushMsgs 合并推送消息队列中可合并的同类型推送.
+// 首条推送在首次合并前被克隆, 不修改调用方追加的消息.
+func coalescePushMsgs(msgQueue []proto.Message) []proto.Message {
+	if len(msgQueue) < 2 {
+		return msgQueue
+	}
+
+	type target struct {
+		idx    int  // 合并目标在 result 中的位置
+		cloned bool // 合并目标是否已克隆
+	}
+
+	var (
+		result  = make([]proto.Message, 0, len(msgQueue))
+		targets map[reflect.Type]*target
+	)
+	for _, msg := range msgQueue {
+		typ := reflect.TypeOf(msg)
+		merge, ok := pushMergers[typ]
+		if !ok {
+			result = append(result, msg)
+			continue
+		}
+
+		t, ok := targets[typ]
+		if !ok {
+			if targets == nil {
+				targets = make(map[reflect.Type]*target)
+			}
+			targets[typ] = &target{idx: len(result)}
+			result = append(result, msg)
+			continue
+		}
+		if !t.cloned {
+			result[t.idx] = proto.Clone(result[t.idx])
+			t.cloned = true
+		}
+		merge(result[t.idx], msg)
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCoalescePushMsgs(t *testing.T) {
+	RegisterPushMerger(PushMergeFunc[*pbc2s.ItemPush](func(dst, src *pbc2s.ItemPush) {
+		dst.Items = append(dst.Items, src.Items...)
+	}))
+	defer delete(pushMergers, reflect.TypeFor[*pbc2s.ItemPush]())
+
+	item1 := &pbc2s.ItemPush{Items: []*pbcommon.Item{{Id: 1, Count: 1}}}
+	item2 := &pbc2s.ItemPush{Items: []*pbcommon.Item{{Id: 2, Count: 2}}}
+	system1 := &pbc2s.SystemPush{Content: "a"}
+	system2 := &pbc2s.SystemPush{Content: "b"}
+
+	result := coalescePushMsgs([]proto.Message{system1, item1, system2, item2})
+	if assert.Len(t, result, 3) {
+		// 不可合并的推送保持原样, 合并后的推送位于首条的位置.
+		assert.Same(t, system1, result[0])
+		assert.True(t, proto.Equal(&pbc2s.ItemPush{Items: []*pbcommon.Item{{Id: 1, Count: 1}, {Id: 2, Count: 2}}}, result[1]))
+		assert.Same(t, system2, result[2])
+	}
+	// 调用方追加的消息未被修改.
+	assert.Len(t, item1.Items, 1)
+
+	// 单条推送不克隆.
+	result = coalescePushMsgs([]proto.Message{system1, item1})
+	assert.Same(t, item1, result[1])
+}
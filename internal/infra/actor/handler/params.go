@@ -7,6 +7,7 @@ import (
 
 var (
 	ctxKeyPushMsgQueue = actor.NewCtxK[[]proto.Message]() // 推送的消息队列
+	ctxKeyPushSent     = actor.NewCtxK[[]func(error)]()   // 推送发送完成回调
 )
 
 // GetArgs 获取当前上下文的请求参数.
@@ -14,9 +15,16 @@ func GetArgs[Args proto.Message](ctx *actor.Context) Args {
 	return actor.SugarContext(ctx).GetMsg().(Args)
 }
 
-// AppendPushMsg 追加推送消息.
+// AppendPushMsg 追加推送消息, 注册了合并器的推送在发送前合并, 参见 RegisterPushMerger.
 func AppendPushMsg(ctx *actor.Context, msg proto.Message) {
 	msgQueue, _ := actor.CtxKGet(ctx, ctxKeyPushMsgQueue)
 	msgQueue = append(msgQueue, msg)
 	actor.CtxKSet(ctx, ctxKeyPushMsgQueue, msgQueue)
 }
+
+// OnPushSent 注册推送发送完成回调, 在当前请求的推送全部发送后于 Actor 协程中调用,
+// err 为首个发送失败的错误, 全部成功时为 nil.
+func OnPushSent(ctx *actor.Context, f func(err error)) {
+	callbacks, _ := actor.CtxKGet(ctx, ctxKeyPushSent)
+	actor.CtxKSet(ctx, ctxKeyPushSent, append(callbacks, f))
+}
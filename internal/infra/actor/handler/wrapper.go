@@ -2,9 +2,9 @@ package handler
 
 import (
 	"github.com/godyy/ggs/internal/infra/actor"
-	pbc2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
-	pbcommon "github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
 	pbs2s "github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -75,11 +75,19 @@ func replyS2SError(ctx *actor.Context, err error) {
 	actor.SugarContext(ctx).Reply(respErr)
 }
 
-// handlePushMsgQueue 处理推送消息队列.
+// handlePushMsgQueue 处理推送消息队列, 可合并的推送合并后发送, 随后调用推送发送完成回调.
 func handlePushMsgQueue(ctx *actor.Context) {
 	sugared := actor.CSugared{CActor: ctx.Actor().(actor.CActor)}
 	msgQueue, _ := actor.CtxKGet(ctx, ctxKeyPushMsgQueue)
-	for _, msg := range msgQueue {
-		sugared.PushRawMessage(msg)
+	var sendErr error
+	for _, msg := range coalescePushMsgs(msgQueue) {
+		if err := sugared.PushRawMessage(msg); err != nil && sendErr == nil {
+			sendErr = err
+		}
+	}
+
+	callbacks, _ := actor.CtxKGet(ctx, ctxKeyPushSent)
+	for _, f := range callbacks {
+		f(sendErr)
 	}
 }
@@ -11,6 +11,7 @@ import (
 type InitConfig struct {
 	Persist           *persist.InitConfig  // 持久化配置
 	DB                string               // 数据库名
+	ActorDB           ActorDB              // Actor 数据库名解析, 为空时统一使用 DB
 	AsyncSaveCallback AsyncSaveCallback    // 异步存储回调
 	ActorSaveDelay    time.Duration        // actor 保存延迟
 	ProtoRegistry     *actor.ProtoRegistry // 协议注册表
@@ -19,6 +20,7 @@ type InitConfig struct {
 var (
 	initialized       bool              // 是否初始化
 	db                string            // 数据库名
+	actorDB           ActorDB           // Actor 数据库名解析
 	asyncSaveCallback AsyncSaveCallback // 异步存储回调
 	actorSaveDelay    time.Duration     // actor 保存延迟
 )
@@ -30,6 +32,7 @@ func Init(cfg *InitConfig) {
 	}
 	persist.Init(cfg.Persist)
 	db = cfg.DB
+	actorDB = cfg.ActorDB
 	asyncSaveCallback = cfg.AsyncSaveCallback
 	actorSaveDelay = cfg.ActorSaveDelay
 	actorSugarUtil = actor.NewActorSugarUtil(cfg.ProtoRegistry)
@@ -0,0 +1,44 @@
+package player
+
+// QueuedPush 玩家离线时暂存的推送.
+type QueuedPush struct {
+	TypeURL string `bson:"typeUrl"` // 消息类型URL.
+	Value   []byte `bson:"value"`   // 消息体.
+	Time    int64  `bson:"time"`    // 暂存时间, Unix 毫秒.
+}
+
+// PushQueue 离线推送模块.
+// 暂存玩家离线期间需要保留的推送, 登录后下发.
+type PushQueue struct {
+	Pushes []*QueuedPush `bson:"pushes"`
+}
+
+// OnInit 初始化离线推送模块.
+func (m *PushQueue) OnInit() {
+	m.Pushes = nil
+}
+
+// ModuleKey 模块关键字.
+func (m *PushQueue) ModuleKey() string {
+	return "pushQueue"
+}
+
+// Append 暂存推送, 超过 limit 条时丢弃最早的推送, 返回丢弃的数量.
+func (m *PushQueue) Append(push *QueuedPush, limit int) int {
+	m.Pushes = append(m.Pushes, push)
+	dropped := len(m.Pushes) - limit
+	if dropped <= 0 {
+		return 0
+	}
+	m.Pushes = append(m.Pushes[:0:0], m.Pushes[dropped:]...)
+	return dropped
+}
+
+// Remove 移除最早暂存的 n 条推送.
+func (m *PushQueue) Remove(n int) {
+	if n >= len(m.Pushes) {
+		m.Pushes = nil
+		return
+	}
+	m.Pushes = append(m.Pushes[:0:0], m.Pushes[n:]...)
+}
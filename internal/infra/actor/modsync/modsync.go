@@ -0,0 +1,82 @@
+// Package modsync 玩家模块同步.
+//
+// 模块注册快照转换器与变更推送构造器, 登录时由注册表生成全部模块的快照,
+// 模块变更后由同一注册表生成该模块的增量推送.
+package modsync
+
+import (
+	"fmt"
+
+	iactor "github.com/godyy/ggs/internal/infra/actor"
+	"github.com/godyy/ggs/internal/infra/actor/handler"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	"github.com/godyy/ggskit/infra/actor"
+	"google.golang.org/protobuf/proto"
+)
+
+// Syncer 模块同步器.
+type Syncer[M actor.Module] struct {
+	// Snapshot 生成模块的全量快照.
+	Snapshot func(m M) *pbcommon.ModuleSnapshot
+
+	// Update 生成模块的增量推送. keys 为变更条目的键, 为空表示整个模块变更.
+	Update func(m M, keys []int64) proto.Message
+}
+
+// syncer 类型擦除后的模块同步器.
+type syncer struct {
+	moduleKey string
+	snapshot  func(a actor.ActorWithModule) *pbcommon.ModuleSnapshot
+	update    func(m actor.Module, keys []int64) proto.Message
+}
+
+var (
+	syncers     []*syncer              // 按注册顺序
+	syncerByKey = map[string]*syncer{} // 模块关键字 -> 同步器
+)
+
+// Register 注册模块同步器. 须在 init 阶段调用, 重复注册 panic.
+func Register[M actor.Module](s Syncer[M]) {
+	if s.Snapshot == nil || s.Update == nil {
+		panic("modsync: Snapshot and Update must not be nil")
+	}
+
+	var zero M
+	moduleKey := zero.ModuleKey()
+	if _, ok := syncerByKey[moduleKey]; ok {
+		panic(fmt.Errorf("modsync: syncer of module %s already registered", moduleKey))
+	}
+
+	ss := &syncer{
+		moduleKey: moduleKey,
+		snapshot: func(a actor.ActorWithModule) *pbcommon.ModuleSnapshot {
+			return s.Snapshot(iactor.GetActorModule[M](a, true))
+		},
+		update: func(m actor.Module, keys []int64) proto.Message {
+			return s.Update(m.(M), keys)
+		},
+	}
+	syncers = append(syncers, ss)
+	syncerByKey[moduleKey] = ss
+}
+
+// Snapshots 按注册顺序生成全部模块的快照.
+func Snapshots(a actor.ActorWithModule) []*pbcommon.ModuleSnapshot {
+	snapshots := make([]*pbcommon.ModuleSnapshot, 0, len(syncers))
+	for _, s := range syncers {
+		snapshots = append(snapshots, s.snapshot(a))
+	}
+	return snapshots
+}
+
+// Changed 通知模块发生变更, 生成增量推送并随当前请求下发.
+// keys 为变更条目的键, 为空表示整个模块变更. 未注册同步器的模块忽略.
+func Changed(ctx *iactor.Context, m actor.Module, keys ...int64) {
+	s, ok := syncerByKey[m.ModuleKey()]
+	if !ok {
+		return
+	}
+	if msg := s.update(m, keys); msg != nil {
+		handler.AppendPushMsg(ctx, msg)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/godyy/gactor"
 	"github.com/godyy/ggs/internal/base/logger"
 	"github.com/godyy/ggs/internal/infra/actor/persist"
+	pkgerrors "github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -13,9 +14,47 @@ type ActorSaveWithTimer = persist.ActorSaveWithTimer
 
 type AsyncSaveCallback func(uid gactor.ActorUID, err error)
 
+// ActorDB 解析 Actor 数据所在的数据库名, 例如按 Actor 所属服务器区分数据库.
+type ActorDB func(uid ActorUID) (string, error)
+
 // persistor 持久化辅助结构
 type persistor struct {
 	saveTimerId TimerId // save 定时器ID
+	db          string  // 数据库名, 加载时解析
+}
+
+// persistDB 返回加载时解析的数据库名.
+func (p *persistor) persistDB() string {
+	return p.db
+}
+
+// setPersistDB 设置数据库名.
+func (p *persistor) setPersistDB(db string) {
+	p.db = db
+}
+
+// dbHolder 缓存数据库名的 Actor, 由 persistor 实现.
+type dbHolder interface {
+	persistDB() string
+	setPersistDB(db string)
+}
+
+// resolveDB 解析 Actor 的数据库名.
+func resolveDB(a ActorSaveWithTimer) (string, error) {
+	if h, ok := a.(dbHolder); ok && h.persistDB() != "" {
+		return h.persistDB(), nil
+	}
+	if actorDB == nil {
+		return db, nil
+	}
+	name, err := actorDB(a.ActorUID())
+	if err != nil {
+		return "", pkgerrors.WithMessage(err, "resolve actor db")
+	}
+	if h, ok := a.(dbHolder); ok {
+		h.setPersistDB(name)
+	}
+	return name, nil
 }
 
 func (p *persistor) SaveTimerId() TimerId {
@@ -29,24 +68,41 @@ func (p *persistor) SetSaveTimerId(timerId TimerId) {
 // LoadModel 加载Actor模型.
 func LoadModel(a ActorSaveWithTimer) (bool, error) {
 	checkState()
+	db, err := resolveDB(a)
+	if err != nil {
+		return false, err
+	}
 	return persist.LoadModel(a.GetModel(), db)
 }
 
 // SaveModel 保存Actor模型.
 func SaveModel(a ActorSaveWithTimer) error {
 	checkState()
+	db, err := resolveDB(a)
+	if err != nil {
+		return err
+	}
 	return persist.SaveModel(a.GetModel(), db)
 }
 
 // AsyncSaveModel 异步保存Actor模型.
 func AsyncSaveModel(a ActorSaveWithTimer) error {
 	checkState()
+	db, err := resolveDB(a)
+	if err != nil {
+		return err
+	}
 	return persist.AsyncSaveModel(a.ActorUID(), a.GetModel(), db, asyncSaveModelCallback)
 }
 
 // DelaySave 延迟保存Actor.
 func DelaySave(a ActorSaveWithTimer, delay time.Duration) {
 	checkState()
+	db, err := resolveDB(a)
+	if err != nil {
+		asyncSaveModelCallback(a.ActorUID(), err)
+		return
+	}
 	persist.DelaySaveActorModel(a, db, delay, asyncSaveModelCallback)
 }
 
@@ -0,0 +1,153 @@
+// Package fragment 提供 c2s 数据包的分片与重组.
+//
+// 长度超过单个数据包上限的数据包被拆分为若干 FragmentNtf 分片数据包依次发送,
+// 分片数据包的 pt、seq 与原数据包相同, 接收方按序重组出原数据包.
+// 不同消息的分片允许交错到达, 同一消息的分片必须按序到达.
+package fragment
+
+import (
+	c2sregistry "github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	"github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/godyy/ggskit/base/protocol"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	// DefaultChunkSize 默认分片大小.
+	DefaultChunkSize = 64 * 1024
+
+	// DefaultMaxMessageLen 默认重组后数据包最大长度, 与集群会话的最大包长度一致.
+	DefaultMaxMessageLen = 1024 * 1024
+
+	// DefaultMaxPartials 默认同时重组中的消息数量上限.
+	DefaultMaxPartials = 4
+)
+
+var (
+	// ErrMessageTooLarge 重组后数据包超过长度上限.
+	ErrMessageTooLarge = pkgerrors.New("fragmented message too large")
+
+	// ErrTooManyPartials 同时重组中的消息过多.
+	ErrTooManyPartials = pkgerrors.New("too many partial messages")
+
+	// ErrInvalidFragment 分片不合法.
+	ErrInvalidFragment = pkgerrors.New("invalid fragment")
+)
+
+// fragmentPid FragmentNtf 的协议ID.
+var fragmentPid protocol.PID
+
+func init() {
+	pid, ok := c2sregistry.Registry.GetPid((*c2s.FragmentNtf)(nil))
+	if !ok {
+		panic("fragment: FragmentNtf not registered")
+	}
+	fragmentPid = pid
+}
+
+// IsFragment 返回数据包是否为分片.
+func IsFragment(p []byte) bool {
+	return len(p) >= codecc2s.HeadLen && codecc2s.HeadGetPid(p) == fragmentPid
+}
+
+// Split 将数据包 p 按 chunkSize 拆分为分片数据包, id 为消息编号.
+func Split(p []byte, id uint32, chunkSize int) ([][]byte, error) {
+	if len(p) < codecc2s.HeadLen {
+		return nil, ErrInvalidFragment
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	pt, seq := codecc2s.HeadGetPt(p), codecc2s.HeadGetSeq(p)
+	total := (len(p) + chunkSize - 1) / chunkSize
+	packets := make([][]byte, 0, total)
+	for i := 0; i < total; i++ {
+		end := min((i+1)*chunkSize, len(p))
+		fp, err := codecc2s.EncodePacket(c2sregistry.Registry, pt, seq, &c2s.FragmentNtf{
+			Id:    id,
+			Index: uint32(i),
+			Total: uint32(total),
+			Data:  p[i*chunkSize : end],
+		})
+		if err != nil {
+			return nil, pkgerrors.WithMessagef(err, "encode fragment %d/%d", i, total)
+		}
+		packets = append(packets, fp)
+	}
+	return packets, nil
+}
+
+// partial 重组中的消息.
+type partial struct {
+	total uint32 // 分片总数.
+	next  uint32 // 下一个分片序号.
+	data  []byte // 已收到的数据.
+}
+
+// Reassembler 分片重组器, 非并发安全.
+type Reassembler struct {
+	maxMessageLen int                 // 重组后数据包最大长度.
+	maxPartials   int                 // 同时重组中的消息数量上限.
+	partials      map[uint32]*partial // 重组中的消息.
+}
+
+// NewReassembler 创建 Reassembler, 参数不大于 0 时使用默认值.
+func NewReassembler(maxMessageLen, maxPartials int) *Reassembler {
+	if maxMessageLen <= 0 {
+		maxMessageLen = DefaultMaxMessageLen
+	}
+	if maxPartials <= 0 {
+		maxPartials = DefaultMaxPartials
+	}
+	return &Reassembler{
+		maxMessageLen: maxMessageLen,
+		maxPartials:   maxPartials,
+		partials:      make(map[uint32]*partial),
+	}
+}
+
+// Add 处理分片数据包 p. 消息重组完成时返回原数据包, 否则返回 nil.
+func (r *Reassembler) Add(p []byte) ([]byte, error) {
+	msg, err := codecc2s.DecodeMessage(c2sregistry.Registry, p)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "decode fragment")
+	}
+	f, ok := msg.(*c2s.FragmentNtf)
+	if !ok || len(f.Data) == 0 {
+		return nil, ErrInvalidFragment
+	}
+
+	pm := r.partials[f.Id]
+	if pm == nil {
+		if f.Index != 0 || f.Total == 0 {
+			return nil, ErrInvalidFragment
+		}
+		if len(r.partials) >= r.maxPartials {
+			return nil, ErrTooManyPartials
+		}
+		pm = &partial{total: f.Total}
+		r.partials[f.Id] = pm
+	} else if f.Index != pm.next || f.Total != pm.total {
+		delete(r.partials, f.Id)
+		return nil, ErrInvalidFragment
+	}
+
+	if len(pm.data)+len(f.Data) > r.maxMessageLen {
+		delete(r.partials, f.Id)
+		return nil, ErrMessageTooLarge
+	}
+	pm.data = append(pm.data, f.Data...)
+	pm.next++
+	if pm.next < pm.total {
+		return nil, nil
+	}
+
+	// 重组完成.
+	delete(r.partials, f.Id)
+	if len(pm.data) < codecc2s.HeadLen || IsFragment(pm.data) {
+		return nil, ErrInvalidFragment
+	}
+	return pm.data, nil
+}
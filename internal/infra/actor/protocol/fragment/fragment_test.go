@@ -0,0 +1,90 @@
+package fragment
+
+import (
+	"testing"
+
+	c2sregistry "github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/stretchr/testify/require"
+)
+
+// genPacket 生成包含 n 个道具的 ItemPush 数据包.
+func genPacket(t *testing.T, n int) []byte {
+	push := &pbc2s.ItemPush{}
+	for i := 0; i < n; i++ {
+		push.Items = append(push.Items, &pbcommon.Item{Id: int32(i), Count: int64(i)})
+	}
+	p, err := codecc2s.EncodePacket(c2sregistry.Registry, codecc2s.PtPush, 0, push)
+	require.NoError(t, err)
+	return p
+}
+
+func TestSplitReassemble(t *testing.T) {
+	p1, p2 := genPacket(t, 1000), genPacket(t, 2000)
+	fps1, err := Split(p1, 1, 1024)
+	require.NoError(t, err)
+	fps2, err := Split(p2, 2, 1024)
+	require.NoError(t, err)
+	require.Greater(t, len(fps1), 1)
+	for _, fp := range fps1 {
+		require.True(t, IsFragment(fp))
+		require.Equal(t, codecc2s.HeadGetPt(p1), codecc2s.HeadGetPt(fp))
+	}
+
+	// 不同消息的分片交错到达.
+	r := NewReassembler(0, 0)
+	var got [][]byte
+	for i := 0; i < max(len(fps1), len(fps2)); i++ {
+		for _, fps := range [][][]byte{fps1, fps2} {
+			if i >= len(fps) {
+				continue
+			}
+			p, err := r.Add(fps[i])
+			require.NoError(t, err)
+			if p != nil {
+				got = append(got, p)
+			}
+		}
+	}
+	require.Equal(t, [][]byte{p1, p2}, got)
+	require.False(t, IsFragment(p1))
+}
+
+func TestReassemblerLimits(t *testing.T) {
+	p := genPacket(t, 1000)
+	fps, err := Split(p, 1, 1024)
+	require.NoError(t, err)
+
+	// 超过长度上限.
+	r := NewReassembler(len(p)-1, 0)
+	for _, fp := range fps {
+		if _, err = r.Add(fp); err != nil {
+			break
+		}
+	}
+	require.ErrorIs(t, err, ErrMessageTooLarge)
+
+	// 同时重组中的消息过多.
+	r = NewReassembler(0, 2)
+	for id := uint32(1); id <= 2; id++ {
+		fps, err := Split(p, id, 1024)
+		require.NoError(t, err)
+		_, err = r.Add(fps[0])
+		require.NoError(t, err)
+	}
+	fps, err = Split(p, 3, 1024)
+	require.NoError(t, err)
+	_, err = r.Add(fps[0])
+	require.ErrorIs(t, err, ErrTooManyPartials)
+
+	// 分片乱序.
+	r = NewReassembler(0, 0)
+	_, err = r.Add(fps[1])
+	require.ErrorIs(t, err, ErrInvalidFragment)
+	_, err = r.Add(fps[0])
+	require.NoError(t, err)
+	_, err = r.Add(fps[2])
+	require.ErrorIs(t, err, ErrInvalidFragment)
+}
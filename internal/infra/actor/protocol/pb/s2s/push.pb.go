@@ -0,0 +1,126 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v7.34.0
+// source: s2s/push.proto
+
+package s2s
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 玩家推送通知, 由其他 Actor 投递给玩家 Actor, 玩家 Actor 转为 c2s 推送下发给客户端.
+type PlayerPushNtf struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           *anypb.Any             `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"` // c2s 推送消息.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerPushNtf) Reset() {
+	*x = PlayerPushNtf{}
+	mi := &file_s2s_push_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerPushNtf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerPushNtf) ProtoMessage() {}
+
+func (x *PlayerPushNtf) ProtoReflect() protoreflect.Message {
+	mi := &file_s2s_push_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerPushNtf.ProtoReflect.Descriptor instead.
+func (*PlayerPushNtf) Descriptor() ([]byte, []int) {
+	return file_s2s_push_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PlayerPushNtf) GetMsg() *anypb.Any {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+var File_s2s_push_proto protoreflect.FileDescriptor
+
+const file_s2s_push_proto_rawDesc = "" +
+	"\n" +
+	"\x0es2s/push.proto\x12\x03s2s\x1a\x19google/protobuf/any.proto\"7\n" +
+	"\rPlayerPushNtf\x12&\n" +
+	"\x03msg\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x03msgB;Z9github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2sb\x06proto3"
+
+var (
+	file_s2s_push_proto_rawDescOnce sync.Once
+	file_s2s_push_proto_rawDescData []byte
+)
+
+func file_s2s_push_proto_rawDescGZIP() []byte {
+	file_s2s_push_proto_rawDescOnce.Do(func() {
+		file_s2s_push_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_s2s_push_proto_rawDesc), len(file_s2s_push_proto_rawDesc)))
+	})
+	return file_s2s_push_proto_rawDescData
+}
+
+var file_s2s_push_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_s2s_push_proto_goTypes = []any{
+	(*PlayerPushNtf)(nil), // 0: s2s.PlayerPushNtf
+	(*anypb.Any)(nil),     // 1: google.protobuf.Any
+}
+var file_s2s_push_proto_depIdxs = []int32{
+	1, // 0: s2s.PlayerPushNtf.msg:type_name -> google.protobuf.Any
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_s2s_push_proto_init() }
+func file_s2s_push_proto_init() {
+	if File_s2s_push_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_s2s_push_proto_rawDesc), len(file_s2s_push_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_s2s_push_proto_goTypes,
+		DependencyIndexes: file_s2s_push_proto_depIdxs,
+		MessageInfos:      file_s2s_push_proto_msgTypes,
+	}.Build()
+	File_s2s_push_proto = out.File
+	file_s2s_push_proto_goTypes = nil
+	file_s2s_push_proto_depIdxs = nil
+}
@@ -0,0 +1,132 @@
+// Package record 提供 c2s 数据包流的录制格式.
+//
+// 录制文件为 JSON Lines, 每行一个 Entry, 记录明文数据包的方向、时间、包头与 protojson 格式的消息内容,
+// 便于直接阅读, 也可由客户端回放.
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	c2sregistry "github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Dir 数据包方向.
+type Dir string
+
+const (
+	DirC2S Dir = "c2s" // 客户端至服务端.
+	DirS2C Dir = "s2c" // 服务端至客户端.
+)
+
+// maxLineLen 录制文件单行最大长度.
+const maxLineLen = 8 * 1024 * 1024
+
+// Entry 录制条目.
+type Entry struct {
+	Time time.Time       `json:"time"`          // 录制时间.
+	Dir  Dir             `json:"dir"`           // 方向.
+	Pt   int8            `json:"pt"`            // 数据包类型.
+	Seq  uint32          `json:"seq"`           // 序号.
+	Name string          `json:"name"`          // 消息全名, 解码失败时为空.
+	Msg  json.RawMessage `json:"msg,omitempty"` // protojson 格式的消息内容.
+	Raw  []byte          `json:"raw,omitempty"` // 解码失败时的原始数据包.
+}
+
+// NewEntry 由明文数据包创建录制条目.
+func NewEntry(t time.Time, dir Dir, p []byte) *Entry {
+	e := &Entry{
+		Time: t,
+		Dir:  dir,
+		Pt:   codecc2s.HeadGetPt(p),
+		Seq:  codecc2s.HeadGetSeq(p),
+	}
+	msg, err := codecc2s.DecodeMessage(c2sregistry.Registry, p)
+	if err == nil {
+		e.Msg, err = protojson.Marshal(msg)
+	}
+	if err != nil {
+		e.Raw = append([]byte(nil), p...)
+		return e
+	}
+	e.Name = string(msg.ProtoReflect().Descriptor().FullName())
+	return e
+}
+
+// ShortName 返回不含包名的消息名.
+func (e *Entry) ShortName() string {
+	return string(protoreflect.FullName(e.Name).Name())
+}
+
+// Message 解析消息内容.
+func (e *Entry) Message() (proto.Message, error) {
+	if e.Name == "" {
+		return nil, pkgerrors.New("message not decoded")
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(e.Name))
+	if err != nil {
+		return nil, pkgerrors.WithMessagef(err, "find message %s", e.Name)
+	}
+	msg := mt.New().Interface()
+	if err := protojson.Unmarshal(e.Msg, msg); err != nil {
+		return nil, pkgerrors.WithMessagef(err, "unmarshal %s", e.Name)
+	}
+	return msg, nil
+}
+
+// Writer 录制写入器.
+type Writer struct {
+	enc *json.Encoder
+}
+
+// NewWriter 创建录制写入器.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{enc: json.NewEncoder(w)}
+}
+
+// Write 写入录制条目.
+func (w *Writer) Write(e *Entry) error {
+	return w.enc.Encode(e)
+}
+
+// Read 读取全部录制条目.
+func Read(r io.Reader) ([]*Entry, error) {
+	var entries []*Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLen)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		e := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return nil, pkgerrors.WithMessagef(err, "line %d", line)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ReadFile 读取录制文件.
+func ReadFile(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
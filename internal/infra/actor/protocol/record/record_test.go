@@ -0,0 +1,46 @@
+package record
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	c2sregistry "github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWriteRead(t *testing.T) {
+	req := &pbc2s.UseItemReq{ItemId: 1, Num: 2}
+	p, err := codecc2s.EncodePacket(c2sregistry.Registry, codecc2s.PtReq, 7, req)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	now := time.Now()
+	require.NoError(t, w.Write(NewEntry(now, DirC2S, p)))
+	require.NoError(t, w.Write(NewEntry(now, DirS2C, []byte{byte(codecc2s.PtPush), 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff})))
+
+	entries, err := Read(&buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	e := entries[0]
+	assert.Equal(t, DirC2S, e.Dir)
+	assert.Equal(t, codecc2s.PtReq, e.Pt)
+	assert.Equal(t, uint32(7), e.Seq)
+	assert.Equal(t, "UseItemReq", e.ShortName())
+	assert.True(t, now.Equal(e.Time))
+	msg, err := e.Message()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(req, msg))
+
+	// 无法解码的数据包保留原始内容.
+	assert.Empty(t, entries[1].Name)
+	assert.NotEmpty(t, entries[1].Raw)
+	_, err = entries[1].Message()
+	assert.Error(t, err)
+}
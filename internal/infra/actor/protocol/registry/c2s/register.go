@@ -4,13 +4,14 @@
 package c2s
 
 import (
-	"github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2s"
-	"github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
+	"github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	"github.com/godyy/ggs/pkg/protocol/pb/common"
 )
 
 func init() {
 	register((*common.Error)(nil))
 	register((*c2s.DisconnectPush)(nil))
+	register((*c2s.FragmentNtf)(nil))
 	register((*c2s.HeartbeatReq)(nil))
 	register((*c2s.HeartbeatResp)(nil))
 	register((*c2s.ItemPush)(nil))
@@ -20,6 +21,12 @@ func init() {
 	register((*c2s.LoginResp)(nil))
 	register((*c2s.ModifyNameReq)(nil))
 	register((*c2s.ModifyNameResp)(nil))
+	register((*c2s.PlayerBasePush)(nil))
+	register((*c2s.PlayerMigratePush)(nil))
+	register((*c2s.PlayerStopPush)(nil))
+	register((*c2s.ResumeReq)(nil))
+	register((*c2s.ResumeResp)(nil))
+	register((*c2s.SystemPush)(nil))
 	register((*c2s.UseItemReq)(nil))
 	register((*c2s.UseItemResp)(nil))
 }
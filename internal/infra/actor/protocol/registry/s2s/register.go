@@ -4,8 +4,8 @@
 package s2s
 
 import (
-	"github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
 	"github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
+	"github.com/godyy/ggs/pkg/protocol/pb/common"
 )
 
 func init() {
@@ -13,4 +13,5 @@ func init() {
 	register((*s2s.ActorSaveResultNtf)(nil))
 	register((*s2s.GetServerNameReq)(nil))
 	register((*s2s.GetServerNameResp)(nil))
+	register((*s2s.PlayerPushNtf)(nil))
 }
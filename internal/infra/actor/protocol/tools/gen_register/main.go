@@ -15,11 +15,16 @@ import (
 	pkgerrors "github.com/pkg/errors"
 )
 
-// pbPkgPathRoot 导出Pb文件库的根路径.
-const pbPkgPathRoot = "github.com/godyy/ggs/internal/infra/actor/protocol/pb/"
+const (
+	// pbC2SPkgPath 导出c2s Pb的路径, 客户端协议对外公开.
+	pbC2SPkgPath = "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+
+	// pbS2SPkgPath 导出s2s Pb的路径.
+	pbS2SPkgPath = "github.com/godyy/ggs/internal/infra/actor/protocol/pb/s2s"
 
-// pbCommonPath 导出common Pb的路径.
-const pbCommonPath = "common"
+	// pbCommonPkgPath 导出common Pb的路径.
+	pbCommonPkgPath = "github.com/godyy/ggs/pkg/protocol/pb/common"
+)
 
 var registrableMessageSuffixes = []string{"Req", "Resp", "Push", "Ntf"}
 
@@ -118,7 +123,7 @@ func parseRegistrableMessageNamesInDir(protoDir string) ([]string, error) {
 	return names, nil
 }
 
-func generateRegisterFile(registryPkg string, pbPkgPath string, msgNames []string, commonMsgNames []string) ([]byte, error) {
+func generateRegisterFile(registryPkg string, pkgImport string, msgNames []string, commonMsgNames []string) ([]byte, error) {
 	var buf bytes.Buffer
 	// Add do-not-edit banner
 	buf.WriteString("// Code generated by proto/tools/gen_register; DO NOT EDIT.\n")
@@ -126,14 +131,12 @@ func generateRegisterFile(registryPkg string, pbPkgPath string, msgNames []strin
 	buf.WriteString(fmt.Sprintf("package %s\n\n", registryPkg))
 
 	needCommon := len(commonMsgNames) > 0
-	// compose full import path from root + relative pkgPath
-	pkgImport := path.Join(pbPkgPathRoot, pbPkgPath)
-	// derive package name prefix from basename of pbPkgPath
-	pkgName := path.Base(pbPkgPath)
+	// derive package name prefix from basename of pkgImport
+	pkgName := path.Base(pkgImport)
 	if needCommon {
 		buf.WriteString("import (\n")
 		buf.WriteString(fmt.Sprintf("\t\"%s\"\n", pkgImport))
-		buf.WriteString(fmt.Sprintf("\t\"%s\"\n", path.Join(pbPkgPathRoot, pbCommonPath)))
+		buf.WriteString(fmt.Sprintf("\t\"%s\"\n", pbCommonPkgPath))
 		buf.WriteString(")\n\n")
 	} else {
 		buf.WriteString(fmt.Sprintf("import \"%s\"\n\n", pkgImport))
@@ -181,7 +184,7 @@ func main() {
 	// Generate files
 	c2sCode, err := generateRegisterFile(
 		"c2s",
-		"c2s",
+		pbC2SPkgPath,
 		c2sMsgNames,
 		commonMessagesFor(commonTargetC2S),
 	)
@@ -190,7 +193,7 @@ func main() {
 	}
 	s2sCode, err := generateRegisterFile(
 		"s2s",
-		"s2s",
+		pbS2SPkgPath,
 		s2sMsgNames,
 		commonMessagesFor(commonTargetS2S),
 	)
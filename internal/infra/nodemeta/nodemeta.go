@@ -0,0 +1,95 @@
+// Package nodemeta 在 etcd 中发布与监听节点元数据, 例如排空状态、负载等.
+//
+// 元数据独立于集群节点注册, 存放在 <EtcdRoot>/meta/<category>/<name>,
+// 与发布者的租约绑定, 节点下线后自动删除.
+package nodemeta
+
+import (
+	"encoding/json"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/godyy/ggskit/infra/cluster"
+	pkgerrors "github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	defaultTTL         = 10
+	defaultDialTimeout = 5 * time.Second
+	opTimeout          = 5 * time.Second
+	retryInterval      = time.Second
+)
+
+// Meta 节点元数据.
+type Meta struct {
+	Category  string  `json:"category"`             // 节点类别.
+	Name      string  `json:"name"`                 // 节点名.
+	ServerId  int64   `json:"server_id"`            // 服务器ID.
+	ServerIds []int64 `json:"server_ids,omitempty"` // 节点托管的全部服务器ID, 为空表示仅托管 ServerId.
+	Addr      string  `json:"addr"`                 // 对外服务地址, 例如网关对客户端的监听地址.
+	Load      int64   `json:"load"`                 // 负载, 例如会话数量、活跃玩家数量.
+	CPU       float64 `json:"cpu"`                  // CPU 使用率估算值(0~1).
+	Draining  bool    `json:"draining"`             // 是否排空中, 排空中的节点不再分配新的负载.
+	UpdateAt  int64   `json:"update_at"`            // 更新时间(Unix 秒).
+}
+
+// NodeId 返回节点ID.
+func (m *Meta) NodeId() string {
+	return cluster.MakeNodeID(m.Category, m.Name)
+}
+
+// HostsServer 返回节点是否托管 serverId 指定的服务器.
+func (m *Meta) HostsServer(serverId int64) bool {
+	if len(m.ServerIds) == 0 {
+		return m.ServerId == serverId
+	}
+	return slices.Contains(m.ServerIds, serverId)
+}
+
+// Config 配置.
+type Config struct {
+	Endpoints []string // etcd 地址.
+	Root      string   // 根路径, 与集群配置的 EtcdRoot 一致.
+	TTL       int64    // 租约时长(秒), 默认 10.
+}
+
+// ConfigFromCluster 根据集群配置创建 Config.
+func ConfigFromCluster(cfg *cluster.Config) *Config {
+	return &Config{
+		Endpoints: cfg.EtcdEndPoints,
+		Root:      cfg.EtcdRoot,
+	}
+}
+
+// newClient 创建 etcd 客户端.
+func newClient(cfg *Config) (*clientv3.Client, error) {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: defaultDialTimeout,
+	})
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "create etcd client")
+	}
+	return cli, nil
+}
+
+// categoryPrefix 返回类别下元数据的路径前缀.
+func categoryPrefix(root, category string) string {
+	return path.Join(root, "meta", category) + "/"
+}
+
+// metaKey 返回节点元数据的路径.
+func metaKey(root, category, name string) string {
+	return categoryPrefix(root, category) + name
+}
+
+// decodeMeta 解码元数据.
+func decodeMeta(b []byte) (*Meta, error) {
+	m := &Meta{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,185 @@
+package nodemeta
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/logger"
+	pkgerrors "github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Publisher 发布自身节点的元数据, 租约丢失后自动重新发布.
+type Publisher struct {
+	cli *clientv3.Client
+	key string
+	ttl int64
+
+	mtx     sync.Mutex
+	meta    Meta             // 当前元数据.
+	leaseId clientv3.LeaseID // 当前租约, 租约丢失后至重新申请成功前为 0.
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPublisher 创建 Publisher.
+func NewPublisher(cfg *Config, meta Meta) (*Publisher, error) {
+	cli, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Publisher{
+		cli:    cli,
+		key:    metaKey(cfg.Root, meta.Category, meta.Name),
+		ttl:    ttl,
+		meta:   meta,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Start 发布元数据并维持租约.
+func (p *Publisher) Start() error {
+	ch, err := p.grant()
+	if err != nil {
+		p.cli.Close()
+		return err
+	}
+	go p.keepAliveLoop(ch)
+	return nil
+}
+
+// Stop 撤销租约并删除元数据.
+func (p *Publisher) Stop() {
+	p.cancel()
+	<-p.done
+
+	p.mtx.Lock()
+	leaseId := p.leaseId
+	p.mtx.Unlock()
+	if leaseId == clientv3.NoLease {
+		p.cli.Close()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	if _, err := p.cli.Revoke(ctx, leaseId); err != nil {
+		logger.Get().Errorf("revoke node meta lease failed, key=%s, %v", p.key, err)
+	}
+	p.cli.Close()
+}
+
+// Meta 返回当前元数据.
+func (p *Publisher) Meta() Meta {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.meta
+}
+
+// Update 修改并重新发布元数据. 租约丢失期间仅修改元数据, 重新申请租约后随之发布;
+// 发布失败时修改同样保留, 在下次发布时生效.
+func (p *Publisher) Update(f func(m *Meta)) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	f(&p.meta)
+	if p.leaseId == clientv3.NoLease {
+		return nil
+	}
+	return p.put(p.leaseId)
+}
+
+// grant 申请租约并发布元数据.
+func (p *Publisher) grant() (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	ctx, cancel := context.WithTimeout(p.ctx, opTimeout)
+	defer cancel()
+
+	lease, err := p.cli.Grant(ctx, p.ttl)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "grant lease")
+	}
+
+	// 发布成功后才启用新租约, 期间的修改由本次发布一并写入.
+	p.mtx.Lock()
+	err = p.put(lease.ID)
+	if err == nil {
+		p.leaseId = lease.ID
+	}
+	p.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := p.cli.KeepAlive(p.ctx, lease.ID)
+	if err != nil {
+		p.mtx.Lock()
+		p.leaseId = clientv3.NoLease
+		p.mtx.Unlock()
+		return nil, pkgerrors.WithMessage(err, "keep lease alive")
+	}
+	return ch, nil
+}
+
+// put 写入元数据, 调用方需持有 mtx.
+func (p *Publisher) put(leaseId clientv3.LeaseID) error {
+	p.meta.UpdateAt = time.Now().Unix()
+	b, err := json.Marshal(&p.meta)
+	if err != nil {
+		return pkgerrors.WithMessage(err, "marshal meta")
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, opTimeout)
+	defer cancel()
+	if _, err := p.cli.Put(ctx, p.key, string(b), clientv3.WithLease(leaseId)); err != nil {
+		return pkgerrors.WithMessagef(err, "put %s", p.key)
+	}
+	return nil
+}
+
+// keepAliveLoop 维持租约, 租约丢失时重新申请.
+func (p *Publisher) keepAliveLoop(ch <-chan *clientv3.LeaseKeepAliveResponse) {
+	defer close(p.done)
+
+	for {
+		for range ch {
+		}
+
+		if p.ctx.Err() != nil {
+			// 已停止, 由 Stop 撤销租约.
+			return
+		}
+
+		// 租约丢失, 此后的修改等待重新申请租约后发布.
+		p.mtx.Lock()
+		p.leaseId = clientv3.NoLease
+		p.mtx.Unlock()
+
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+
+			var err error
+			if ch, err = p.grant(); err == nil {
+				logger.Get().Infof("node meta republished, key=%s", p.key)
+				break
+			}
+			logger.Get().Errorf("republish node meta failed, key=%s, %v", p.key, err)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package nodemeta
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/logger"
+	pkgerrors "github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Watcher 监听某一类别节点的元数据, 维护其最新快照.
+type Watcher struct {
+	cli    *clientv3.Client
+	prefix string
+
+	mtx   sync.RWMutex
+	metas map[string]Meta // 节点名 -> 元数据.
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWatcher 创建 Watcher.
+func NewWatcher(cfg *Config, category string) (*Watcher, error) {
+	cli, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Watcher{
+		cli:    cli,
+		prefix: categoryPrefix(cfg.Root, category),
+		metas:  make(map[string]Meta),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Start 加载当前元数据并开始监听变更.
+func (w *Watcher) Start() error {
+	rev, err := w.load()
+	if err != nil {
+		w.cli.Close()
+		return err
+	}
+	go w.watchLoop(rev)
+	return nil
+}
+
+// Stop 停止监听.
+func (w *Watcher) Stop() {
+	w.cancel()
+	<-w.done
+	w.cli.Close()
+}
+
+// Get 返回指定节点的元数据.
+func (w *Watcher) Get(name string) (Meta, bool) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	m, ok := w.metas[name]
+	return m, ok
+}
+
+// Range 遍历所有节点的元数据, f 返回 false 时停止遍历.
+func (w *Watcher) Range(f func(m *Meta) bool) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	for _, m := range w.metas {
+		if !f(&m) {
+			return
+		}
+	}
+}
+
+// load 全量加载元数据, 返回加载时的版本.
+func (w *Watcher) load() (int64, error) {
+	ctx, cancel := context.WithTimeout(w.ctx, opTimeout)
+	defer cancel()
+
+	resp, err := w.cli.Get(ctx, w.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, pkgerrors.WithMessagef(err, "get %s", w.prefix)
+	}
+
+	metas := make(map[string]Meta, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		m, err := decodeMeta(kv.Value)
+		if err != nil {
+			logger.Get().Errorf("decode node meta failed, key=%s, %v", kv.Key, err)
+			continue
+		}
+		metas[string(kv.Key[len(w.prefix):])] = *m
+	}
+
+	w.mtx.Lock()
+	w.metas = metas
+	w.mtx.Unlock()
+	return resp.Header.Revision, nil
+}
+
+// watchLoop 监听变更, 监听中断时重新全量加载.
+func (w *Watcher) watchLoop(rev int64) {
+	defer close(w.done)
+
+	for {
+		ctx, cancel := context.WithCancel(w.ctx)
+		for resp := range w.cli.Watch(ctx, w.prefix, clientv3.WithPrefix(), clientv3.WithRev(rev+1)) {
+			if err := resp.Err(); err != nil {
+				logger.Get().Errorf("watch node meta failed, prefix=%s, %v", w.prefix, err)
+				break
+			}
+			w.apply(resp.Events)
+		}
+		cancel()
+
+		// 监听中断或已停止.
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+
+			var err error
+			if rev, err = w.load(); err == nil {
+				break
+			}
+			logger.Get().Errorf("reload node meta failed, prefix=%s, %v", w.prefix, err)
+		}
+	}
+}
+
+// apply 应用变更事件.
+func (w *Watcher) apply(events []*clientv3.Event) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for _, ev := range events {
+		name := string(ev.Kv.Key[len(w.prefix):])
+		switch ev.Type {
+		case clientv3.EventTypePut:
+			m, err := decodeMeta(ev.Kv.Value)
+			if err != nil {
+				logger.Get().Errorf("decode node meta failed, key=%s, %v", ev.Kv.Key, err)
+				continue
+			}
+			w.metas[name] = *m
+		case clientv3.EventTypeDelete:
+			delete(w.metas, name)
+		}
+	}
+}
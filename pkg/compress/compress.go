@@ -0,0 +1,291 @@
+// Package compress 提供 c2s 数据包的按需压缩.
+//
+// 协商压缩后, 每个数据包在加密前的明文首部附加 1 字节标志, 标志 FlagCompressed
+// 置位时其后的负载为使用协商算法压缩后的数据.
+//
+// 压缩算法在密钥交换时协商: 客户端在临时密钥后附加 [HandshakeMagic, Algorithms],
+// 服务端在会话密钥后附加选定的 Algorithm. 未附加扩展的一方按不压缩处理.
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Algorithm 压缩算法.
+type Algorithm uint8
+
+const (
+	None  Algorithm = 0 // 不压缩.
+	Flate Algorithm = 1 // flate.
+	Zstd  Algorithm = 2 // zstd.
+)
+
+var algorithmNames = map[Algorithm]string{
+	None:  "none",
+	Flate: "flate",
+	Zstd:  "zstd",
+}
+
+func (a Algorithm) String() string {
+	if name, ok := algorithmNames[a]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Parse 根据名称解析压缩算法.
+func Parse(name string) (Algorithm, error) {
+	for a, n := range algorithmNames {
+		if n == name {
+			return a, nil
+		}
+	}
+	return None, pkgerrors.Errorf("unknown compression algorithm %s", name)
+}
+
+// Algorithms 压缩算法集合.
+type Algorithms uint8
+
+// Supported 本端支持的全部压缩算法.
+const Supported = Algorithms(1<<Flate | 1<<Zstd)
+
+// Has 返回集合中是否包含算法 a.
+func (s Algorithms) Has(a Algorithm) bool {
+	return a != None && s&(1<<a) != 0
+}
+
+const (
+	// HandshakeMagic 密钥交换扩展标识.
+	HandshakeMagic = 0xC0
+
+	// FlagCompressed 数据包已压缩标志.
+	FlagCompressed = 0x01
+
+	// DefaultThreshold 默认压缩阈值, 长度不小于该值的数据包才进行压缩.
+	DefaultThreshold = 1024
+)
+
+// ErrDecodedTooLarge 解压后数据超过长度限制.
+var ErrDecodedTooLarge = pkgerrors.New("decoded packet too large")
+
+// ErrEmptyPacket 数据包缺少标志字节.
+var ErrEmptyPacket = pkgerrors.New("empty packet")
+
+// Negotiate 按 preferred 的顺序选择 peer 支持的第一个算法, 均不支持时返回 None.
+func Negotiate(preferred []Algorithm, peer Algorithms) Algorithm {
+	for _, a := range preferred {
+		if peer.Has(a) && Supported.Has(a) {
+			return a
+		}
+	}
+	return None
+}
+
+// AppendOffer 在客户端临时密钥后附加本端支持的算法集合.
+func AppendOffer(key []byte, algos Algorithms) []byte {
+	return append(append(make([]byte, 0, len(key)+2), key...), HandshakeMagic, byte(algos))
+}
+
+// ParseOffer 解析客户端发送的临时密钥, keyLen 为密钥长度.
+// 返回密钥及客户端支持的算法集合, ok 表示客户端是否附加了扩展.
+func ParseOffer(b []byte, keyLen int) (key []byte, algos Algorithms, ok bool) {
+	if len(b) == keyLen+2 && b[keyLen] == HandshakeMagic {
+		return b[:keyLen], Algorithms(b[keyLen+1]), true
+	}
+	return b, 0, false
+}
+
+// AppendSelection 在会话密钥后附加服务端选定的算法.
+func AppendSelection(key []byte, algo Algorithm) []byte {
+	return append(append(make([]byte, 0, len(key)+1), key...), byte(algo))
+}
+
+// ParseSelection 解析服务端发送的会话密钥, keyLen 为密钥长度.
+// 服务端未附加扩展时返回 None.
+func ParseSelection(b []byte, keyLen int) ([]byte, Algorithm, error) {
+	if len(b) != keyLen+1 {
+		return b, None, nil
+	}
+	algo := Algorithm(b[keyLen])
+	if algo != None && !Supported.Has(algo) {
+		return nil, None, pkgerrors.Errorf("unsupported algorithm %d", algo)
+	}
+	return b[:keyLen], algo, nil
+}
+
+// Codec 数据包压缩编解码器, 并发安全.
+type Codec struct {
+	algo       Algorithm // 压缩算法.
+	threshold  int       // 压缩阈值.
+	maxDecoded int       // 解压后最大长度.
+}
+
+// NewCodec 创建编解码器, algo 为 None 时返回 nil.
+func NewCodec(algo Algorithm, threshold, maxDecoded int) *Codec {
+	if algo == None {
+		return nil
+	}
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Codec{
+		algo:       algo,
+		threshold:  threshold,
+		maxDecoded: maxDecoded,
+	}
+}
+
+// Algorithm 返回压缩算法.
+func (c *Codec) Algorithm() Algorithm {
+	return c.algo
+}
+
+// Encode 编码数据包, 长度达到阈值且压缩有收益时进行压缩.
+func (c *Codec) Encode(p []byte) ([]byte, error) {
+	if len(p) >= c.threshold {
+		compressed, err := c.compress(p)
+		if err != nil {
+			return nil, pkgerrors.WithMessagef(err, "compress with %s", c.algo)
+		}
+		if len(compressed) < len(p) {
+			return compressed, nil
+		}
+	}
+
+	b := make([]byte, 1+len(p))
+	copy(b[1:], p)
+	return b, nil
+}
+
+// Decode 解码数据包.
+func (c *Codec) Decode(p []byte) ([]byte, error) {
+	if len(p) == 0 {
+		return nil, ErrEmptyPacket
+	}
+	if p[0]&FlagCompressed == 0 {
+		return p[1:], nil
+	}
+
+	decoded, err := c.decompress(p[1:])
+	if err != nil {
+		return nil, pkgerrors.WithMessagef(err, "decompress with %s", c.algo)
+	}
+	return decoded, nil
+}
+
+// compress 压缩, 返回附加了标志字节的数据.
+func (c *Codec) compress(p []byte) ([]byte, error) {
+	switch c.algo {
+	case Flate:
+		buf := bytes.NewBuffer(make([]byte, 0, len(p)/2+1))
+		buf.WriteByte(FlagCompressed)
+		w := flateWriterPool.Get().(*flate.Writer)
+		defer flateWriterPool.Put(w)
+		w.Reset(buf)
+		if _, err := w.Write(p); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+
+	case Zstd:
+		return getZstdEncoder().EncodeAll(p, []byte{FlagCompressed}), nil
+
+	default:
+		return nil, pkgerrors.Errorf("unsupported algorithm %d", c.algo)
+	}
+}
+
+// decompress 解压.
+func (c *Codec) decompress(p []byte) ([]byte, error) {
+	var (
+		decoded []byte
+		err     error
+	)
+	switch c.algo {
+	case Flate:
+		r := flate.NewReader(bytes.NewReader(p))
+		defer r.Close()
+		decoded, err = io.ReadAll(io.LimitReader(r, int64(c.maxDecoded)+1))
+
+	case Zstd:
+		decoded, err = c.decompressZstd(p)
+
+	default:
+		return nil, pkgerrors.Errorf("unsupported algorithm %d", c.algo)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) > c.maxDecoded {
+		return nil, ErrDecodedTooLarge
+	}
+	return decoded, nil
+}
+
+var flateWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := flate.NewWriter(nil, flate.DefaultCompression)
+		return w
+	},
+}
+
+const (
+	// zstdDecoderMaxMemory zstd 解码内存上限, 防止恶意数据耗尽内存.
+	zstdDecoderMaxMemory = 64 << 20
+
+	// zstdDecoderMaxWindow zstd 解码窗口上限, 数据包不超过数 MB, 无需更大的窗口.
+	zstdDecoderMaxWindow = 8 << 20
+)
+
+var (
+	zstdEncoder     *zstd.Encoder
+	zstdEncoderOnce sync.Once
+)
+
+// getZstdEncoder 获取共享的 zstd 编码器, EncodeAll 并发安全.
+func getZstdEncoder() *zstd.Encoder {
+	zstdEncoderOnce.Do(func() {
+		zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+	})
+	return zstdEncoder
+}
+
+var zstdDecoderPool = sync.Pool{
+	New: func() any {
+		// 单协程流式解码, 不启动后台协程.
+		d, _ := zstd.NewReader(nil,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderLowmem(true),
+			zstd.WithDecoderMaxMemory(zstdDecoderMaxMemory),
+			zstd.WithDecoderMaxWindow(zstdDecoderMaxWindow))
+		return d
+	},
+}
+
+// decompressZstd 流式解压 zstd 数据, 解压长度超过 maxDecoded 时立即停止.
+// 帧头声明的原始长度超限时直接拒绝, 无需解压.
+func (c *Codec) decompressZstd(p []byte) ([]byte, error) {
+	var h zstd.Header
+	if err := h.Decode(p); err != nil {
+		return nil, err
+	}
+	if h.HasFCS && h.FrameContentSize > uint64(c.maxDecoded) {
+		return nil, ErrDecodedTooLarge
+	}
+
+	d := zstdDecoderPool.Get().(*zstd.Decoder)
+	defer zstdDecoderPool.Put(d)
+	if err := d.Reset(bytes.NewReader(p)); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(io.LimitReader(d, int64(c.maxDecoded)+1))
+}
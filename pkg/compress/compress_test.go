@@ -0,0 +1,202 @@
+package compress
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcommon "github.com/godyy/ggs/pkg/protocol/pb/common"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const testMaxDecoded = 128 * 1024
+
+// genItemPushPacket 生成包含 n 个道具变更的 ItemPush 消息体.
+// 道具ID集中在配置表的常见区间, 数量分布较广, 与线上背包同步的数据特征接近.
+func genItemPushPacket(tb testing.TB, n int) []byte {
+	r := rand.New(rand.NewSource(int64(n)))
+	push := &pbc2s.ItemPush{Items: make([]*pbcommon.Item, 0, n)}
+	for i := 0; i < n; i++ {
+		push.Items = append(push.Items, &pbcommon.Item{
+			Id:    int32(100000 + r.Intn(2000)),
+			Count: int64(r.Intn(1000000)),
+		})
+	}
+	p, err := proto.Marshal(push)
+	require.NoError(tb, err)
+	return p
+}
+
+// mailTemplates 邮件正文模板, 线上邮件多由少量模板填充变量生成.
+var mailTemplates = []string{
+	"亲爱的%s: 恭喜您在第%d期竞技场赛季中获得第%d名, 以下是您的赛季奖励, 请及时领取. 邮件将在%d天后过期.",
+	"亲爱的%s: 由于服务器于%d月%d日进行了停机维护, 为表歉意, 特为所有玩家发放补偿, 感谢您的理解与支持. 补偿将在%d天后过期.",
+	"亲爱的%s: 您的好友赠送了体力, 当前共%d点, 今日还可领取%d次. 快去回赠好友吧! 本邮件%d天后过期.",
+	"System notice for %s: season %d has ended and your final rank is %d. Rewards are attached and expire in %d days.",
+}
+
+// mailNames 邮件正文中的玩家昵称.
+var mailNames = []string{"风之旅人", "夜雨听风", "Knight_Arthur", "银月", "小橘子", "ShadowHunter"}
+
+// genMailPushPacket 生成包含 n 封邮件的邮件推送消息体, 以文本为主, 附带少量附件.
+// 字段布局为 repeated Mail{id=1, title=2, content=3, sender=4, sendTime=5, attachments=6},
+// 附件与 common.Item 一致.
+func genMailPushPacket(tb testing.TB, n int) []byte {
+	r := rand.New(rand.NewSource(int64(n)))
+	var p []byte
+	for i := 0; i < n; i++ {
+		var mail []byte
+		mail = protowire.AppendTag(mail, 1, protowire.VarintType)
+		mail = protowire.AppendVarint(mail, uint64(1000000+r.Intn(1000000)))
+		mail = protowire.AppendTag(mail, 2, protowire.BytesType)
+		mail = protowire.AppendString(mail, fmt.Sprintf("第%d期活动奖励", r.Intn(100)))
+		mail = protowire.AppendTag(mail, 3, protowire.BytesType)
+		mail = protowire.AppendString(mail, fmt.Sprintf(mailTemplates[r.Intn(len(mailTemplates))],
+			mailNames[r.Intn(len(mailNames))], r.Intn(100), r.Intn(1000), 7+r.Intn(24)))
+		mail = protowire.AppendTag(mail, 4, protowire.BytesType)
+		mail = protowire.AppendString(mail, "系统")
+		mail = protowire.AppendTag(mail, 5, protowire.VarintType)
+		mail = protowire.AppendVarint(mail, uint64(1700000000+r.Intn(10000000)))
+		for j := r.Intn(4); j > 0; j-- {
+			item, err := proto.Marshal(&pbcommon.Item{
+				Id:    int32(100000 + r.Intn(2000)),
+				Count: int64(r.Intn(1000)),
+			})
+			require.NoError(tb, err)
+			mail = protowire.AppendTag(mail, 6, protowire.BytesType)
+			mail = protowire.AppendBytes(mail, item)
+		}
+		p = protowire.AppendTag(p, 1, protowire.BytesType)
+		p = protowire.AppendBytes(p, mail)
+	}
+	return p
+}
+
+func TestCodec(t *testing.T) {
+	for _, algo := range []Algorithm{Flate, Zstd} {
+		t.Run(algo.String(), func(t *testing.T) {
+			codec := NewCodec(algo, DefaultThreshold, testMaxDecoded)
+
+			// 小于阈值的数据包不压缩.
+			small := genItemPushPacket(t, 2)
+			encoded, err := codec.Encode(small)
+			require.NoError(t, err)
+			require.Equal(t, byte(0), encoded[0])
+			decoded, err := codec.Decode(encoded)
+			require.NoError(t, err)
+			require.Equal(t, small, decoded)
+
+			// 大数据包压缩.
+			large := genItemPushPacket(t, 1000)
+			encoded, err = codec.Encode(large)
+			require.NoError(t, err)
+			require.Equal(t, byte(FlagCompressed), encoded[0])
+			require.Less(t, len(encoded), len(large))
+			decoded, err = codec.Decode(encoded)
+			require.NoError(t, err)
+			require.Equal(t, large, decoded)
+
+			// 解压后超过长度限制.
+			limited := NewCodec(algo, DefaultThreshold, len(large)-1)
+			_, err = limited.Decode(encoded)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCodec_ZstdBomb(t *testing.T) {
+	codec := NewCodec(Zstd, DefaultThreshold, testMaxDecoded)
+	zeros := make([]byte, 32<<20)
+
+	// 帧头声明了原始长度, 无需解压直接拒绝.
+	encoded := getZstdEncoder().EncodeAll(zeros, []byte{FlagCompressed})
+	_, err := codec.Decode(encoded)
+	require.ErrorIs(t, err, ErrDecodedTooLarge)
+
+	// 流式压缩的帧头不含原始长度, 解压超过长度限制时停止.
+	buf := bytes.NewBuffer([]byte{FlagCompressed})
+	w, err := zstd.NewWriter(buf)
+	require.NoError(t, err)
+	_, err = w.Write(zeros)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	_, err = codec.Decode(buf.Bytes())
+	require.ErrorIs(t, err, ErrDecodedTooLarge)
+}
+
+func TestHandshake(t *testing.T) {
+	key := make([]byte, 16)
+
+	// 客户端附加扩展.
+	parsed, algos, ok := ParseOffer(AppendOffer(key, Supported), len(key))
+	require.True(t, ok)
+	require.Equal(t, key, parsed)
+	require.Equal(t, Zstd, Negotiate([]Algorithm{Zstd, Flate}, algos))
+	require.Equal(t, None, Negotiate(nil, algos))
+
+	// 旧版本客户端.
+	parsed, _, ok = ParseOffer(key, len(key))
+	require.False(t, ok)
+	require.Equal(t, key, parsed)
+
+	// 服务端选择.
+	parsed, algo, err := ParseSelection(AppendSelection(key, Flate), len(key))
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+	require.Equal(t, Flate, algo)
+
+	parsed, algo, err = ParseSelection(key, len(key))
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+	require.Equal(t, None, algo)
+
+	_, _, err = ParseSelection(AppendSelection(key, Algorithm(7)), len(key))
+	require.Error(t, err)
+}
+
+func BenchmarkCodec(b *testing.B) {
+	type payload struct {
+		name string
+		p    []byte
+	}
+	var payloads []payload
+	for _, n := range []int{20, 200, 2000} {
+		payloads = append(payloads, payload{fmt.Sprintf("ItemPush-%d", n), genItemPushPacket(b, n)})
+	}
+	for _, n := range []int{10, 50, 200} {
+		payloads = append(payloads, payload{fmt.Sprintf("MailPush-%d", n), genMailPushPacket(b, n)})
+	}
+
+	for _, pl := range payloads {
+		p := pl.p
+		for _, algo := range []Algorithm{Flate, Zstd} {
+			codec := NewCodec(algo, DefaultThreshold, testMaxDecoded)
+			encoded, err := codec.Encode(p)
+			require.NoError(b, err)
+
+			b.Run(fmt.Sprintf("%s/%s/encode", pl.name, algo), func(b *testing.B) {
+				b.SetBytes(int64(len(p)))
+				b.ReportMetric(float64(len(encoded))/float64(len(p)), "ratio")
+				for i := 0; i < b.N; i++ {
+					if _, err := codec.Encode(p); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+
+			b.Run(fmt.Sprintf("%s/%s/decode", pl.name, algo), func(b *testing.B) {
+				b.SetBytes(int64(len(p)))
+				for i := 0; i < b.N; i++ {
+					if _, err := codec.Decode(encoded); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
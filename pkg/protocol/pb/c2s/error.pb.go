@@ -34,6 +34,7 @@ const (
 	ErrCode_ECInvalidPacket ErrCode = 2 // 无效的数据包.
 	ErrCode_ECInvalidToken  ErrCode = 3 // 无效的令牌.
 	ErrCode_ECLoginTimeout  ErrCode = 4 // 登陆超时.
+	ErrCode_ECResumeFailed  ErrCode = 5 // 会话恢复失败.
 	// 道具系统 [1000, 1999]
 	ErrCode_ECItemNotEnough ErrCode = 1000 // 道具数量不足.
 )
@@ -46,6 +47,7 @@ var (
 		2:    "ECInvalidPacket",
 		3:    "ECInvalidToken",
 		4:    "ECLoginTimeout",
+		5:    "ECResumeFailed",
 		1000: "ECItemNotEnough",
 	}
 	ErrCode_value = map[string]int32{
@@ -54,6 +56,7 @@ var (
 		"ECInvalidPacket": 2,
 		"ECInvalidToken":  3,
 		"ECLoginTimeout":  4,
+		"ECResumeFailed":  5,
 		"ECItemNotEnough": 1000,
 	}
 )
@@ -89,14 +92,15 @@ var File_c2s_error_proto protoreflect.FileDescriptor
 
 const file_c2s_error_proto_rawDesc = "" +
 	"\n" +
-	"\x0fc2s/error.proto\x12\x03c2s*\x80\x01\n" +
+	"\x0fc2s/error.proto\x12\x03c2s*\x94\x01\n" +
 	"\aErrCode\x12\r\n" +
 	"\tECSuccess\x10\x00\x12\x13\n" +
 	"\x0fECInternalError\x10\x01\x12\x13\n" +
 	"\x0fECInvalidPacket\x10\x02\x12\x12\n" +
 	"\x0eECInvalidToken\x10\x03\x12\x12\n" +
-	"\x0eECLoginTimeout\x10\x04\x12\x14\n" +
-	"\x0fECItemNotEnough\x10\xe8\aB;Z9github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2sb\x06proto3"
+	"\x0eECLoginTimeout\x10\x04\x12\x12\n" +
+	"\x0eECResumeFailed\x10\x05\x12\x14\n" +
+	"\x0fECItemNotEnough\x10\xe8\aB*Z(github.com/godyy/ggs/pkg/protocol/pb/c2sb\x06proto3"
 
 var (
 	file_c2s_error_proto_rawDescOnce sync.Once
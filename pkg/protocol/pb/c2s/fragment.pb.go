@@ -0,0 +1,152 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v7.34.0
+// source: c2s/fragment.proto
+
+package c2s
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 消息分片.
+// 超过单个数据包长度上限的数据包被拆分为多个分片依次发送, 接收方按序重组.
+// 分片数据包的 pt、seq 与原数据包相同.
+type FragmentNtf struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`       // 消息编号, 同一连接同一方向内唯一.
+	Index         uint32                 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"` // 分片序号, 从 0 开始.
+	Total         uint32                 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"` // 分片总数.
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`    // 原数据包片段.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FragmentNtf) Reset() {
+	*x = FragmentNtf{}
+	mi := &file_c2s_fragment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FragmentNtf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FragmentNtf) ProtoMessage() {}
+
+func (x *FragmentNtf) ProtoReflect() protoreflect.Message {
+	mi := &file_c2s_fragment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FragmentNtf.ProtoReflect.Descriptor instead.
+func (*FragmentNtf) Descriptor() ([]byte, []int) {
+	return file_c2s_fragment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FragmentNtf) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FragmentNtf) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *FragmentNtf) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *FragmentNtf) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_c2s_fragment_proto protoreflect.FileDescriptor
+
+const file_c2s_fragment_proto_rawDesc = "" +
+	"\n" +
+	"\x12c2s/fragment.proto\x12\x03c2s\"]\n" +
+	"\vFragmentNtf\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\rR\x05index\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\rR\x05total\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04dataB*Z(github.com/godyy/ggs/pkg/protocol/pb/c2sb\x06proto3"
+
+var (
+	file_c2s_fragment_proto_rawDescOnce sync.Once
+	file_c2s_fragment_proto_rawDescData []byte
+)
+
+func file_c2s_fragment_proto_rawDescGZIP() []byte {
+	file_c2s_fragment_proto_rawDescOnce.Do(func() {
+		file_c2s_fragment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_c2s_fragment_proto_rawDesc), len(file_c2s_fragment_proto_rawDesc)))
+	})
+	return file_c2s_fragment_proto_rawDescData
+}
+
+var file_c2s_fragment_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_c2s_fragment_proto_goTypes = []any{
+	(*FragmentNtf)(nil), // 0: c2s.FragmentNtf
+}
+var file_c2s_fragment_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_c2s_fragment_proto_init() }
+func file_c2s_fragment_proto_init() {
+	if File_c2s_fragment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_c2s_fragment_proto_rawDesc), len(file_c2s_fragment_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_c2s_fragment_proto_goTypes,
+		DependencyIndexes: file_c2s_fragment_proto_depIdxs,
+		MessageInfos:      file_c2s_fragment_proto_msgTypes,
+	}.Build()
+	File_c2s_fragment_proto = out.File
+	file_c2s_fragment_proto_goTypes = nil
+	file_c2s_fragment_proto_depIdxs = nil
+}
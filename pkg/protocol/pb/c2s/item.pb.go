@@ -7,7 +7,7 @@
 package c2s
 
 import (
-	common "github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
+	common "github.com/godyy/ggs/pkg/protocol/pb/common"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -195,7 +195,7 @@ const file_c2s_item_proto_rawDesc = "" +
 	"\x03num\x18\x02 \x01(\x03R\x03num\x12\x18\n" +
 	"\aleftNum\x18\x03 \x01(\x03R\aleftNum\".\n" +
 	"\bItemPush\x12\"\n" +
-	"\x05items\x18\x01 \x03(\v2\f.common.ItemR\x05itemsB;Z9github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2sb\x06proto3"
+	"\x05items\x18\x01 \x03(\v2\f.common.ItemR\x05itemsB*Z(github.com/godyy/ggs/pkg/protocol/pb/c2sb\x06proto3"
 
 var (
 	file_c2s_item_proto_rawDescOnce sync.Once
@@ -7,7 +7,7 @@
 package c2s
 
 import (
-	common "github.com/godyy/ggs/internal/infra/actor/protocol/pb/common"
+	common "github.com/godyy/ggs/pkg/protocol/pb/common"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -30,6 +30,9 @@ const (
 	DisconnectPush_Disconnect   DisconnectPush_Reason = 2 // 被断开.
 	DisconnectPush_AnotherLogin DisconnectPush_Reason = 3 // 另一个登录.
 	DisconnectPush_LoginTimeout DisconnectPush_Reason = 4 // 登录超时.
+	DisconnectPush_RateLimited  DisconnectPush_Reason = 5 // 触发流量限制.
+	DisconnectPush_SlowClient   DisconnectPush_Reason = 6 // 下行数据积压过多.
+	DisconnectPush_Reconnect    DisconnectPush_Reason = 7 // 节点下线, 需重新登录以连接其他节点.
 )
 
 // Enum value maps for DisconnectPush_Reason.
@@ -40,6 +43,9 @@ var (
 		2: "Disconnect",
 		3: "AnotherLogin",
 		4: "LoginTimeout",
+		5: "RateLimited",
+		6: "SlowClient",
+		7: "Reconnect",
 	}
 	DisconnectPush_Reason_value = map[string]int32{
 		"Unknown":      0,
@@ -47,6 +53,9 @@ var (
 		"Disconnect":   2,
 		"AnotherLogin": 3,
 		"LoginTimeout": 4,
+		"RateLimited":  5,
+		"SlowClient":   6,
+		"Reconnect":    7,
 	}
 )
 
@@ -74,7 +83,7 @@ func (x DisconnectPush_Reason) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use DisconnectPush_Reason.Descriptor instead.
 func (DisconnectPush_Reason) EnumDescriptor() ([]byte, []int) {
-	return file_c2s_login_proto_rawDescGZIP(), []int{4, 0}
+	return file_c2s_login_proto_rawDescGZIP(), []int{6, 0}
 }
 
 // 登陆请求.
@@ -124,7 +133,9 @@ func (x *LoginReq) GetToken() string {
 
 // 登陆响应.
 type LoginResp struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	ResumeTicket  string                   `protobuf:"bytes,1,opt,name=resumeTicket,proto3" json:"resumeTicket,omitempty"` // 会话恢复票据, 为空表示不支持会话恢复.
+	Modules       []*common.ModuleSnapshot `protobuf:"bytes,2,rep,name=modules,proto3" json:"modules,omitempty"`           // 玩家各模块快照, 由 LoginCharacterResp 原样转发.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -159,6 +170,118 @@ func (*LoginResp) Descriptor() ([]byte, []int) {
 	return file_c2s_login_proto_rawDescGZIP(), []int{1}
 }
 
+func (x *LoginResp) GetResumeTicket() string {
+	if x != nil {
+		return x.ResumeTicket
+	}
+	return ""
+}
+
+func (x *LoginResp) GetModules() []*common.ModuleSnapshot {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
+// 会话恢复请求.
+type ResumeReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        string                 `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`    // 会话恢复票据.
+	LastSeq       uint32                 `protobuf:"varint,2,opt,name=lastSeq,proto3" json:"lastSeq,omitempty"` // 登陆后已收到的下行数据包数量.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeReq) Reset() {
+	*x = ResumeReq{}
+	mi := &file_c2s_login_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeReq) ProtoMessage() {}
+
+func (x *ResumeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_c2s_login_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeReq.ProtoReflect.Descriptor instead.
+func (*ResumeReq) Descriptor() ([]byte, []int) {
+	return file_c2s_login_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResumeReq) GetTicket() string {
+	if x != nil {
+		return x.Ticket
+	}
+	return ""
+}
+
+func (x *ResumeReq) GetLastSeq() uint32 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+// 会话恢复响应.
+type ResumeResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        string                 `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"` // 新的会话恢复票据.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeResp) Reset() {
+	*x = ResumeResp{}
+	mi := &file_c2s_login_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeResp) ProtoMessage() {}
+
+func (x *ResumeResp) ProtoReflect() protoreflect.Message {
+	mi := &file_c2s_login_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeResp.ProtoReflect.Descriptor instead.
+func (*ResumeResp) Descriptor() ([]byte, []int) {
+	return file_c2s_login_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResumeResp) GetTicket() string {
+	if x != nil {
+		return x.Ticket
+	}
+	return ""
+}
+
 // 登陆游戏请求.
 type LoginCharacterReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -170,7 +293,7 @@ type LoginCharacterReq struct {
 
 func (x *LoginCharacterReq) Reset() {
 	*x = LoginCharacterReq{}
-	mi := &file_c2s_login_proto_msgTypes[2]
+	mi := &file_c2s_login_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -182,7 +305,7 @@ func (x *LoginCharacterReq) String() string {
 func (*LoginCharacterReq) ProtoMessage() {}
 
 func (x *LoginCharacterReq) ProtoReflect() protoreflect.Message {
-	mi := &file_c2s_login_proto_msgTypes[2]
+	mi := &file_c2s_login_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -195,7 +318,7 @@ func (x *LoginCharacterReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginCharacterReq.ProtoReflect.Descriptor instead.
 func (*LoginCharacterReq) Descriptor() ([]byte, []int) {
-	return file_c2s_login_proto_rawDescGZIP(), []int{2}
+	return file_c2s_login_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *LoginCharacterReq) GetUid() string {
@@ -214,15 +337,15 @@ func (x *LoginCharacterReq) GetAccountId() int64 {
 
 // 登陆游戏响应.
 type LoginCharacterResp struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Base          *common.PlayerBase     `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Modules       []*common.ModuleSnapshot `protobuf:"bytes,1,rep,name=modules,proto3" json:"modules,omitempty"` // 玩家各模块快照.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LoginCharacterResp) Reset() {
 	*x = LoginCharacterResp{}
-	mi := &file_c2s_login_proto_msgTypes[3]
+	mi := &file_c2s_login_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -234,7 +357,7 @@ func (x *LoginCharacterResp) String() string {
 func (*LoginCharacterResp) ProtoMessage() {}
 
 func (x *LoginCharacterResp) ProtoReflect() protoreflect.Message {
-	mi := &file_c2s_login_proto_msgTypes[3]
+	mi := &file_c2s_login_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -247,12 +370,12 @@ func (x *LoginCharacterResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginCharacterResp.ProtoReflect.Descriptor instead.
 func (*LoginCharacterResp) Descriptor() ([]byte, []int) {
-	return file_c2s_login_proto_rawDescGZIP(), []int{3}
+	return file_c2s_login_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *LoginCharacterResp) GetBase() *common.PlayerBase {
+func (x *LoginCharacterResp) GetModules() []*common.ModuleSnapshot {
 	if x != nil {
-		return x.Base
+		return x.Modules
 	}
 	return nil
 }
@@ -267,7 +390,7 @@ type DisconnectPush struct {
 
 func (x *DisconnectPush) Reset() {
 	*x = DisconnectPush{}
-	mi := &file_c2s_login_proto_msgTypes[4]
+	mi := &file_c2s_login_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -279,7 +402,7 @@ func (x *DisconnectPush) String() string {
 func (*DisconnectPush) ProtoMessage() {}
 
 func (x *DisconnectPush) ProtoReflect() protoreflect.Message {
-	mi := &file_c2s_login_proto_msgTypes[4]
+	mi := &file_c2s_login_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -292,7 +415,7 @@ func (x *DisconnectPush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DisconnectPush.ProtoReflect.Descriptor instead.
 func (*DisconnectPush) Descriptor() ([]byte, []int) {
-	return file_c2s_login_proto_rawDescGZIP(), []int{4}
+	return file_c2s_login_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *DisconnectPush) GetReason() DisconnectPush_Reason {
@@ -311,7 +434,7 @@ type HeartbeatReq struct {
 
 func (x *HeartbeatReq) Reset() {
 	*x = HeartbeatReq{}
-	mi := &file_c2s_login_proto_msgTypes[5]
+	mi := &file_c2s_login_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -323,7 +446,7 @@ func (x *HeartbeatReq) String() string {
 func (*HeartbeatReq) ProtoMessage() {}
 
 func (x *HeartbeatReq) ProtoReflect() protoreflect.Message {
-	mi := &file_c2s_login_proto_msgTypes[5]
+	mi := &file_c2s_login_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -336,7 +459,7 @@ func (x *HeartbeatReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatReq.ProtoReflect.Descriptor instead.
 func (*HeartbeatReq) Descriptor() ([]byte, []int) {
-	return file_c2s_login_proto_rawDescGZIP(), []int{5}
+	return file_c2s_login_proto_rawDescGZIP(), []int{7}
 }
 
 type HeartbeatResp struct {
@@ -347,7 +470,7 @@ type HeartbeatResp struct {
 
 func (x *HeartbeatResp) Reset() {
 	*x = HeartbeatResp{}
-	mi := &file_c2s_login_proto_msgTypes[6]
+	mi := &file_c2s_login_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +482,7 @@ func (x *HeartbeatResp) String() string {
 func (*HeartbeatResp) ProtoMessage() {}
 
 func (x *HeartbeatResp) ProtoReflect() protoreflect.Message {
-	mi := &file_c2s_login_proto_msgTypes[6]
+	mi := &file_c2s_login_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,33 +495,45 @@ func (x *HeartbeatResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatResp.ProtoReflect.Descriptor instead.
 func (*HeartbeatResp) Descriptor() ([]byte, []int) {
-	return file_c2s_login_proto_rawDescGZIP(), []int{6}
+	return file_c2s_login_proto_rawDescGZIP(), []int{8}
 }
 
 var File_c2s_login_proto protoreflect.FileDescriptor
 
 const file_c2s_login_proto_rawDesc = "" +
 	"\n" +
-	"\x0fc2s/login.proto\x12\x03c2s\x1a\x13common/player.proto\" \n" +
+	"\x0fc2s/login.proto\x12\x03c2s\x1a\x11common/sync.proto\" \n" +
 	"\bLoginReq\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"\v\n" +
-	"\tLoginResp\"C\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"a\n" +
+	"\tLoginResp\x12\"\n" +
+	"\fresumeTicket\x18\x01 \x01(\tR\fresumeTicket\x120\n" +
+	"\amodules\x18\x02 \x03(\v2\x16.common.ModuleSnapshotR\amodules\"=\n" +
+	"\tResumeReq\x12\x16\n" +
+	"\x06ticket\x18\x01 \x01(\tR\x06ticket\x12\x18\n" +
+	"\alastSeq\x18\x02 \x01(\rR\alastSeq\"$\n" +
+	"\n" +
+	"ResumeResp\x12\x16\n" +
+	"\x06ticket\x18\x01 \x01(\tR\x06ticket\"C\n" +
 	"\x11LoginCharacterReq\x12\x10\n" +
 	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x1c\n" +
-	"\taccountId\x18\x02 \x01(\x03R\taccountId\"<\n" +
-	"\x12LoginCharacterResp\x12&\n" +
-	"\x04base\x18\x01 \x01(\v2\x12.common.PlayerBaseR\x04base\"\xa0\x01\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\"F\n" +
+	"\x12LoginCharacterResp\x120\n" +
+	"\amodules\x18\x01 \x03(\v2\x16.common.ModuleSnapshotR\amodules\"\xd1\x01\n" +
 	"\x0eDisconnectPush\x122\n" +
-	"\x06reason\x18\x01 \x01(\x0e2\x1a.c2s.DisconnectPush.ReasonR\x06reason\"Z\n" +
+	"\x06reason\x18\x01 \x01(\x0e2\x1a.c2s.DisconnectPush.ReasonR\x06reason\"\x8a\x01\n" +
 	"\x06Reason\x12\v\n" +
 	"\aUnknown\x10\x00\x12\x0f\n" +
 	"\vSystemError\x10\x01\x12\x0e\n" +
 	"\n" +
 	"Disconnect\x10\x02\x12\x10\n" +
 	"\fAnotherLogin\x10\x03\x12\x10\n" +
-	"\fLoginTimeout\x10\x04\"\x0e\n" +
+	"\fLoginTimeout\x10\x04\x12\x0f\n" +
+	"\vRateLimited\x10\x05\x12\x0e\n" +
+	"\n" +
+	"SlowClient\x10\x06\x12\r\n" +
+	"\tReconnect\x10\a\"\x0e\n" +
 	"\fHeartbeatReq\"\x0f\n" +
-	"\rHeartbeatRespB;Z9github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2sb\x06proto3"
+	"\rHeartbeatRespB*Z(github.com/godyy/ggs/pkg/protocol/pb/c2sb\x06proto3"
 
 var (
 	file_c2s_login_proto_rawDescOnce sync.Once
@@ -413,26 +548,29 @@ func file_c2s_login_proto_rawDescGZIP() []byte {
 }
 
 var file_c2s_login_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_c2s_login_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_c2s_login_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_c2s_login_proto_goTypes = []any{
-	(DisconnectPush_Reason)(0), // 0: c2s.DisconnectPush.Reason
-	(*LoginReq)(nil),           // 1: c2s.LoginReq
-	(*LoginResp)(nil),          // 2: c2s.LoginResp
-	(*LoginCharacterReq)(nil),  // 3: c2s.LoginCharacterReq
-	(*LoginCharacterResp)(nil), // 4: c2s.LoginCharacterResp
-	(*DisconnectPush)(nil),     // 5: c2s.DisconnectPush
-	(*HeartbeatReq)(nil),       // 6: c2s.HeartbeatReq
-	(*HeartbeatResp)(nil),      // 7: c2s.HeartbeatResp
-	(*common.PlayerBase)(nil),  // 8: common.PlayerBase
+	(DisconnectPush_Reason)(0),    // 0: c2s.DisconnectPush.Reason
+	(*LoginReq)(nil),              // 1: c2s.LoginReq
+	(*LoginResp)(nil),             // 2: c2s.LoginResp
+	(*ResumeReq)(nil),             // 3: c2s.ResumeReq
+	(*ResumeResp)(nil),            // 4: c2s.ResumeResp
+	(*LoginCharacterReq)(nil),     // 5: c2s.LoginCharacterReq
+	(*LoginCharacterResp)(nil),    // 6: c2s.LoginCharacterResp
+	(*DisconnectPush)(nil),        // 7: c2s.DisconnectPush
+	(*HeartbeatReq)(nil),          // 8: c2s.HeartbeatReq
+	(*HeartbeatResp)(nil),         // 9: c2s.HeartbeatResp
+	(*common.ModuleSnapshot)(nil), // 10: common.ModuleSnapshot
 }
 var file_c2s_login_proto_depIdxs = []int32{
-	8, // 0: c2s.LoginCharacterResp.base:type_name -> common.PlayerBase
-	0, // 1: c2s.DisconnectPush.reason:type_name -> c2s.DisconnectPush.Reason
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	10, // 0: c2s.LoginResp.modules:type_name -> common.ModuleSnapshot
+	10, // 1: c2s.LoginCharacterResp.modules:type_name -> common.ModuleSnapshot
+	0,  // 2: c2s.DisconnectPush.reason:type_name -> c2s.DisconnectPush.Reason
+	3,  // [3:3] is the sub-list for method output_type
+	3,  // [3:3] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_c2s_login_proto_init() }
@@ -446,7 +584,7 @@ func file_c2s_login_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_c2s_login_proto_rawDesc), len(file_c2s_login_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
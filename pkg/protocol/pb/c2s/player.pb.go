@@ -7,6 +7,7 @@
 package c2s
 
 import (
+	common "github.com/godyy/ggs/pkg/protocol/pb/common"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -111,15 +112,62 @@ func (x *ModifyNameResp) GetName() string {
 	return ""
 }
 
+// 角色基础信息变更通知.
+type PlayerBasePush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          *common.PlayerBase     `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"` // 变更后的基础信息.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerBasePush) Reset() {
+	*x = PlayerBasePush{}
+	mi := &file_c2s_player_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerBasePush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerBasePush) ProtoMessage() {}
+
+func (x *PlayerBasePush) ProtoReflect() protoreflect.Message {
+	mi := &file_c2s_player_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerBasePush.ProtoReflect.Descriptor instead.
+func (*PlayerBasePush) Descriptor() ([]byte, []int) {
+	return file_c2s_player_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlayerBasePush) GetBase() *common.PlayerBase {
+	if x != nil {
+		return x.Base
+	}
+	return nil
+}
+
 var File_c2s_player_proto protoreflect.FileDescriptor
 
 const file_c2s_player_proto_rawDesc = "" +
 	"\n" +
-	"\x10c2s/player.proto\x12\x03c2s\"#\n" +
+	"\x10c2s/player.proto\x12\x03c2s\x1a\x13common/player.proto\"#\n" +
 	"\rModifyNameReq\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\"$\n" +
 	"\x0eModifyNameResp\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04nameB;Z9github.com/godyy/ggs/internal/infra/actor/protocol/pb/c2sb\x06proto3"
+	"\x04name\x18\x01 \x01(\tR\x04name\"8\n" +
+	"\x0ePlayerBasePush\x12&\n" +
+	"\x04base\x18\x01 \x01(\v2\x12.common.PlayerBaseR\x04baseB*Z(github.com/godyy/ggs/pkg/protocol/pb/c2sb\x06proto3"
 
 var (
 	file_c2s_player_proto_rawDescOnce sync.Once
@@ -133,17 +181,20 @@ func file_c2s_player_proto_rawDescGZIP() []byte {
 	return file_c2s_player_proto_rawDescData
 }
 
-var file_c2s_player_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_c2s_player_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_c2s_player_proto_goTypes = []any{
-	(*ModifyNameReq)(nil),  // 0: c2s.ModifyNameReq
-	(*ModifyNameResp)(nil), // 1: c2s.ModifyNameResp
+	(*ModifyNameReq)(nil),     // 0: c2s.ModifyNameReq
+	(*ModifyNameResp)(nil),    // 1: c2s.ModifyNameResp
+	(*PlayerBasePush)(nil),    // 2: c2s.PlayerBasePush
+	(*common.PlayerBase)(nil), // 3: common.PlayerBase
 }
 var file_c2s_player_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3, // 0: c2s.PlayerBasePush.base:type_name -> common.PlayerBase
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_c2s_player_proto_init() }
@@ -157,7 +208,7 @@ func file_c2s_player_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_c2s_player_proto_rawDesc), len(file_c2s_player_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
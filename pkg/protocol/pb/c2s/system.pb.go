@@ -0,0 +1,235 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v7.34.0
+// source: c2s/system.proto
+
+package c2s
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 系统推送, 例如运维公告.
+type SystemPush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"` // 内容.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemPush) Reset() {
+	*x = SystemPush{}
+	mi := &file_c2s_system_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemPush) ProtoMessage() {}
+
+func (x *SystemPush) ProtoReflect() protoreflect.Message {
+	mi := &file_c2s_system_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemPush.ProtoReflect.Descriptor instead.
+func (*SystemPush) Descriptor() ([]byte, []int) {
+	return file_c2s_system_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SystemPush) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// 玩家迁移推送, 玩家 Actor 迁移前发送给网关, 网关据此在 Actor 断开后重新连接至目标节点.
+// 持久化失败时再次发送并标记中止, 网关随即断开客户端, 不再等待迁移超时.
+// 由网关拦截, 不下发至客户端.
+type PlayerMigratePush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetNodeId  string                 `protobuf:"bytes,1,opt,name=targetNodeId,proto3" json:"targetNodeId,omitempty"` // 目标节点ID.
+	Aborted       bool                   `protobuf:"varint,2,opt,name=aborted,proto3" json:"aborted,omitempty"`          // 迁移是否已中止.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerMigratePush) Reset() {
+	*x = PlayerMigratePush{}
+	mi := &file_c2s_system_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerMigratePush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerMigratePush) ProtoMessage() {}
+
+func (x *PlayerMigratePush) ProtoReflect() protoreflect.Message {
+	mi := &file_c2s_system_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerMigratePush.ProtoReflect.Descriptor instead.
+func (*PlayerMigratePush) Descriptor() ([]byte, []int) {
+	return file_c2s_system_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PlayerMigratePush) GetTargetNodeId() string {
+	if x != nil {
+		return x.TargetNodeId
+	}
+	return ""
+}
+
+func (x *PlayerMigratePush) GetAborted() bool {
+	if x != nil {
+		return x.Aborted
+	}
+	return false
+}
+
+// 玩家停止推送, 玩家 Actor 因排空等原因主动停止前发送给网关, 网关在 Actor 断开后以 reason 断开客户端.
+// 由网关拦截, 不下发至客户端.
+type PlayerStopPush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        DisconnectPush_Reason  `protobuf:"varint,1,opt,name=reason,proto3,enum=c2s.DisconnectPush_Reason" json:"reason,omitempty"` // 断开原因.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerStopPush) Reset() {
+	*x = PlayerStopPush{}
+	mi := &file_c2s_system_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerStopPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerStopPush) ProtoMessage() {}
+
+func (x *PlayerStopPush) ProtoReflect() protoreflect.Message {
+	mi := &file_c2s_system_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerStopPush.ProtoReflect.Descriptor instead.
+func (*PlayerStopPush) Descriptor() ([]byte, []int) {
+	return file_c2s_system_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlayerStopPush) GetReason() DisconnectPush_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return DisconnectPush_Unknown
+}
+
+var File_c2s_system_proto protoreflect.FileDescriptor
+
+const file_c2s_system_proto_rawDesc = "" +
+	"\n" +
+	"\x10c2s/system.proto\x12\x03c2s\x1a\x0fc2s/login.proto\"&\n" +
+	"\n" +
+	"SystemPush\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\tR\acontent\"Q\n" +
+	"\x11PlayerMigratePush\x12\"\n" +
+	"\ftargetNodeId\x18\x01 \x01(\tR\ftargetNodeId\x12\x18\n" +
+	"\aaborted\x18\x02 \x01(\bR\aaborted\"D\n" +
+	"\x0ePlayerStopPush\x122\n" +
+	"\x06reason\x18\x01 \x01(\x0e2\x1a.c2s.DisconnectPush.ReasonR\x06reasonB*Z(github.com/godyy/ggs/pkg/protocol/pb/c2sb\x06proto3"
+
+var (
+	file_c2s_system_proto_rawDescOnce sync.Once
+	file_c2s_system_proto_rawDescData []byte
+)
+
+func file_c2s_system_proto_rawDescGZIP() []byte {
+	file_c2s_system_proto_rawDescOnce.Do(func() {
+		file_c2s_system_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_c2s_system_proto_rawDesc), len(file_c2s_system_proto_rawDesc)))
+	})
+	return file_c2s_system_proto_rawDescData
+}
+
+var file_c2s_system_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_c2s_system_proto_goTypes = []any{
+	(*SystemPush)(nil),         // 0: c2s.SystemPush
+	(*PlayerMigratePush)(nil),  // 1: c2s.PlayerMigratePush
+	(*PlayerStopPush)(nil),     // 2: c2s.PlayerStopPush
+	(DisconnectPush_Reason)(0), // 3: c2s.DisconnectPush.Reason
+}
+var file_c2s_system_proto_depIdxs = []int32{
+	3, // 0: c2s.PlayerStopPush.reason:type_name -> c2s.DisconnectPush.Reason
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_c2s_system_proto_init() }
+func file_c2s_system_proto_init() {
+	if File_c2s_system_proto != nil {
+		return
+	}
+	file_c2s_login_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_c2s_system_proto_rawDesc), len(file_c2s_system_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_c2s_system_proto_goTypes,
+		DependencyIndexes: file_c2s_system_proto_depIdxs,
+		MessageInfos:      file_c2s_system_proto_msgTypes,
+	}.Build()
+	File_c2s_system_proto = out.File
+	file_c2s_system_proto_goTypes = nil
+	file_c2s_system_proto_depIdxs = nil
+}
@@ -185,7 +185,7 @@ const file_common_common_proto_rawDesc = "" +
 	"\x01s\x18\x01 \x01(\tH\x00R\x01s\x12\x0e\n" +
 	"\x01i\x18\x02 \x01(\x03H\x00R\x01i\x12\x0e\n" +
 	"\x01f\x18\x03 \x01(\x01H\x00R\x01fB\a\n" +
-	"\x05valueB>Z<github.com/godyy/ggs/internal/infra/actor/protocol/pb/commonb\x06proto3"
+	"\x05valueB-Z+github.com/godyy/ggs/pkg/protocol/pb/commonb\x06proto3"
 
 var (
 	file_common_common_proto_rawDescOnce sync.Once
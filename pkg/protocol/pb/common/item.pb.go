@@ -81,7 +81,7 @@ const file_common_item_proto_rawDesc = "" +
 	"\x11common/item.proto\x12\x06common\",\n" +
 	"\x04Item\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
-	"\x05count\x18\x02 \x01(\x03R\x05countB>Z<github.com/godyy/ggs/internal/infra/actor/protocol/pb/commonb\x06proto3"
+	"\x05count\x18\x02 \x01(\x03R\x05countB-Z+github.com/godyy/ggs/pkg/protocol/pb/commonb\x06proto3"
 
 var (
 	file_common_item_proto_rawDescOnce sync.Once
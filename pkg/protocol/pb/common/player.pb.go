@@ -73,7 +73,7 @@ const file_common_player_proto_rawDesc = "" +
 	"\x13common/player.proto\x12\x06common\" \n" +
 	"\n" +
 	"PlayerBase\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04nameB>Z<github.com/godyy/ggs/internal/infra/actor/protocol/pb/commonb\x06proto3"
+	"\x04name\x18\x01 \x01(\tR\x04nameB-Z+github.com/godyy/ggs/pkg/protocol/pb/commonb\x06proto3"
 
 var (
 	file_common_player_proto_rawDescOnce sync.Once
@@ -0,0 +1,222 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v7.34.0
+// source: common/sync.proto
+
+package common
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 玩家模块快照, 登录时下发全部模块的快照.
+type ModuleSnapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Module:
+	//
+	//	*ModuleSnapshot_Base
+	//	*ModuleSnapshot_Items
+	Module        isModuleSnapshot_Module `protobuf_oneof:"module"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModuleSnapshot) Reset() {
+	*x = ModuleSnapshot{}
+	mi := &file_common_sync_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModuleSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleSnapshot) ProtoMessage() {}
+
+func (x *ModuleSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_common_sync_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleSnapshot.ProtoReflect.Descriptor instead.
+func (*ModuleSnapshot) Descriptor() ([]byte, []int) {
+	return file_common_sync_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ModuleSnapshot) GetModule() isModuleSnapshot_Module {
+	if x != nil {
+		return x.Module
+	}
+	return nil
+}
+
+func (x *ModuleSnapshot) GetBase() *PlayerBase {
+	if x != nil {
+		if x, ok := x.Module.(*ModuleSnapshot_Base); ok {
+			return x.Base
+		}
+	}
+	return nil
+}
+
+func (x *ModuleSnapshot) GetItems() *ItemsSnapshot {
+	if x != nil {
+		if x, ok := x.Module.(*ModuleSnapshot_Items); ok {
+			return x.Items
+		}
+	}
+	return nil
+}
+
+type isModuleSnapshot_Module interface {
+	isModuleSnapshot_Module()
+}
+
+type ModuleSnapshot_Base struct {
+	Base *PlayerBase `protobuf:"bytes,1,opt,name=base,proto3,oneof"` // 基础信息.
+}
+
+type ModuleSnapshot_Items struct {
+	Items *ItemsSnapshot `protobuf:"bytes,2,opt,name=items,proto3,oneof"` // 道具.
+}
+
+func (*ModuleSnapshot_Base) isModuleSnapshot_Module() {}
+
+func (*ModuleSnapshot_Items) isModuleSnapshot_Module() {}
+
+// 道具模块快照.
+type ItemsSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Item                `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"` // 持有的道具, 按道具ID排序.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemsSnapshot) Reset() {
+	*x = ItemsSnapshot{}
+	mi := &file_common_sync_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemsSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemsSnapshot) ProtoMessage() {}
+
+func (x *ItemsSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_common_sync_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemsSnapshot.ProtoReflect.Descriptor instead.
+func (*ItemsSnapshot) Descriptor() ([]byte, []int) {
+	return file_common_sync_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ItemsSnapshot) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+var File_common_sync_proto protoreflect.FileDescriptor
+
+const file_common_sync_proto_rawDesc = "" +
+	"\n" +
+	"\x11common/sync.proto\x12\x06common\x1a\x11common/item.proto\x1a\x13common/player.proto\"s\n" +
+	"\x0eModuleSnapshot\x12(\n" +
+	"\x04base\x18\x01 \x01(\v2\x12.common.PlayerBaseH\x00R\x04base\x12-\n" +
+	"\x05items\x18\x02 \x01(\v2\x15.common.ItemsSnapshotH\x00R\x05itemsB\b\n" +
+	"\x06module\"3\n" +
+	"\rItemsSnapshot\x12\"\n" +
+	"\x05items\x18\x01 \x03(\v2\f.common.ItemR\x05itemsB-Z+github.com/godyy/ggs/pkg/protocol/pb/commonb\x06proto3"
+
+var (
+	file_common_sync_proto_rawDescOnce sync.Once
+	file_common_sync_proto_rawDescData []byte
+)
+
+func file_common_sync_proto_rawDescGZIP() []byte {
+	file_common_sync_proto_rawDescOnce.Do(func() {
+		file_common_sync_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_common_sync_proto_rawDesc), len(file_common_sync_proto_rawDesc)))
+	})
+	return file_common_sync_proto_rawDescData
+}
+
+var file_common_sync_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_common_sync_proto_goTypes = []any{
+	(*ModuleSnapshot)(nil), // 0: common.ModuleSnapshot
+	(*ItemsSnapshot)(nil),  // 1: common.ItemsSnapshot
+	(*PlayerBase)(nil),     // 2: common.PlayerBase
+	(*Item)(nil),           // 3: common.Item
+}
+var file_common_sync_proto_depIdxs = []int32{
+	2, // 0: common.ModuleSnapshot.base:type_name -> common.PlayerBase
+	1, // 1: common.ModuleSnapshot.items:type_name -> common.ItemsSnapshot
+	3, // 2: common.ItemsSnapshot.items:type_name -> common.Item
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_common_sync_proto_init() }
+func file_common_sync_proto_init() {
+	if File_common_sync_proto != nil {
+		return
+	}
+	file_common_item_proto_init()
+	file_common_player_proto_init()
+	file_common_sync_proto_msgTypes[0].OneofWrappers = []any{
+		(*ModuleSnapshot_Base)(nil),
+		(*ModuleSnapshot_Items)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_sync_proto_rawDesc), len(file_common_sync_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_common_sync_proto_goTypes,
+		DependencyIndexes: file_common_sync_proto_depIdxs,
+		MessageInfos:      file_common_sync_proto_msgTypes,
+	}.Build()
+	File_common_sync_proto = out.File
+	file_common_sync_proto_goTypes = nil
+	file_common_sync_proto_depIdxs = nil
+}
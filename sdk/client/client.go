@@ -0,0 +1,331 @@
+// Package client Go 客户端 SDK.
+//
+// 提供登录服 HTTP 登录、网关连接与密钥交换、按 seq 匹配响应的并发请求、按消息类型订阅推送与自动心跳:
+//
+//	resp, _ := client.Login(ctx, loginURLRoot, userToken, serverId)
+//	c, _ := client.Dial(resp.AgentAddr, client.Options{})
+//	defer c.Close()
+//	if _, err := c.Login(ctx, resp.Token); err != nil { ... }
+//	client.Subscribe(c, func(push *pbc2s.ItemPush) { ... })
+//	useResp, err := client.Request[*pbc2s.UseItemResp](ctx, c, &pbc2s.UseItemReq{ItemId: 1, Num: 1})
+//
+// 需要自行处理全部下行数据包(例如会话恢复)时, 可使用更底层的 DialStream.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcom "github.com/godyy/ggs/pkg/protocol/pb/common"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// defaultRequestTimeout 默认请求超时时间.
+const defaultRequestTimeout = 5 * time.Second
+
+// ErrClosed 连接已关闭.
+var ErrClosed = errors.New("client closed")
+
+// ServerError 服务端返回的错误响应.
+type ServerError struct {
+	Resp *pbcom.Error
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error: %s", pbc2s.ErrCode(e.Resp.Code))
+}
+
+// Code 返回错误码.
+func (e *ServerError) Code() int32 {
+	return e.Resp.Code
+}
+
+// Options 客户端选项.
+type Options struct {
+	// RequestTimeout 请求 ctx 未设置截止时间时使用的超时时间, 默认 5s.
+	RequestTimeout time.Duration
+
+	// HeartbeatInterval 登录成功后自动发送心跳的间隔, 默认 consts.HeartbeatInterval, 小于 0 表示不发送.
+	HeartbeatInterval time.Duration
+
+	// OnClose 连接关闭时回调, err 为关闭原因, 主动关闭时为 ErrClosed.
+	OnClose func(err error)
+}
+
+// withDefaults 返回补全默认值后的选项.
+func (o Options) withDefaults() Options {
+	if o.RequestTimeout <= 0 {
+		o.RequestTimeout = defaultRequestTimeout
+	}
+	if o.HeartbeatInterval == 0 {
+		o.HeartbeatInterval = consts.HeartbeatInterval
+	}
+	return o
+}
+
+// subscription 推送订阅.
+type subscription struct {
+	id      uint64
+	handler func(proto.Message)
+}
+
+// Client 网关客户端, 方法可并发调用.
+type Client struct {
+	opts    Options
+	stream  *Stream
+	seqIncr atomic.Uint32
+
+	mtx       sync.Mutex
+	pending   map[uint32]chan proto.Message            // 等待响应的请求, seq -> 响应通道.
+	subs      map[protoreflect.FullName][]subscription // 推送订阅, 消息全名 -> 订阅, 空名表示订阅全部推送.
+	subIdIncr uint64
+	closed    bool
+	err       error         // 关闭原因.
+	done      chan struct{} // 连接关闭时关闭.
+
+	heartbeatOnce sync.Once
+}
+
+// Dial 连接网关并交换密钥.
+func Dial(addr string, opts Options) (*Client, error) {
+	c := newClient(opts)
+	stream, err := DialStream(addr, streamHandler{c: c})
+	if err != nil {
+		return nil, err
+	}
+	c.stream = stream
+	return c, nil
+}
+
+// newClient 创建未连接的客户端.
+func newClient(opts Options) *Client {
+	return &Client{
+		opts:    opts.withDefaults(),
+		pending: make(map[uint32]chan proto.Message),
+		subs:    make(map[protoreflect.FullName][]subscription),
+		done:    make(chan struct{}),
+	}
+}
+
+// Login 使用登录服下发的网关令牌登录, 成功后开始自动发送心跳.
+func (c *Client) Login(ctx context.Context, token string) (*pbc2s.LoginResp, error) {
+	resp, err := Request[*pbc2s.LoginResp](ctx, c, &pbc2s.LoginReq{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	if c.opts.HeartbeatInterval > 0 {
+		c.heartbeatOnce.Do(func() {
+			go c.heartbeatLoop()
+		})
+	}
+	return resp, nil
+}
+
+// heartbeatLoop 定期发送心跳, 直至连接关闭.
+func (c *Client) heartbeatLoop() {
+	ticker := time.NewTicker(c.opts.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.Call(context.Background(), &pbc2s.HeartbeatReq{})
+		}
+	}
+}
+
+// Call 发送请求并等待响应. 错误响应以 *ServerError 返回.
+func (c *Client) Call(ctx context.Context, req proto.Message) (proto.Message, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.RequestTimeout)
+		defer cancel()
+	}
+
+	seq := c.nextSeq()
+	ch := make(chan proto.Message, 1)
+	c.mtx.Lock()
+	if c.closed {
+		err := c.err
+		c.mtx.Unlock()
+		return nil, err
+	}
+	c.pending[seq] = ch
+	c.mtx.Unlock()
+	defer func() {
+		c.mtx.Lock()
+		delete(c.pending, seq)
+		c.mtx.Unlock()
+	}()
+
+	if err := c.stream.SendReq(seq, req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		if e, ok := resp.(*pbcom.Error); ok {
+			return nil, &ServerError{Resp: e}
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, c.Err()
+	}
+}
+
+// Request 发送请求并等待 Resp 类型的响应.
+func Request[Resp proto.Message](ctx context.Context, c *Client, req proto.Message) (Resp, error) {
+	var r Resp
+	resp, err := c.Call(ctx, req)
+	if err != nil {
+		return r, err
+	}
+	r, ok := resp.(Resp)
+	if !ok {
+		return r, fmt.Errorf("unexpected response %s", resp.ProtoReflect().Descriptor().FullName())
+	}
+	return r, nil
+}
+
+// nextSeq 生成请求 seq, 跳过网关保留的 seq.
+func (c *Client) nextSeq() uint32 {
+	for {
+		if seq := c.seqIncr.Add(1); !reservedSeq(seq) {
+			return seq
+		}
+	}
+}
+
+// reservedSeq 返回 seq 是否由网关保留: 0 用于会话挂起期间代发心跳,
+// math.MaxUint32 用于玩家迁移后代为重新登录.
+func reservedSeq(seq uint32) bool {
+	return seq == 0 || seq == math.MaxUint32
+}
+
+// Subscribe 订阅全名为 name 的推送, 返回取消订阅的函数.
+// handler 在读取协程中执行, 不应阻塞, 也不能在其中同步等待请求响应.
+func (c *Client) Subscribe(name protoreflect.FullName, handler func(proto.Message)) (cancel func()) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.subIdIncr++
+	id := c.subIdIncr
+	c.subs[name] = append(c.subs[name], subscription{id: id, handler: handler})
+	return func() {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		subs := c.subs[name]
+		for i := range subs {
+			if subs[i].id == id {
+				c.subs[name] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// SubscribeAll 订阅全部推送, 返回取消订阅的函数.
+func (c *Client) SubscribeAll(handler func(proto.Message)) (cancel func()) {
+	return c.Subscribe("", handler)
+}
+
+// Subscribe 订阅 T 类型的推送, 返回取消订阅的函数.
+func Subscribe[T proto.Message](c *Client, handler func(T)) (cancel func()) {
+	var zero T
+	return c.Subscribe(zero.ProtoReflect().Descriptor().FullName(), func(m proto.Message) {
+		handler(m.(T))
+	})
+}
+
+// dispatchPush 分发推送.
+func (c *Client) dispatchPush(m proto.Message) {
+	name := m.ProtoReflect().Descriptor().FullName()
+	c.mtx.Lock()
+	subs := make([]subscription, 0, len(c.subs[name])+len(c.subs[""]))
+	subs = append(subs, c.subs[name]...)
+	subs = append(subs, c.subs[""]...)
+	c.mtx.Unlock()
+	for _, sub := range subs {
+		sub.handler(m)
+	}
+}
+
+// dispatchResp 分发响应.
+func (c *Client) dispatchResp(seq uint32, m proto.Message) {
+	c.mtx.Lock()
+	ch := c.pending[seq]
+	c.mtx.Unlock()
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- m:
+	default:
+	}
+}
+
+// Done 返回连接关闭通知.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err 返回连接关闭原因, 未关闭时返回 nil.
+func (c *Client) Err() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.err
+}
+
+// Close 关闭连接.
+func (c *Client) Close() {
+	c.close(ErrClosed)
+	c.stream.Close()
+}
+
+// close 标记连接关闭并回调 OnClose, 仅执行一次.
+func (c *Client) close(err error) {
+	c.mtx.Lock()
+	if c.closed {
+		c.mtx.Unlock()
+		return
+	}
+	c.closed = true
+	c.err = err
+	close(c.done)
+	c.mtx.Unlock()
+
+	if c.opts.OnClose != nil {
+		c.opts.OnClose(err)
+	}
+}
+
+// streamHandler 处理 Stream 事件.
+type streamHandler struct {
+	c *Client
+}
+
+// OnStreamMsg 处理消息.
+func (h streamHandler) OnStreamMsg(msg Msg) {
+	switch msg.Pt {
+	case codecc2s.PtResp:
+		h.c.dispatchResp(msg.Seq, msg.Msg)
+	case codecc2s.PtPush:
+		h.c.dispatchPush(msg.Msg)
+	}
+}
+
+// OnStreamClose 处理关闭事件.
+func (h streamHandler) OnStreamClose(err error) {
+	h.c.close(err)
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	inet "github.com/godyy/ggs/internal/base/net"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	"github.com/godyy/ggs/pkg/compress"
+	pbc2s "github.com/godyy/ggs/pkg/protocol/pb/c2s"
+	pbcom "github.com/godyy/ggs/pkg/protocol/pb/common"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeAgent 明文网关, 收齐 n 个请求后逆序响应, 并在每个 UseItemReq 响应前推送 ItemPush.
+func fakeAgent(t *testing.T, conn net.Conn, n int) {
+	type req struct {
+		seq uint32
+		msg proto.Message
+	}
+	var reqs []req
+	for len(reqs) < n {
+		p, err := inet.ReadPacket(conn, consts.ReadWriteTimeout)
+		if err != nil {
+			return
+		}
+		msg, err := codecc2s.DecodeMessage(c2s.Registry, p)
+		if !assert.NoError(t, err) {
+			return
+		}
+		reqs = append(reqs, req{seq: codecc2s.HeadGetSeq(p), msg: msg})
+	}
+
+	write := func(pt int8, seq uint32, m proto.Message) {
+		p, err := codecc2s.EncodePacket(c2s.Registry, pt, seq, m)
+		if assert.NoError(t, err) {
+			assert.NoError(t, inet.WritePacket(conn, p, consts.ReadWriteTimeout))
+		}
+	}
+	for i := len(reqs) - 1; i >= 0; i-- {
+		switch m := reqs[i].msg.(type) {
+		case *pbc2s.UseItemReq:
+			if m.Num <= 0 {
+				write(codecc2s.PtResp, reqs[i].seq, &pbcom.Error{Code: int32(pbc2s.ErrCode_ECItemNotEnough)})
+				continue
+			}
+			write(codecc2s.PtPush, 0, &pbc2s.ItemPush{Items: []*pbcom.Item{{Id: m.ItemId, Count: m.Num}}})
+			write(codecc2s.PtResp, reqs[i].seq, &pbc2s.UseItemResp{ItemId: m.ItemId, Num: m.Num})
+		default:
+			write(codecc2s.PtResp, reqs[i].seq, &pbc2s.HeartbeatResp{})
+		}
+	}
+}
+
+func newTestClient(t *testing.T, n int) *Client {
+	cliConn, srvConn := net.Pipe()
+	go fakeAgent(t, srvConn, n)
+	c := newClient(Options{RequestTimeout: time.Second})
+	stream, err := NewStream(cliConn, nil, compress.None, streamHandler{c: c})
+	require.NoError(t, err)
+	c.stream = stream
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestClientConcurrentRequests(t *testing.T) {
+	const n = 8
+	c := newTestClient(t, n)
+
+	var pushMtx sync.Mutex
+	pushes := make(map[int32]int)
+	Subscribe(c, func(push *pbc2s.ItemPush) {
+		pushMtx.Lock()
+		pushes[push.Items[0].Id]++
+		pushMtx.Unlock()
+	})
+	all := 0
+	cancel := c.SubscribeAll(func(proto.Message) { all++ })
+	cancel()
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := Request[*pbc2s.UseItemResp](context.Background(), c, &pbc2s.UseItemReq{ItemId: int32(i), Num: int64(i)})
+			if i == 0 {
+				var se *ServerError
+				if assert.True(t, errors.As(err, &se)) {
+					assert.Equal(t, int32(pbc2s.ErrCode_ECItemNotEnough), se.Code())
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, int32(i), resp.ItemId)
+			}
+		}()
+	}
+	wg.Wait()
+
+	pushMtx.Lock()
+	defer pushMtx.Unlock()
+	assert.Len(t, pushes, n-1)
+	assert.Zero(t, all)
+}
+
+func TestClientClosed(t *testing.T) {
+	c := newTestClient(t, 2)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.Call(context.Background(), &pbc2s.HeartbeatReq{})
+		errCh <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	c.Close()
+	assert.ErrorIs(t, <-errCh, ErrClosed)
+	_, err := c.Call(context.Background(), &pbc2s.HeartbeatReq{})
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestClient_NextSeqSkipsReserved(t *testing.T) {
+	c := &Client{}
+	c.seqIncr.Store(math.MaxUint32 - 2)
+	assert.Equal(t, uint32(math.MaxUint32-1), c.nextSeq())
+	assert.Equal(t, uint32(1), c.nextSeq())
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"crypto/rand"
+	"net"
+
+	icrypto "github.com/godyy/ggs/internal/base/crypto"
+	"github.com/godyy/ggs/pkg/compress"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// DialStream 连接网关, 交换密钥后创建 Stream, 由 handler 处理收到的消息.
+func DialStream(addr string, handler Handler) (*Stream, error) {
+	// 建立连接
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 交换密钥
+	sessionKey, compression, err := exchangeSecretKey(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// 创建stream
+	stream, err := NewStream(conn, sessionKey, compression, handler)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return stream, nil
+}
+
+// exchangeSecretKey 交换密钥, 同时协商压缩算法.
+func exchangeSecretKey(conn net.Conn) ([]byte, compress.Algorithm, error) {
+	// 生成临时secret key
+	tmpKey := make([]byte, 16)
+	rand.Read(tmpKey)
+
+	// 创建加密器
+	entryptor, err := icrypto.CreateRSAEncryptor()
+	if err != nil {
+		return nil, compress.None, pkgerrors.WithMessage(err, "create encryptor failed")
+	}
+
+	// 发送临时secret key, 附加支持的压缩算法
+	if err := packetReadWriter.EncryptAndWritePacket(conn, compress.AppendOffer(tmpKey, compress.Supported), entryptor); err != nil {
+		return nil, compress.None, pkgerrors.WithMessage(err, "encrypt and write tmpKey failed")
+	}
+
+	// 接收会话密钥
+	sessionKeyDecryptor, err := icrypto.CreateAESCrypto(tmpKey)
+	if err != nil {
+		return nil, compress.None, pkgerrors.WithMessage(err, "create sessionKey decryptor failed")
+	}
+	sessionKey, err := packetReadWriter.ReadAndDecryptPacket(conn, sessionKeyDecryptor)
+	if err != nil {
+		return nil, compress.None, pkgerrors.WithMessage(err, "read and decrypt sessionKey failed")
+	}
+
+	// 解析服务端选定的压缩算法
+	sessionKey, compression, err := compress.ParseSelection(sessionKey, len(tmpKey))
+	if err != nil {
+		return nil, compress.None, pkgerrors.WithMessage(err, "parse compression selection failed")
+	}
+
+	return sessionKey, compression, nil
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+
+	"github.com/godyy/ggs/app/login/httpproto"
+	"github.com/godyy/ggs/internal/utils/httputils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Login 通过登录服 HTTP 接口登录, 选择 serverId 上的角色, 不存在时创建角色.
+// 返回网关登录令牌与分配的网关地址, userToken 为平台签发的用户令牌.
+func Login(ctx context.Context, urlRoot string, userToken string, serverId int64) (*httpproto.CharacterLoginResp, error) {
+	characters, err := GetCharacterList(ctx, urlRoot, userToken)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "get character list")
+	}
+	var characterId int64
+	for _, c := range characters {
+		if c.ServerID == serverId {
+			characterId = c.ID
+			break
+		}
+	}
+	if characterId == 0 {
+		if characterId, err = CreateCharacter(ctx, urlRoot, userToken, serverId); err != nil {
+			return nil, pkgerrors.WithMessage(err, "create character")
+		}
+	}
+	resp, err := LoginCharacter(ctx, urlRoot, userToken, characterId)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "login character")
+	}
+	return resp, nil
+}
+
+// GetCharacterList 获取角色列表.
+func GetCharacterList(ctx context.Context, urlRoot string, token string) ([]httpproto.CharacterInfo, error) {
+	resp := httpproto.GetCharacterListResp{}
+	if err := httputils.GetJsonWithContext(ctx, urlRoot+"/character/list?token="+token, &resp, nil); err != nil {
+		return nil, err
+	}
+	return resp.CharacterList, nil
+}
+
+// CreateCharacter 在 serverId 上创建角色, 返回角色ID.
+func CreateCharacter(ctx context.Context, urlRoot string, token string, serverId int64) (int64, error) {
+	req := httpproto.CreateCharacterReq{
+		ServerID: serverId,
+	}
+	resp := httpproto.CreateCharacterResp{}
+	if err := httputils.PostJsonWithContext(ctx, urlRoot+"/character/create?token="+token, &req, &resp, nil); err != nil {
+		return 0, err
+	}
+	return resp.CharacterID, nil
+}
+
+// LoginCharacter 登录角色, 返回网关登录令牌与分配的网关地址.
+func LoginCharacter(ctx context.Context, urlRoot string, token string, characterId int64) (*httpproto.CharacterLoginResp, error) {
+	req := httpproto.CharacterLoginReq{
+		CharacterID: characterId,
+	}
+	resp := &httpproto.CharacterLoginResp{}
+	if err := httputils.PostJsonWithContext(ctx, urlRoot+"/character/login?token="+token, &req, resp, nil); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
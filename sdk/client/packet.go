@@ -0,0 +1,34 @@
+package client
+
+import (
+	"github.com/godyy/ggs/internal/base/consts"
+	"github.com/godyy/ggs/internal/base/crypto"
+	"github.com/godyy/ggs/internal/base/net"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+)
+
+var (
+	// maxPacketLen 数据包最大长度.
+	maxPacketLen = uint32(128 * 1024)
+
+	// maxUnfragmentedLen 无需分片的数据包明文最大长度.
+	maxUnfragmentedLen int
+
+	// packetReadWriter 数据包读写器.
+	packetReadWriter *net.PacketReadWriterWithCryptor
+)
+
+func init() {
+	initPacketReadWriter()
+}
+
+func initPacketReadWriter() {
+	tmpKey := make([]byte, 16)
+	tmpCrypto, _ := crypto.CreateAESCrypto(tmpKey)
+	minLen := uint32(tmpCrypto.EncryptedLen(codecc2s.HeadLen))
+	packetReadWriter = net.NewPacketReadWriterWithCryptor(minLen, maxPacketLen, consts.ReadWriteTimeout)
+
+	// 扣除加密开销与压缩标志字节.
+	cryptoOverhead := tmpCrypto.EncryptedLen(codecc2s.HeadLen) - codecc2s.HeadLen
+	maxUnfragmentedLen = int(maxPacketLen) - cryptoOverhead - 1
+}
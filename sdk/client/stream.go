@@ -0,0 +1,185 @@
+package client
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/godyy/ggs/internal/base/consts"
+	icrypto "github.com/godyy/ggs/internal/base/crypto"
+	inet "github.com/godyy/ggs/internal/base/net"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/fragment"
+	"github.com/godyy/ggs/internal/infra/actor/protocol/registry/c2s"
+	"github.com/godyy/ggs/pkg/compress"
+	codecc2s "github.com/godyy/ggskit/base/codec/c2s"
+	"github.com/godyy/ggskit/base/crypto/aes"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// Msg 消息包
+type Msg struct {
+	Pt  int8
+	Seq uint32
+	Msg proto.Message
+}
+
+// Handler Stream 事件处理器.
+type Handler interface {
+	// OnStreamMsg 处理消息.
+	OnStreamMsg(Msg)
+
+	// OnStreamClose 处理关闭事件.
+	OnStreamClose(error)
+}
+
+// Stream 代理发送网络请求和接收网络消息.
+type Stream struct {
+	conn        net.Conn
+	cryptor     aes.Cryptor
+	codec       *compress.Codec       // 压缩编解码器, 未协商压缩时为 nil.
+	fragId      uint32                // 分片消息编号.
+	reassembler *fragment.Reassembler // 分片重组器.
+	handler     Handler
+	sendMtx     sync.Mutex // 串行化发送, 保证分片数据包连续写出.
+	closed      int32
+}
+
+// NewStream 创建Stream. compression 为密钥交换时协商的压缩算法.
+func NewStream(conn net.Conn, sessionKey []byte, compression compress.Algorithm, handler Handler) (*Stream, error) {
+	s := &Stream{
+		conn:        conn,
+		codec:       compress.NewCodec(compression, compress.DefaultThreshold, int(maxPacketLen)),
+		reassembler: fragment.NewReassembler(fragment.DefaultMaxMessageLen, fragment.DefaultMaxPartials),
+		handler:     handler,
+	}
+
+	if sessionKey != nil {
+		cryptor, err := icrypto.CreateAESCrypto(sessionKey)
+		if err != nil {
+			return nil, err
+		}
+		s.cryptor = cryptor
+	}
+
+	go s.readLoop()
+	return s, nil
+}
+
+// SendReq 发送请求包
+func (s *Stream) SendReq(seq uint32, msg proto.Message) error {
+	p, err := codecc2s.EncodeReqPacket(c2s.Registry, seq, msg)
+	if err != nil {
+		return err
+	}
+	return s.Send(p)
+}
+
+// Send 发送数据包, 超过长度上限时拆分为分片. 可并发调用.
+func (s *Stream) Send(p []byte) error {
+	s.sendMtx.Lock()
+	defer s.sendMtx.Unlock()
+
+	if len(p) <= maxUnfragmentedLen {
+		return s.sendFrame(p)
+	}
+
+	fps, err := fragment.Split(p, atomic.AddUint32(&s.fragId, 1), fragment.DefaultChunkSize)
+	if err != nil {
+		return err
+	}
+	for _, fp := range fps {
+		if err := s.sendFrame(fp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendFrame 发送单个数据包.
+func (s *Stream) sendFrame(p []byte) error {
+	if s.codec != nil {
+		var err error
+		if p, err = s.codec.Encode(p); err != nil {
+			return err
+		}
+	}
+
+	if s.cryptor == nil {
+		return inet.WritePacket(s.conn, p, consts.ReadWriteTimeout)
+	} else {
+		return packetReadWriter.EncryptAndWritePacket(s.conn, p, s.cryptor)
+	}
+}
+
+// readLoop 读取循环
+func (s *Stream) readLoop() {
+	for {
+		p, err := s.readPacket()
+		if err != nil {
+			s.close(err)
+			return
+		}
+
+		m := Msg{
+			Pt:  codecc2s.HeadGetPt(p),
+			Seq: codecc2s.HeadGetSeq(p),
+		}
+		respMsg, err := codecc2s.DecodeMessage(c2s.Registry, p)
+		if err != nil {
+			s.close(pkgerrors.WithMessagef(err, "decode pt:%v seq:%v", m.Pt, m.Seq))
+			return
+		}
+		m.Msg = respMsg
+
+		s.handler.OnStreamMsg(m)
+	}
+}
+
+// readPacket 读取数据包, 分片消息重组完成后返回原数据包.
+func (s *Stream) readPacket() ([]byte, error) {
+	for {
+		p, err := s.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		if !fragment.IsFragment(p) {
+			return p, nil
+		}
+		if p, err = s.reassembler.Add(p); err != nil || p != nil {
+			return p, err
+		}
+	}
+}
+
+// readFrame 读取单个数据包.
+func (s *Stream) readFrame() ([]byte, error) {
+	var (
+		p   []byte
+		err error
+	)
+	if s.cryptor == nil {
+		p, err = inet.ReadPacket(s.conn, consts.ReadWriteTimeout)
+	} else {
+		p, err = packetReadWriter.ReadAndDecryptPacket(s.conn, s.cryptor)
+	}
+	if err != nil || s.codec == nil {
+		return p, err
+	}
+	return s.codec.Decode(p)
+}
+
+// Close 关闭Stream
+func (s *Stream) Close() {
+	s.close(nil)
+}
+
+func (s *Stream) close(err error) {
+	if !atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
+		return
+	}
+	s.conn.Close()
+	if err != nil {
+		s.handler.OnStreamClose(err)
+	}
+}